@@ -0,0 +1,45 @@
+// chairlift-system-helper is a privileged helper binary for root operations
+// that have no helper of their own, such as rolling back the system image.
+// It is invoked via pkexec from the main chairlift application. Which
+// fixed programs each subcommand runs is decided in internal/systemhelper.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/frostyard/chairlift/internal/systemhelper"
+)
+
+func main() {
+	steps, err := systemhelper.Plan(os.Args[1:])
+	if err != nil {
+		fatal(err.Error())
+	}
+
+	for _, step := range steps {
+		cmd := exec.Command(step.Path, step.Args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			// Pass the program's exit status through, except pkexec's own
+			// 126 and 127, so the caller can still tell those apart
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code := exitErr.ExitCode()
+				if code == 126 || code == 127 || code < 0 {
+					code = 1
+				}
+				os.Exit(code)
+			}
+			fatal(fmt.Sprintf("failed to run %s: %v", step.Path, err))
+		}
+	}
+}
+
+func fatal(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -1,6 +1,6 @@
 // chairlift-updex-helper is a privileged helper binary for updex write operations.
 // It is invoked via pkexec from the main chairlift application to perform
-// operations that require root access (enable/disable features, update).
+// operations that require root access (enable/disable/remove features, update).
 package main
 
 import (
@@ -40,6 +40,12 @@ func main() {
 		}
 		result, err := client.DisableFeature(ctx, os.Args[2], updexhelper.DisableOptions(dryRun))
 		outputJSON(result, err)
+	case "remove-feature":
+		if len(os.Args) < 3 {
+			fatal("usage: chairlift-updex-helper remove-feature <name> [--dry-run]")
+		}
+		result, err := client.DisableFeature(ctx, os.Args[2], updexhelper.RemoveOptions(dryRun))
+		outputJSON(result, err)
 	case "update":
 		results, err := client.UpdateFeatures(ctx, updexhelper.UpdateOptions(dryRun))
 		outputJSON(results, err)
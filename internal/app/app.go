@@ -7,9 +7,14 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/frostyard/chairlift/internal/audit"
 	"github.com/frostyard/chairlift/internal/bootc"
+	"github.com/frostyard/chairlift/internal/containers"
+	"github.com/frostyard/chairlift/internal/devtools"
 	"github.com/frostyard/chairlift/internal/flatpak"
 	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/power"
+	"github.com/frostyard/chairlift/internal/systemd"
 	"github.com/frostyard/chairlift/internal/updex"
 	"github.com/frostyard/chairlift/internal/views"
 	"github.com/frostyard/chairlift/internal/window"
@@ -75,6 +80,13 @@ func New() *Application {
 
 	app := (*Application)(appRegistry.Get(obj.GoPointer()))
 
+	// Record package changes from here on; dry-run skips are never recorded
+	if path, err := audit.DefaultPath(); err == nil {
+		audit.Init(path)
+	} else {
+		log.Printf("Audit log disabled: %v", err)
+	}
+
 	// Check for --dry-run flag before GTK processes args
 	for _, arg := range os.Args[1:] {
 		if arg == "--dry-run" || arg == "-d" {
@@ -84,12 +96,17 @@ func New() *Application {
 			homebrew.SetDryRun(true)
 			bootc.SetDryRun(true)
 			updex.SetDryRun(true)
+			devtools.SetDryRun(true)
+			power.SetDryRun(true)
+			systemd.SetDryRun(true)
+			containers.SetDryRun(true)
 			views.SetDryRun(true)
 			break
 		}
 	}
 
-	// Set up keyboard shortcuts
+	// Set up application actions and keyboard shortcuts
+	app.setupActions()
 	app.setupKeyboardShortcuts()
 
 	// Register command line options
@@ -117,6 +134,23 @@ func (a *Application) onActivate() {
 	log.Printf("app: window presented in %s (since activate)", time.Since(activateStart))
 }
 
+// setupActions sets up application actions. Desktop notifications can
+// only activate app actions, so show-updates is here rather than on the
+// window.
+func (a *Application) setupActions() {
+	showUpdatesAction := gio.NewSimpleAction("show-updates", nil)
+	showUpdatesCb := func(action gio.SimpleAction, param uintptr) {
+		if a.window == nil {
+			a.Activate()
+		}
+		if a.window != nil {
+			a.window.ShowPage("updates")
+		}
+	}
+	showUpdatesAction.ConnectActivate(&showUpdatesCb)
+	a.AddAction(showUpdatesAction)
+}
+
 // setupKeyboardShortcuts sets up application-wide keyboard shortcuts
 func (a *Application) setupKeyboardShortcuts() {
 	a.SetAccelsForAction("app.quit", []string{"<Primary>q"})
@@ -127,6 +161,10 @@ func (a *Application) setupKeyboardShortcuts() {
 	a.SetAccelsForAction("win.navigate-system", []string{"<Alt>4"})
 	a.SetAccelsForAction("win.navigate-features", []string{"<Alt>5"})
 	a.SetAccelsForAction("win.navigate-help", []string{"<Alt>6"})
+	a.SetAccelsForAction("win.navigate-devtools", []string{"<Alt>7"})
+	a.SetAccelsForAction("win.navigate-services", []string{"<Alt>8"})
+	a.SetAccelsForAction("win.navigate-logs", []string{"<Alt>9"})
+	a.SetAccelsForAction("win.navigate-storage", []string{"<Alt>0"})
 }
 
 // registerOptions registers command line options
@@ -0,0 +1,166 @@
+// Package audit keeps an append-only log of every package-changing command
+// ChairLift runs — installs, removals, upgrades, system image stages and
+// feature changes — so an administrator can see what was changed on a
+// machine, by whom and when.
+//
+// The wrappers call Record after each state-changing command they actually
+// run; dry-run skips are not recorded. Recording is off until Init is
+// called at startup, which keeps the wrappers' tests from writing to the
+// real log.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the log's name inside its directory
+const FileName = "audit.log"
+
+// Results recorded in Entry.Result
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+// Entry is one recorded command
+type Entry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Tool    string    `json:"tool"`
+	Command string    `json:"command"`
+	Result  string    `json:"result"`
+	Error   string    `json:"error,omitempty"`
+}
+
+var (
+	mu       sync.Mutex
+	logPath  string // Empty until Init; Record is a no-op then
+	userName string
+)
+
+// DefaultPath returns $XDG_STATE_HOME/chairlift/audit.log, falling back to
+// ~/.local/state when XDG_STATE_HOME is unset
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "chairlift", FileName), nil
+}
+
+// Init turns recording on, appending to the log at path
+func Init(path string) {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	logPath = path
+	userName = name
+	log.Printf("Audit log: %s", path)
+}
+
+// Path returns the log file recording goes to, or "" before Init
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return logPath
+}
+
+// Record appends one entry for a command that ran, failed if err is
+// non-nil. A log that cannot be written is reported to the process log
+// only; it never fails the command it describes.
+func Record(tool string, args []string, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if logPath == "" {
+		return
+	}
+
+	entry := Entry{
+		Time:    time.Now(),
+		User:    userName,
+		Tool:    tool,
+		Command: strings.Join(args, " "),
+		Result:  ResultSucceeded,
+	}
+	if err != nil {
+		entry.Result = ResultFailed
+		entry.Error = err.Error()
+	}
+
+	if writeErr := appendEntry(logPath, entry); writeErr != nil {
+		log.Printf("audit: could not record %s %s: %v", tool, entry.Command, writeErr)
+	}
+}
+
+// appendEntry writes entry as one JSON line at the end of the file at path
+func appendEntry(path string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns up to limit entries from the log at path, newest first. A
+// missing log has no entries; lines that do not parse are skipped.
+func Read(path string, limit int) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	slices.Reverse(entries)
+	return entries, nil
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// initTemp points recording at a fresh file for one test and turns it off
+// again afterwards
+func initTemp(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state", FileName)
+	Init(path)
+	t.Cleanup(func() {
+		mu.Lock()
+		logPath = ""
+		mu.Unlock()
+	})
+	return path
+}
+
+func TestRecordBeforeInitIsNoOp(t *testing.T) {
+	if Path() != "" {
+		t.Fatalf("Path() = %q before Init, want empty", Path())
+	}
+	// Must not panic or write anywhere
+	Record("brew", []string{"install", "wget"}, nil)
+}
+
+func TestRecordAndRead(t *testing.T) {
+	path := initTemp(t)
+
+	Record("brew", []string{"install", "wget"}, nil)
+	Record("flatpak", []string{"uninstall", "-y", "org.example.App"}, errors.New("not installed"))
+	Record("brew", []string{"upgrade", "jq"}, nil)
+
+	entries, err := Read(path, 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Read returned %d entries, want 3", len(entries))
+	}
+
+	// Newest first
+	if entries[0].Command != "upgrade jq" || entries[2].Command != "install wget" {
+		t.Errorf("entries out of order: %+v", entries)
+	}
+	failed := entries[1]
+	if failed.Tool != "flatpak" || failed.Result != ResultFailed || failed.Error != "not installed" {
+		t.Errorf("failed entry = %+v", failed)
+	}
+	if entries[0].Result != ResultSucceeded || entries[0].Error != "" {
+		t.Errorf("succeeded entry = %+v", entries[0])
+	}
+	for _, entry := range entries {
+		if entry.User == "" || entry.Time.IsZero() {
+			t.Errorf("entry missing user or time: %+v", entry)
+		}
+	}
+}
+
+func TestReadLimitKeepsNewest(t *testing.T) {
+	path := initTemp(t)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		Record("brew", []string{"install", name}, nil)
+	}
+
+	entries, err := Read(path, 2)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Command != "install d" || entries[1].Command != "install c" {
+		t.Errorf("Read(limit 2) = %+v, want install d, install c", entries)
+	}
+}
+
+func TestReadSkipsMalformedLines(t *testing.T) {
+	path := initTemp(t)
+	Record("brew", []string{"install", "wget"}, nil)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("not json\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	Record("brew", []string{"upgrade"}, nil)
+
+	entries, err := Read(path, 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Read returned %d entries, want 2 (malformed line skipped)", len(entries))
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), "missing.log"), 0)
+	if err != nil || entries != nil {
+		t.Errorf("Read(missing) = %+v, %v, want nil, nil", entries, err)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/tmp/state/chairlift/audit.log"; path != want {
+		t.Errorf("DefaultPath() = %q, want %q", path, want)
+	}
+}
@@ -0,0 +1,51 @@
+// Package batch runs one package-manager operation over many items with a
+// bounded number of concurrent workers. The wrappers build their batch
+// APIs (flatpak.UpdateBatch, homebrew.UpgradeBatch) on top of it.
+package batch
+
+import "sync"
+
+// Run calls fn for every item using at most workers goroutines and returns
+// one error per item, in input order. done, if non-nil, is called from the
+// worker goroutine as each item finishes.
+func Run[T any](items []T, workers int, fn func(T) error, done func(T, error)) []error {
+	errs := make([]error, len(items))
+	if workers < 1 {
+		workers = 1
+	}
+	workers = min(workers, len(items))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(items[i])
+				if done != nil {
+					done(items[i], errs[i])
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return errs
+}
+
+// Failed returns how many of errs are non-nil
+func Failed(errs []error) int {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
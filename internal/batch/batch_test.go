@@ -0,0 +1,78 @@
+package batch
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRunKeepsOrder checks that errors line up with their items no matter
+// which worker finished first.
+func TestRunKeepsOrder(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4, 5}
+	errs := Run(items, 3, func(i int) error {
+		time.Sleep(time.Duration(len(items)-i) * time.Millisecond)
+		if i%2 == 1 {
+			return errors.New("odd")
+		}
+		return nil
+	}, nil)
+
+	if len(errs) != len(items) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(items))
+	}
+	for i, err := range errs {
+		if (err != nil) != (i%2 == 1) {
+			t.Errorf("errs[%d] = %v, want error only for odd items", i, err)
+		}
+	}
+	if got := Failed(errs); got != 3 {
+		t.Errorf("Failed() = %d, want 3", got)
+	}
+}
+
+// TestRunBoundsWorkers checks that no more than workers calls are in
+// flight at once and that done sees every item.
+func TestRunBoundsWorkers(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+
+	items := []string{"a", "b", "c", "d", "e", "f", "g"}
+	Run(items, 2, func(string) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}, func(item string, _ error) {
+		mu.Lock()
+		seen[item] = true
+		mu.Unlock()
+	})
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+	if len(seen) != len(items) {
+		t.Errorf("done saw %d items, want %d", len(seen), len(items))
+	}
+}
+
+// TestRunEmpty checks that an empty batch returns without calling fn.
+func TestRunEmpty(t *testing.T) {
+	errs := Run(nil, 4, func(string) error {
+		t.Error("fn called for an empty batch")
+		return nil
+	}, nil)
+	if len(errs) != 0 {
+		t.Errorf("got %d errors, want 0", len(errs))
+	}
+}
@@ -61,6 +61,9 @@ func (e *NotFoundError) Error() string {
 type ImageReference struct {
 	Image     string `json:"image"`
 	Transport string `json:"transport"`
+	// Signature is bootc's verification setting: "containerPolicy",
+	// "insecure" or {"ostreeRemote": "<remote>"}. Absent when unset.
+	Signature json.RawMessage `json:"signature,omitempty"`
 }
 
 // ImageStatus describes a deployed image.
@@ -77,6 +80,14 @@ type Deployment struct {
 	Pinned bool         `json:"pinned"`
 }
 
+// Reference returns the deployment's image reference, or nil if unknown.
+func (d *Deployment) Reference() *ImageReference {
+	if d == nil || d.Image == nil {
+		return nil
+	}
+	return &d.Image.Image
+}
+
 // ImageRef returns the deployment's image reference, or "" if unknown.
 func (d *Deployment) ImageRef() string {
 	if d == nil || d.Image == nil {
@@ -109,6 +120,37 @@ func (d *Deployment) Digest() string {
 	return d.Image.ImageDigest
 }
 
+// Change is one image field that differs between two deployments.
+type Change struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Diff lists what differs between the from and to deployments' images:
+// version, image reference, build time and digest, in that order. A field
+// that is equal, or unknown on both sides, is left out.
+func Diff(from, to *Deployment) []Change {
+	fields := []struct {
+		name string
+		get  func(*Deployment) string
+	}{
+		{"Version", (*Deployment).Version},
+		{"Image", (*Deployment).ImageRef},
+		{"Built", (*Deployment).Timestamp},
+		{"Digest", (*Deployment).Digest},
+	}
+
+	var changes []Change
+	for _, f := range fields {
+		before, after := f.get(from), f.get(to)
+		if before != after {
+			changes = append(changes, Change{Field: f.name, From: before, To: after})
+		}
+	}
+	return changes
+}
+
 // SpecInfo is the host spec section of bootc status.
 type SpecInfo struct {
 	Image *ImageReference `json:"image"`
@@ -119,6 +161,8 @@ type StatusInfo struct {
 	Booted   *Deployment `json:"booted"`
 	Staged   *Deployment `json:"staged"`
 	Rollback *Deployment `json:"rollback"`
+	// RollbackQueued is true when the next boot starts the rollback
+	RollbackQueued bool `json:"rollbackQueued"`
 }
 
 // Status is the parsed output of `bootc status --format json`.
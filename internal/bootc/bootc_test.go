@@ -1,6 +1,9 @@
 package bootc
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // nonBootcJSON is real output captured from `bootc status --format json`
 // on a non-bootc (non-bootc-booted) host.
@@ -100,3 +103,36 @@ func TestParseStatusMalformed(t *testing.T) {
 		t.Error("parseStatus(garbage) = nil error, want error")
 	}
 }
+
+func TestDiffBootedToStaged(t *testing.T) {
+	s, err := parseStatus([]byte(bootedStagedJSON))
+	if err != nil {
+		t.Fatalf("parseStatus: %v", err)
+	}
+
+	changes := Diff(s.Status.Booted, s.Status.Staged)
+	var fields []string
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	// Same image reference on both sides, so it is left out
+	if got, want := strings.Join(fields, ","), "Version,Built,Digest"; got != want {
+		t.Fatalf("Diff fields = %s, want %s", got, want)
+	}
+	if changes[0].From != "20260701.0" || changes[0].To != "20260706.0" {
+		t.Errorf("Version change = %+v", changes[0])
+	}
+}
+
+func TestDiffSameOrUnknown(t *testing.T) {
+	s, err := parseStatus([]byte(bootedStagedJSON))
+	if err != nil {
+		t.Fatalf("parseStatus: %v", err)
+	}
+	if changes := Diff(s.Status.Booted, s.Status.Booted); len(changes) != 0 {
+		t.Errorf("Diff(booted, booted) = %+v, want none", changes)
+	}
+	if changes := Diff(nil, nil); len(changes) != 0 {
+		t.Errorf("Diff(nil, nil) = %+v, want none", changes)
+	}
+}
@@ -0,0 +1,123 @@
+package bootc
+
+import "strings"
+
+// Failure classifies why a privileged bootc operation failed.
+type Failure int
+
+const (
+	FailureUnknown Failure = iota
+	// FailureAuthDismissed: the polkit prompt was closed (pkexec exit 126)
+	FailureAuthDismissed
+	// FailureNotAuthorized: polkit refused the user (pkexec exit 127)
+	FailureNotAuthorized
+	FailureRegistry
+	FailureDiskFull
+	FailureSignature
+)
+
+// pkexec's own exit codes; anything else is the program's
+const (
+	pkexecDismissed     = 126
+	pkexecNotAuthorized = 127
+)
+
+// failurePatterns maps lowercase output fragments to the failure they
+// signal, checked in order: a full disk or a rejected signature often
+// surfaces alongside a generic pull error.
+var failurePatterns = []struct {
+	failure  Failure
+	fragment string
+}{
+	{FailureDiskFull, "no space left on device"},
+	{FailureDiskFull, "disk quota exceeded"},
+	{FailureSignature, "signature"},
+	{FailureSignature, "policy requirements"},
+	{FailureRegistry, "no such host"},
+	{FailureRegistry, "temporary failure in name resolution"},
+	{FailureRegistry, "connection refused"},
+	{FailureRegistry, "network is unreachable"},
+	{FailureRegistry, "i/o timeout"},
+	{FailureRegistry, "tls handshake timeout"},
+	{FailureRegistry, "pinging container registry"},
+}
+
+// classifyLine returns the failure an output line points at, or
+// FailureUnknown.
+func classifyLine(line string) Failure {
+	lower := strings.ToLower(line)
+	for _, p := range failurePatterns {
+		if strings.Contains(lower, p.fragment) {
+			return p.failure
+		}
+	}
+	return FailureUnknown
+}
+
+// classifyExit returns the failure for a command that exited with code,
+// given the failure its output pointed at. pkexec's exit codes only mean
+// an authentication failure when name is pkexec.
+func classifyExit(name string, code int, seen Failure) Failure {
+	if name == pkexecCommand {
+		switch code {
+		case pkexecDismissed:
+			return FailureAuthDismissed
+		case pkexecNotAuthorized:
+			return FailureNotAuthorized
+		}
+	}
+	return seen
+}
+
+// OperationError is returned when a privileged bootc operation fails for
+// a recognised reason. Message keeps the raw command output for logs and
+// progress rows; UserMessage is meant for toasts.
+type OperationError struct {
+	Failure Failure
+	Message string
+}
+
+func (e *OperationError) Error() string {
+	return e.Message
+}
+
+// Summary says what went wrong in a few words.
+func (e *OperationError) Summary() string {
+	switch e.Failure {
+	case FailureAuthDismissed:
+		return "authentication was cancelled"
+	case FailureNotAuthorized:
+		return "not authorized to change the system image"
+	case FailureRegistry:
+		return "could not reach the image registry"
+	case FailureDiskFull:
+		return "not enough disk space"
+	case FailureSignature:
+		return "the image signature could not be verified"
+	}
+	return e.Message
+}
+
+// Hint says what the user can do about it, or "" when there is nothing
+// to suggest.
+func (e *OperationError) Hint() string {
+	switch e.Failure {
+	case FailureNotAuthorized:
+		return "ask an administrator to update the system"
+	case FailureRegistry:
+		return "check your network connection and try again"
+	case FailureDiskFull:
+		return "free some space and try again"
+	case FailureSignature:
+		return "nothing was changed; check that the image source is trusted"
+	}
+	return ""
+}
+
+// UserMessage joins Summary and Hint for a toast.
+func (e *OperationError) UserMessage() string {
+	if hint := e.Hint(); hint != "" {
+		return e.Summary() + " — " + hint
+	}
+	return e.Summary()
+}
@@ -0,0 +1,88 @@
+package bootc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClassifyLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Failure
+	}{
+		{"Error: writing blob: write /var/tmp/x: no space left on device", FailureDiskFull},
+		{"Source image rejected: A signature was required, but no signature exists", FailureSignature},
+		{"pinging container registry ghcr.io: Get \"https://ghcr.io/v2/\": dial tcp: lookup ghcr.io: no such host", FailureRegistry},
+		{"dial tcp 140.82.112.33:443: i/o timeout", FailureRegistry},
+		{"Copying blob sha256:abc done", FailureUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyLine(tt.line); got != tt.want {
+			t.Errorf("classifyLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyExit(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		seen Failure
+		want Failure
+	}{
+		{pkexecCommand, 126, FailureUnknown, FailureAuthDismissed},
+		{pkexecCommand, 127, FailureUnknown, FailureNotAuthorized},
+		{pkexecCommand, 1, FailureDiskFull, FailureDiskFull},
+		// 126/127 are only pkexec's when pkexec ran
+		{"bootc", 126, FailureUnknown, FailureUnknown},
+		{pkexecCommand, 1, FailureUnknown, FailureUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyExit(tt.name, tt.code, tt.seen); got != tt.want {
+			t.Errorf("classifyExit(%q, %d, %v) = %v, want %v", tt.name, tt.code, tt.seen, got, tt.want)
+		}
+	}
+}
+
+func TestOperationErrorUserMessage(t *testing.T) {
+	err := &OperationError{Failure: FailureDiskFull, Message: "update staging failed (exit 1): no space left on device"}
+	if want := "not enough disk space — free some space and try again"; err.UserMessage() != want {
+		t.Errorf("UserMessage() = %q, want %q", err.UserMessage(), want)
+	}
+	if err.Error() != err.Message {
+		t.Errorf("Error() = %q, want the raw message", err.Error())
+	}
+
+	dismissed := &OperationError{Failure: FailureAuthDismissed}
+	if want := "authentication was cancelled"; dismissed.UserMessage() != want {
+		t.Errorf("UserMessage() = %q, want %q", dismissed.UserMessage(), want)
+	}
+}
+
+func TestRunStreamingClassifiesFailure(t *testing.T) {
+	script := writeScript(t, `echo "Error: write /sysroot/ostree: no space left on device" >&2
+echo "Cleaning up" >&2
+exit 1`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan ProgressEvent)
+	done := make(chan error, 1)
+	go func() { done <- runStreaming(ctx, ch, "update staging", script) }()
+
+	collectEvents(ch)
+	err := <-done
+	var opErr *OperationError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("runStreaming error = %v (%T), want *OperationError", err, err)
+	}
+	if opErr.Failure != FailureDiskFull {
+		t.Errorf("Failure = %v, want FailureDiskFull", opErr.Failure)
+	}
+	if want := "update staging failed (exit 1): Cleaning up"; opErr.Error() != want {
+		t.Errorf("error = %q, want %q", opErr, want)
+	}
+}
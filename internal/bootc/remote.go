@@ -0,0 +1,124 @@
+package bootc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// RemoteTimeout bounds a registry lookup
+const RemoteTimeout = 30 * time.Second
+
+// RemoteImage is the newest image behind a reference in its registry
+type RemoteImage struct {
+	// Digest is the manifest digest for this machine's architecture, as
+	// bootc status reports it for a deployed image
+	Digest string
+	// DownloadSize is the compressed size of the image's layers: what a
+	// pull fetches at most, less any layers already on disk
+	DownloadSize int64
+}
+
+// inspectRaw fetches the raw manifest for a registry reference. Tests
+// replace it.
+var inspectRaw = func(ctx context.Context, ref string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "skopeo", "inspect", "--raw", "docker://"+ref)
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &Error{Message: "registry lookup timed out"}
+		}
+		var execErr *exec.Error
+		if errors.As(err, &execErr) && execErr.Err == exec.ErrNotFound {
+			return nil, &NotFoundError{Message: "skopeo not found"}
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, &Error{Message: fmt.Sprintf("skopeo inspect failed: %s", strings.TrimSpace(string(exitErr.Stderr)))}
+		}
+		return nil, &Error{Message: err.Error()}
+	}
+	return output, nil
+}
+
+// manifest is the part of an OCI/Docker image manifest or index that
+// sizes and platforms are read from
+type manifest struct {
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// CheckRemote looks up image in its registry without pulling it. An image
+// index is resolved to this machine's architecture. Needs skopeo, which
+// bootc systems ship for signature and registry work.
+func CheckRemote(parent context.Context, image string) (*RemoteImage, error) {
+	ctx, cancel := context.WithTimeout(parent, RemoteTimeout)
+	defer cancel()
+
+	raw, err := inspectRaw(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("failed to parse manifest for %s: %v", image, err)}
+	}
+
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(raw))
+	if len(m.Manifests) > 0 {
+		digest = ""
+		for _, entry := range m.Manifests {
+			if entry.Platform.OS == "linux" && entry.Platform.Architecture == runtime.GOARCH {
+				digest = entry.Digest
+				break
+			}
+		}
+		if digest == "" {
+			return nil, &Error{Message: fmt.Sprintf("%s has no image for linux/%s", image, runtime.GOARCH)}
+		}
+		if raw, err = inspectRaw(ctx, repository(image)+"@"+digest); err != nil {
+			return nil, err
+		}
+		m = manifest{}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, &Error{Message: fmt.Sprintf("failed to parse manifest for %s: %v", image, err)}
+		}
+	}
+
+	remote := &RemoteImage{Digest: digest}
+	for _, layer := range m.Layers {
+		remote.DownloadSize += layer.Size
+	}
+	return remote, nil
+}
+
+// repository strips the tag or digest from an image reference
+func repository(image string) string {
+	if name, _, found := strings.Cut(image, "@"); found {
+		return name
+	}
+	// A colon after the last slash starts a tag; one before it is a port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
+
+// Newer reports whether the remote image differs from the deployment
+func (r *RemoteImage) Newer(d *Deployment) bool {
+	return r != nil && d.Digest() != "" && r.Digest != d.Digest()
+}
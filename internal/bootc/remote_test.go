@@ -0,0 +1,84 @@
+package bootc
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+const singleManifest = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"size":1200},"layers":[{"size":1000000000},{"size":400000000}]}`
+
+// fakeRegistry serves raw manifests by reference for one test
+func fakeRegistry(t *testing.T, manifests map[string]string) {
+	t.Helper()
+	orig := inspectRaw
+	inspectRaw = func(_ context.Context, ref string) ([]byte, error) {
+		raw, ok := manifests[ref]
+		if !ok {
+			return nil, &Error{Message: "manifest unknown: " + ref}
+		}
+		return []byte(raw), nil
+	}
+	t.Cleanup(func() { inspectRaw = orig })
+}
+
+func TestCheckRemoteSingleManifest(t *testing.T) {
+	fakeRegistry(t, map[string]string{"ghcr.io/frostyard/snow:stable": singleManifest})
+
+	remote, err := CheckRemote(context.Background(), "ghcr.io/frostyard/snow:stable")
+	if err != nil {
+		t.Fatalf("CheckRemote: %v", err)
+	}
+	if want := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(singleManifest))); remote.Digest != want {
+		t.Errorf("Digest = %s, want %s", remote.Digest, want)
+	}
+	if remote.DownloadSize != 1400000000 {
+		t.Errorf("DownloadSize = %d, want 1400000000 (layers only)", remote.DownloadSize)
+	}
+}
+
+func TestCheckRemoteResolvesIndex(t *testing.T) {
+	index := fmt.Sprintf(`{"schemaVersion":2,"manifests":[
+		{"digest":"sha256:other","platform":{"architecture":"s390x","os":"linux"}},
+		{"digest":"sha256:mine","platform":{"architecture":%q,"os":"linux"}}]}`, runtime.GOARCH)
+	fakeRegistry(t, map[string]string{
+		"localhost:5000/os:42":          index,
+		"localhost:5000/os@sha256:mine": singleManifest,
+	})
+
+	remote, err := CheckRemote(context.Background(), "localhost:5000/os:42")
+	if err != nil {
+		t.Fatalf("CheckRemote: %v", err)
+	}
+	if remote.Digest != "sha256:mine" || remote.DownloadSize != 1400000000 {
+		t.Errorf("CheckRemote(index) = %+v", remote)
+	}
+}
+
+func TestRepository(t *testing.T) {
+	for in, want := range map[string]string{
+		"ghcr.io/frostyard/snow:stable": "ghcr.io/frostyard/snow",
+		"localhost:5000/os":             "localhost:5000/os",
+		"localhost:5000/os:42":          "localhost:5000/os",
+		"quay.io/os@sha256:abc":         "quay.io/os",
+	} {
+		if got := repository(in); got != want {
+			t.Errorf("repository(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRemoteNewer(t *testing.T) {
+	booted := &Deployment{Image: &ImageStatus{ImageDigest: "sha256:a"}}
+	if (&RemoteImage{Digest: "sha256:a"}).Newer(booted) {
+		t.Error("same digest reported as newer")
+	}
+	if !(&RemoteImage{Digest: "sha256:b"}).Newer(booted) {
+		t.Error("different digest not reported as newer")
+	}
+	if (&RemoteImage{Digest: "sha256:b"}).Newer(nil) {
+		t.Error("unknown booted digest reported as newer")
+	}
+}
@@ -0,0 +1,149 @@
+package bootc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// PolicyPath is the containers signature policy that system pulls follow
+const PolicyPath = "/etc/containers/policy.json"
+
+// Policy is the part of containers-policy.json(5) that decides whether an
+// image must be signed to be pulled
+type Policy struct {
+	Default    []PolicyRequirement                       `json:"default"`
+	Transports map[string]map[string][]PolicyRequirement `json:"transports"`
+}
+
+// PolicyRequirement is one requirement for a scope
+type PolicyRequirement struct {
+	Type     string   `json:"type"`
+	KeyPath  string   `json:"keyPath,omitempty"`
+	KeyPaths []string `json:"keyPaths,omitempty"`
+	Fulcio   *struct {
+		SubjectEmail string `json:"subjectEmail"`
+	} `json:"fulcio,omitempty"`
+}
+
+// LoadPolicy reads the signature policy at path. A missing file is an
+// empty policy, which requires no signatures.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Policy{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("failed to parse %s: %v", path, err)}
+	}
+	return &policy, nil
+}
+
+// requirements returns the requirements for image pulled from a registry:
+// the most specific matching scope under the docker transport, else that
+// transport's default, else the global default
+func (p *Policy) requirements(image string) []PolicyRequirement {
+	scopes := p.Transports["docker"]
+	best := ""
+	var reqs []PolicyRequirement
+	for scope, scopeReqs := range scopes {
+		if scope == "" || len(scope) <= len(best) {
+			continue
+		}
+		if image == scope || strings.HasPrefix(image, scope+"/") ||
+			strings.HasPrefix(image, scope+":") || strings.HasPrefix(image, scope+"@") {
+			best, reqs = scope, scopeReqs
+		}
+	}
+	if best != "" {
+		return reqs
+	}
+	if transportDefault, ok := scopes[""]; ok {
+		return transportDefault
+	}
+	return p.Default
+}
+
+// Provenance describes where an image comes from and whether it must be
+// signed before it is pulled
+type Provenance struct {
+	Registry string
+	// Signed is true when a signature is required, so an unsigned or
+	// tampered image would have been refused
+	Signed bool
+	// Signer names the key, identity or ostree remote that signatures are
+	// checked against, or "" when unknown
+	Signer string
+}
+
+// Registry returns the registry host of an image name, defaulting to
+// docker.io like the container tools do
+func Registry(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		return host
+	}
+	return "docker.io"
+}
+
+// Provenance reports the reference's registry and whether pulling it
+// requires a valid signature. bootc's own setting is used when it names
+// an ostree remote or turns verification off; otherwise the image follows
+// policy, which may be nil when it could not be read.
+func (r *ImageReference) Provenance(policy *Policy) Provenance {
+	if r == nil {
+		return Provenance{}
+	}
+	prov := Provenance{Registry: Registry(r.Image)}
+
+	var signature any
+	if len(r.Signature) > 0 {
+		_ = json.Unmarshal(r.Signature, &signature)
+	}
+	switch sig := signature.(type) {
+	case string:
+		if sig == "insecure" {
+			return prov
+		}
+	case map[string]any:
+		if remote, ok := sig["ostreeRemote"].(string); ok {
+			prov.Signed = true
+			prov.Signer = "ostree remote " + remote
+			return prov
+		}
+	}
+
+	if policy == nil {
+		return prov
+	}
+	for _, req := range policy.requirements(r.Image) {
+		switch req.Type {
+		case "signedBy", "sigstoreSigned":
+			prov.Signed = true
+			if prov.Signer == "" {
+				prov.Signer = req.signer()
+			}
+		}
+	}
+	return prov
+}
+
+// signer names what a signing requirement checks against
+func (req PolicyRequirement) signer() string {
+	switch {
+	case req.Fulcio != nil && req.Fulcio.SubjectEmail != "":
+		return req.Fulcio.SubjectEmail
+	case req.KeyPath != "":
+		return req.KeyPath
+	case len(req.KeyPaths) > 0:
+		return strings.Join(req.KeyPaths, ", ")
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package bootc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// snowPolicyJSON requires sigstore signatures for frostyard images and
+// accepts anything else, like a typical bootc host policy
+const snowPolicyJSON = `{
+  "default": [{"type": "insecureAcceptAnything"}],
+  "transports": {
+    "docker": {
+      "ghcr.io/frostyard": [
+        {"type": "sigstoreSigned", "keyPath": "/etc/pki/containers/frostyard.pub", "signedIdentity": {"type": "matchRepository"}}
+      ],
+      "ghcr.io/frostyard/snow-dev": [{"type": "insecureAcceptAnything"}],
+      "quay.io": [
+        {"type": "sigstoreSigned", "fulcio": {"subjectEmail": "builder@example.com", "oidcIssuer": "https://example.com"}}
+      ]
+    }
+  }
+}`
+
+func writePolicy(t *testing.T, content string) *Policy {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	return policy
+}
+
+func TestProvenanceFromPolicy(t *testing.T) {
+	policy := writePolicy(t, snowPolicyJSON)
+
+	tests := []struct {
+		image string
+		want  Provenance
+	}{
+		{"ghcr.io/frostyard/snow:stable", Provenance{Registry: "ghcr.io", Signed: true, Signer: "/etc/pki/containers/frostyard.pub"}},
+		// The longer scope wins
+		{"ghcr.io/frostyard/snow-dev:latest", Provenance{Registry: "ghcr.io"}},
+		// A shared prefix that is not a path component does not match
+		{"ghcr.io/frostyardx/snow:stable", Provenance{Registry: "ghcr.io"}},
+		{"quay.io/example/os:42", Provenance{Registry: "quay.io", Signed: true, Signer: "builder@example.com"}},
+		{"fedora/fedora-bootc", Provenance{Registry: "docker.io"}},
+	}
+	for _, tt := range tests {
+		ref := &ImageReference{Image: tt.image, Transport: "registry", Signature: json.RawMessage(`"containerPolicy"`)}
+		if got := ref.Provenance(policy); got != tt.want {
+			t.Errorf("Provenance(%s) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestProvenanceFromBootcSignature(t *testing.T) {
+	policy := writePolicy(t, snowPolicyJSON)
+
+	insecure := &ImageReference{Image: "ghcr.io/frostyard/snow:stable", Signature: json.RawMessage(`"insecure"`)}
+	if got := insecure.Provenance(policy); got.Signed {
+		t.Errorf("insecure Provenance = %+v, want unsigned despite the policy", got)
+	}
+
+	ostree := &ImageReference{Image: "localhost/os:latest", Signature: json.RawMessage(`{"ostreeRemote": "snow"}`)}
+	want := Provenance{Registry: "localhost", Signed: true, Signer: "ostree remote snow"}
+	if got := ostree.Provenance(nil); got != want {
+		t.Errorf("ostreeRemote Provenance = %+v, want %+v", got, want)
+	}
+
+	var missing *ImageReference
+	if got := missing.Provenance(policy); got != (Provenance{}) {
+		t.Errorf("nil reference Provenance = %+v, want zero", got)
+	}
+}
+
+func TestLoadPolicyMissingAndMalformed(t *testing.T) {
+	policy, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadPolicy(missing): %v", err)
+	}
+	ref := &ImageReference{Image: "ghcr.io/frostyard/snow:stable"}
+	if got := ref.Provenance(policy); got.Signed {
+		t.Errorf("Provenance with no policy file = %+v, want unsigned", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(path); err == nil {
+		t.Error("LoadPolicy(malformed) = nil error, want error")
+	}
+}
+
+func TestDeploymentReference(t *testing.T) {
+	s, err := parseStatus([]byte(bootedStagedJSON))
+	if err != nil {
+		t.Fatalf("parseStatus: %v", err)
+	}
+	if got := s.Status.Booted.Reference().Image; got != "ghcr.io/frostyard/snow:stable" {
+		t.Errorf("Reference().Image = %q", got)
+	}
+	var d *Deployment
+	if d.Reference() != nil {
+		t.Error("nil Deployment Reference() must be nil")
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/systemhelper"
 )
 
 // StageScriptPath is the snow-shipped workaround script that pulls the OS
@@ -49,13 +52,94 @@ func StageUpdate(ctx context.Context, progressCh chan<- ProgressEvent) error {
 		close(progressCh)
 		return nil
 	}
-	return runStageStreaming(ctx, progressCh, pkexecCommand, StageScriptPath)
+	err := runStageStreaming(ctx, progressCh, pkexecCommand, StageScriptPath)
+	audit.Record("bootc", []string{"stage", StageScriptPath}, err)
+	return err
+}
+
+// Rollback makes the rollback deployment the default for the next boot by
+// running `bootc rollback` through the system helper via pkexec, under its
+// own polkit action (org.frostyard.ChairLift.bootc.rollback). Output lines
+// stream to progressCh as EventMessage events; EventComplete is sent on
+// success. progressCh is closed when done. The booted system is untouched
+// until a restart.
+func Rollback(ctx context.Context, progressCh chan<- ProgressEvent) error {
+	if dryRun {
+		log.Printf("[DRY-RUN] would execute: pkexec %s %s", systemhelper.Path, systemhelper.BootcRollback)
+		progressCh <- ProgressEvent{Type: EventMessage, Message: "[DRY-RUN] would run bootc rollback"}
+		progressCh <- ProgressEvent{Type: EventComplete, Message: "Dry run complete"}
+		close(progressCh)
+		return nil
+	}
+	err := runStreaming(ctx, progressCh, "rollback", pkexecCommand, systemhelper.Path, systemhelper.BootcRollback)
+	audit.Record("bootc", []string{"rollback"}, err)
+	return err
+}
+
+// ValidateImageRef rejects an image reference that cannot be passed to
+// bootc switch: empty, containing whitespace, starting with "-", which
+// would be read as a flag, or with characters no image reference has. The
+// system helper checks the same again before running anything.
+func ValidateImageRef(image string) error {
+	switch {
+	case image == "":
+		return &Error{Message: "enter an image reference"}
+	case strings.ContainsAny(image, " \t\n"):
+		return &Error{Message: "an image reference cannot contain spaces"}
+	case strings.HasPrefix(image, "-"):
+		return &Error{Message: "an image reference cannot start with \"-\""}
+	case !systemhelper.ValidImageRef(image):
+		return &Error{Message: "not a valid image reference"}
+	}
+	return nil
+}
+
+// switchCommand returns the pkexec command line that switches to image
+// through the system helper. transport is the booted image's; any other
+// than containers-storage means a registry pull by bootc itself.
+func switchCommand(image, transport string) []string {
+	if transport != systemhelper.TransportContainersStorage {
+		transport = systemhelper.TransportRegistry
+	}
+	return []string{systemhelper.Path, systemhelper.BootcSwitch, transport, image}
+}
+
+// Switch makes image the system image, staging it for the next boot like
+// an update, through the system helper under its own polkit action
+// (org.frostyard.ChairLift.bootc.switch). transport is the booted image's
+// transport from bootc status, which decides how the new image is pulled.
+// The current deployment stays as the rollback. Output streams to
+// progressCh like StageUpdate.
+func Switch(ctx context.Context, image, transport string, progressCh chan<- ProgressEvent) error {
+	if err := ValidateImageRef(image); err != nil {
+		close(progressCh)
+		return err
+	}
+	args := switchCommand(image, transport)
+	if dryRun {
+		log.Printf("[DRY-RUN] would execute: pkexec %s", strings.Join(args, " "))
+		progressCh <- ProgressEvent{Type: EventMessage, Message: "[DRY-RUN] would switch to " + image}
+		progressCh <- ProgressEvent{Type: EventComplete, Message: "Dry run complete"}
+		close(progressCh)
+		return nil
+	}
+	err := runStreaming(ctx, progressCh, "image switch", pkexecCommand, args...)
+	audit.Record("bootc", []string{"switch", image}, err)
+	return err
 }
 
 // runStageStreaming runs a command, streaming stdout+stderr lines to
 // progressCh. It closes progressCh before returning. Separated from
 // StageUpdate so tests can run a local fake script without pkexec.
 func runStageStreaming(ctx context.Context, progressCh chan<- ProgressEvent, name string, args ...string) error {
+	return runStreaming(ctx, progressCh, "update staging", name, args...)
+}
+
+// runStreaming runs a privileged bootc operation, streaming stdout+stderr
+// lines to progressCh and naming the operation in its errors. A failure
+// with a recognised cause is returned as an *OperationError. It closes
+// progressCh before returning.
+func runStreaming(ctx context.Context, progressCh chan<- ProgressEvent, operation string, name string, args ...string) error {
 	defer close(progressCh)
 
 	cmd := exec.CommandContext(ctx, name, args...)
@@ -75,6 +159,7 @@ func runStageStreaming(ctx context.Context, progressCh chan<- ProgressEvent, nam
 	}
 
 	var lastLine string
+	seen := FailureUnknown
 	scanner := bufio.NewScanner(stdout)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -82,6 +167,9 @@ func runStageStreaming(ctx context.Context, progressCh chan<- ProgressEvent, nam
 			continue
 		}
 		lastLine = line
+		if seen == FailureUnknown {
+			seen = classifyLine(line)
+		}
 		select {
 		case progressCh <- ProgressEvent{Type: EventMessage, Message: line}:
 		case <-ctx.Done():
@@ -93,18 +181,21 @@ func runStageStreaming(ctx context.Context, progressCh chan<- ProgressEvent, nam
 
 	if err := cmd.Wait(); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return &Error{Message: "Update staging timed out"}
+			return &Error{Message: fmt.Sprintf("%s%s timed out", strings.ToUpper(operation[:1]), operation[1:])}
 		}
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			msg := fmt.Sprintf("update staging failed (exit %d)", exitErr.ExitCode())
+			msg := fmt.Sprintf("%s failed (exit %d)", operation, exitErr.ExitCode())
 			if lastLine != "" {
 				msg += ": " + lastLine
 			}
+			if failure := classifyExit(name, exitErr.ExitCode(), seen); failure != FailureUnknown {
+				return &OperationError{Failure: failure, Message: msg}
+			}
 			return &Error{Message: msg}
 		}
 		return &Error{Message: err.Error()}
 	}
 
-	progressCh <- ProgressEvent{Type: EventComplete, Message: "Staging complete"}
+	progressCh <- ProgressEvent{Type: EventComplete, Message: fmt.Sprintf("%s%s complete", strings.ToUpper(operation[:1]), operation[1:])}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -105,3 +106,97 @@ func TestStageUpdateDryRun(t *testing.T) {
 		t.Errorf("dry-run should emit mock events ending in EventComplete; got %+v", events)
 	}
 }
+
+func TestRunStreamingNamesOperation(t *testing.T) {
+	script := writeScript(t, `echo "No rollback deployment" >&2
+exit 2`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan ProgressEvent)
+	done := make(chan error, 1)
+	go func() { done <- runStreaming(ctx, ch, "rollback", script) }()
+
+	collectEvents(ch)
+	err := <-done
+	if err == nil {
+		t.Fatal("runStreaming = nil error, want failure")
+	}
+	if want := "rollback failed (exit 2): No rollback deployment"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestRollbackDryRun(t *testing.T) {
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	ch := make(chan ProgressEvent)
+	done := make(chan error, 1)
+	go func() { done <- Rollback(context.Background(), ch) }()
+
+	events := collectEvents(ch)
+	if err := <-done; err != nil {
+		t.Fatalf("dry-run Rollback: %v", err)
+	}
+	if len(events) == 0 || events[len(events)-1].Type != EventComplete {
+		t.Errorf("dry-run should emit mock events ending in EventComplete; got %+v", events)
+	}
+}
+
+func TestValidateImageRef(t *testing.T) {
+	for _, image := range []string{"ghcr.io/frostyard/snow:testing", "quay.io/fedora/fedora-bootc:42"} {
+		if err := ValidateImageRef(image); err != nil {
+			t.Errorf("ValidateImageRef(%q) = %v, want nil", image, err)
+		}
+	}
+	for _, image := range []string{"", "ghcr.io/frostyard/snow testing", "--help", "-x", "ghcr.io/x;id", "$(id)"} {
+		if err := ValidateImageRef(image); err == nil {
+			t.Errorf("ValidateImageRef(%q) = nil, want error", image)
+		}
+	}
+}
+
+func TestSwitchCommand(t *testing.T) {
+	got := strings.Join(switchCommand("quay.io/fedora/fedora-bootc:42", "registry"), " ")
+	if want := "/usr/bin/chairlift-system-helper bootc-switch registry quay.io/fedora/fedora-bootc:42"; got != want {
+		t.Errorf("registry switchCommand = %q, want %q", got, want)
+	}
+
+	got = strings.Join(switchCommand("ghcr.io/frostyard/snow:testing", "containers-storage"), " ")
+	if want := "/usr/bin/chairlift-system-helper bootc-switch containers-storage ghcr.io/frostyard/snow:testing"; got != want {
+		t.Errorf("containers-storage switchCommand = %q, want %q", got, want)
+	}
+
+	// Any other transport bootc status reports pulls from a registry
+	got = strings.Join(switchCommand("quay.io/fedora/fedora-bootc:42", "oci"), " ")
+	if !strings.Contains(got, "bootc-switch registry ") {
+		t.Errorf("oci switchCommand = %q, want the registry transport", got)
+	}
+}
+
+func TestSwitchDryRunAndInvalid(t *testing.T) {
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	ch := make(chan ProgressEvent)
+	done := make(chan error, 1)
+	go func() { done <- Switch(context.Background(), "ghcr.io/frostyard/snow:testing", "registry", ch) }()
+	events := collectEvents(ch)
+	if err := <-done; err != nil {
+		t.Fatalf("dry-run Switch: %v", err)
+	}
+	if len(events) == 0 || events[len(events)-1].Type != EventComplete {
+		t.Errorf("dry-run should end in EventComplete; got %+v", events)
+	}
+
+	ch = make(chan ProgressEvent)
+	go func() { done <- Switch(context.Background(), "--bad", "registry", ch) }()
+	if events := collectEvents(ch); len(events) != 0 {
+		t.Errorf("invalid image emitted events: %+v", events)
+	}
+	if err := <-done; err == nil {
+		t.Error("Switch(invalid) = nil error, want error")
+	}
+}
@@ -0,0 +1,64 @@
+package bootc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusPollInterval is how often WatchStatus re-reads bootc status
+const StatusPollInterval = time.Minute
+
+// statusFunc reads the status for WatchStatus. Tests replace it.
+var statusFunc = GetStatus
+
+// WatchStatus re-reads bootc status every interval until ctx is done,
+// calling onChange with the new status whenever its deployments differ
+// from the previous read — an update staged from a terminal or by a
+// timer, for example. The first read only sets the baseline, and failed
+// reads are skipped. It blocks, so run it in its own goroutine.
+func WatchStatus(ctx context.Context, interval time.Duration, onChange func(*Status)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	first := true
+	var last string
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, interval)
+		status, err := statusFunc(readCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			current := status.fingerprint()
+			if !first && current != last {
+				onChange(status)
+			}
+			first, last = false, current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fingerprint identifies the booted, staged and rollback deployments by
+// digest and pin state, and whether a rollback is queued, so two reads
+// can be compared
+func (s *Status) fingerprint() string {
+	var parts []string
+	for _, d := range []*Deployment{s.Status.Booted, s.Status.Staged, s.Status.Rollback} {
+		if d == nil {
+			parts = append(parts, "-")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s/%v", d.Digest(), d.Pinned))
+	}
+	parts = append(parts, fmt.Sprintf("queued=%v", s.Status.RollbackQueued))
+	return strings.Join(parts, " ")
+}
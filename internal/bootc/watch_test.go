@@ -0,0 +1,82 @@
+package bootc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStatuses makes statusFunc return each of reads in turn, repeating
+// the last one, for the duration of the test
+func fakeStatuses(t *testing.T, reads ...string) {
+	t.Helper()
+	var mu sync.Mutex
+	next := 0
+	orig := statusFunc
+	statusFunc = func(context.Context) (*Status, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		read := reads[min(next, len(reads)-1)]
+		next++
+		if read == "error" {
+			return nil, errors.New("bootc status failed")
+		}
+		return parseStatus([]byte(read))
+	}
+	t.Cleanup(func() { statusFunc = orig })
+}
+
+func TestWatchStatusReportsChangesOnly(t *testing.T) {
+	// Baseline, a failed read, the same again, then an update is staged
+	fakeStatuses(t, nonBootcJSON, "error", nonBootcJSON, bootedStagedJSON)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan *Status, 10)
+	done := make(chan struct{})
+	go func() {
+		WatchStatus(ctx, 5*time.Millisecond, func(s *Status) { changes <- s })
+		close(done)
+	}()
+
+	select {
+	case s := <-changes:
+		if s.Status.Staged == nil {
+			t.Errorf("onChange status has no staged deployment: %+v", s.Status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called for the staged update")
+	}
+
+	// The status stays the same from here, so no further calls
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	<-done
+	if len(changes) != 0 {
+		t.Errorf("onChange called %d more times for an unchanged status", len(changes))
+	}
+}
+
+func TestFingerprintDiffersWhenStaged(t *testing.T) {
+	plain, err := parseStatus([]byte(nonBootcJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	staged, err := parseStatus([]byte(bootedStagedJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.fingerprint() == staged.fingerprint() {
+		t.Error("fingerprints equal for different deployments")
+	}
+	if staged.fingerprint() != staged.fingerprint() {
+		t.Error("fingerprint not stable")
+	}
+
+	before := staged.fingerprint()
+	staged.Status.RollbackQueued = true
+	if staged.fingerprint() == before {
+		t.Error("fingerprint unchanged after a rollback was queued")
+	}
+}
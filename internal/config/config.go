@@ -16,6 +16,11 @@ type Config struct {
 	ApplicationsPage PageConfig `yaml:"applications_page"`
 	MaintenancePage  PageConfig `yaml:"maintenance_page"`
 	FeaturesPage     PageConfig `yaml:"features_page"`
+	DevtoolsPage     PageConfig `yaml:"devtools_page"`
+	ContainersPage   PageConfig `yaml:"containers_page"`
+	ServicesPage     PageConfig `yaml:"services_page"`
+	LogsPage         PageConfig `yaml:"logs_page"`
+	StoragePage      PageConfig `yaml:"storage_page"`
 	HelpPage         PageConfig `yaml:"help_page"`
 }
 
@@ -31,6 +36,7 @@ type GroupConfig struct {
 	Issues       string         `yaml:"issues,omitempty"`
 	Chat         string         `yaml:"chat,omitempty"`
 	BundlesPaths []string       `yaml:"bundles_paths,omitempty"`
+	Interval     string         `yaml:"interval,omitempty"`
 }
 
 // ActionConfig represents a configurable action
@@ -51,6 +57,11 @@ type rawConfig struct {
 	ApplicationsPage rawPageConfig `yaml:"applications_page"`
 	MaintenancePage  rawPageConfig `yaml:"maintenance_page"`
 	FeaturesPage     rawPageConfig `yaml:"features_page"`
+	DevtoolsPage     rawPageConfig `yaml:"devtools_page"`
+	ContainersPage   rawPageConfig `yaml:"containers_page"`
+	ServicesPage     rawPageConfig `yaml:"services_page"`
+	LogsPage         rawPageConfig `yaml:"logs_page"`
+	StoragePage      rawPageConfig `yaml:"storage_page"`
 	HelpPage         rawPageConfig `yaml:"help_page"`
 }
 
@@ -70,6 +81,7 @@ type rawGroupConfig struct {
 	Issues       *string         `yaml:"issues"`
 	Chat         *string         `yaml:"chat"`
 	BundlesPaths *[]string       `yaml:"bundles_paths"`
+	Interval     *string         `yaml:"interval"`
 }
 
 // configPaths are the locations to search for the config file
@@ -133,6 +145,11 @@ func mergeConfig(def *Config, raw *rawConfig) *Config {
 		ApplicationsPage: mergePage(def.ApplicationsPage, raw.ApplicationsPage),
 		MaintenancePage:  mergePage(def.MaintenancePage, raw.MaintenancePage),
 		FeaturesPage:     mergePage(def.FeaturesPage, raw.FeaturesPage),
+		DevtoolsPage:     mergePage(def.DevtoolsPage, raw.DevtoolsPage),
+		ContainersPage:   mergePage(def.ContainersPage, raw.ContainersPage),
+		ServicesPage:     mergePage(def.ServicesPage, raw.ServicesPage),
+		LogsPage:         mergePage(def.LogsPage, raw.LogsPage),
+		StoragePage:      mergePage(def.StoragePage, raw.StoragePage),
 		HelpPage:         mergePage(def.HelpPage, raw.HelpPage),
 	}
 }
@@ -190,6 +207,9 @@ func mergeGroup(def GroupConfig, raw rawGroupConfig) GroupConfig {
 	if raw.BundlesPaths != nil {
 		result.BundlesPaths = *raw.BundlesPaths
 	}
+	if raw.Interval != nil {
+		result.Interval = *raw.Interval
+	}
 
 	return result
 }
@@ -206,20 +226,27 @@ func defaultConfig() *Config {
 			},
 		},
 		UpdatesPage: PageConfig{
+			"update_all_group":      GroupConfig{Enabled: true},
 			"bootc_updates_group":   GroupConfig{Enabled: true},
 			"flatpak_updates_group": GroupConfig{Enabled: true},
 			"brew_updates_group":    GroupConfig{Enabled: true},
 			"brew_trust_group":      GroupConfig{Enabled: true},
+			"update_check_group": GroupConfig{
+				Enabled:  true,
+				Interval: "6h",
+			},
 		},
 		ApplicationsPage: PageConfig{
 			"applications_installed_group": GroupConfig{
 				Enabled: true,
 				AppID:   "io.github.kolunmi.Bazaar",
 			},
-			"flatpak_user_group":   GroupConfig{Enabled: true},
-			"flatpak_system_group": GroupConfig{Enabled: true},
-			"brew_group":           GroupConfig{Enabled: true},
-			"brew_search_group":    GroupConfig{Enabled: true},
+			"flatpak_user_group":    GroupConfig{Enabled: true},
+			"flatpak_system_group":  GroupConfig{Enabled: true},
+			"flatpak_remotes_group": GroupConfig{Enabled: true},
+			"brew_group":            GroupConfig{Enabled: true},
+			"brew_search_group":     GroupConfig{Enabled: true},
+			"flatpak_search_group":  GroupConfig{Enabled: true},
 			"brew_bundles_group": GroupConfig{
 				Enabled:      true,
 				BundlesPaths: []string{"/usr/share/snow/bundles"},
@@ -238,11 +265,33 @@ func defaultConfig() *Config {
 			},
 			"maintenance_brew_group":         GroupConfig{Enabled: true},
 			"maintenance_flatpak_group":      GroupConfig{Enabled: true},
+			"maintenance_manifest_group":     GroupConfig{Enabled: true},
+			"maintenance_audit_group":        GroupConfig{Enabled: true},
 			"maintenance_optimization_group": GroupConfig{Enabled: true},
 		},
 		FeaturesPage: PageConfig{
 			"features_group": GroupConfig{Enabled: true},
 		},
+		DevtoolsPage: PageConfig{
+			"pipx_group":  GroupConfig{Enabled: true},
+			"cargo_group": GroupConfig{Enabled: true},
+			"npm_group":   GroupConfig{Enabled: true},
+		},
+		ContainersPage: PageConfig{
+			"podman_group": GroupConfig{Enabled: true},
+			"docker_group": GroupConfig{Enabled: true},
+		},
+		ServicesPage: PageConfig{
+			"system_services_group": GroupConfig{Enabled: true},
+			"user_services_group":   GroupConfig{Enabled: true},
+		},
+		LogsPage: PageConfig{
+			"journal_group": GroupConfig{Enabled: true},
+		},
+		StoragePage: PageConfig{
+			"filesystems_group": GroupConfig{Enabled: true},
+			"consumers_group":   GroupConfig{Enabled: true},
+		},
 		HelpPage: PageConfig{
 			"help_resources_group": GroupConfig{
 				Enabled: true,
@@ -268,6 +317,16 @@ func (c *Config) IsGroupEnabled(pageName, groupName string) bool {
 		page = c.MaintenancePage
 	case "features_page":
 		page = c.FeaturesPage
+	case "devtools_page":
+		page = c.DevtoolsPage
+	case "containers_page":
+		page = c.ContainersPage
+	case "services_page":
+		page = c.ServicesPage
+	case "logs_page":
+		page = c.LogsPage
+	case "storage_page":
+		page = c.StoragePage
 	case "help_page":
 		page = c.HelpPage
 	default:
@@ -295,6 +354,16 @@ func (c *Config) GetGroupConfig(pageName, groupName string) *GroupConfig {
 		page = c.MaintenancePage
 	case "features_page":
 		page = c.FeaturesPage
+	case "devtools_page":
+		page = c.DevtoolsPage
+	case "containers_page":
+		page = c.ContainersPage
+	case "services_page":
+		page = c.ServicesPage
+	case "logs_page":
+		page = c.LogsPage
+	case "storage_page":
+		page = c.StoragePage
 	case "help_page":
 		page = c.HelpPage
 	default:
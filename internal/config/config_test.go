@@ -21,6 +21,11 @@ var pageNames = []string{
 	"applications_page",
 	"maintenance_page",
 	"features_page",
+	"devtools_page",
+	"containers_page",
+	"services_page",
+	"logs_page",
+	"storage_page",
 	"help_page",
 }
 
@@ -33,6 +38,11 @@ func pagesOf(cfg *Config) map[string]PageConfig {
 		"applications_page": cfg.ApplicationsPage,
 		"maintenance_page":  cfg.MaintenancePage,
 		"features_page":     cfg.FeaturesPage,
+		"devtools_page":     cfg.DevtoolsPage,
+		"containers_page":   cfg.ContainersPage,
+		"services_page":     cfg.ServicesPage,
+		"logs_page":         cfg.LogsPage,
+		"storage_page":      cfg.StoragePage,
 		"help_page":         cfg.HelpPage,
 	}
 }
@@ -144,8 +154,8 @@ func TestMaintenanceCleanupGroupDefaultConsistentAcrossAbsentAndOmitted(t *testi
 }
 
 // defaultBearingGroups lists every group in defaultConfig() that defines a
-// non-Enabled default field (AppID, Website/Issues/Chat, Actions, or
-// BundlesPaths). Enabled-only-overlay coverage loops over all of them, not
+// non-Enabled default field (AppID, Website/Issues/Chat, Actions,
+// BundlesPaths, or Interval). Enabled-only-overlay coverage loops over all of them, not
 // just one, per the repo's regression-tests-must-cover-every-collection-entry
 // skill.
 var defaultBearingGroups = []struct {
@@ -157,6 +167,7 @@ var defaultBearingGroups = []struct {
 	{"help_page", "help_resources_group"},
 	{"maintenance_page", "maintenance_cleanup_group"},
 	{"applications_page", "brew_bundles_group"},
+	{"updates_page", "update_check_group"},
 }
 
 // TestEnabledOnlyOverlayPreservesOtherDefaultFields feeds a partial file
@@ -243,6 +254,37 @@ func TestOmittedEnabledInheritsDocumentedDefault(t *testing.T) {
 	})
 }
 
+// TestSearchGroupsAreIndependent asserts that turning off Homebrew search
+// leaves Flatpak search on, since each has its own key.
+func TestSearchGroupsAreIndependent(t *testing.T) {
+	path := writeConfigFile(t, "applications_page:\n  brew_search_group:\n    enabled: false\n")
+	cfg, err := loadFromPath(path)
+	if err != nil {
+		t.Fatalf("loadFromPath(%q): %v", path, err)
+	}
+	if cfg.IsGroupEnabled("applications_page", "brew_search_group") {
+		t.Error("brew_search_group: got enabled, want disabled")
+	}
+	if !cfg.IsGroupEnabled("applications_page", "flatpak_search_group") {
+		t.Error("flatpak_search_group: got disabled, want enabled (default)")
+	}
+}
+
+// TestIntervalOverlay asserts a configured update check interval replaces
+// the default and leaves `enabled` at its default.
+func TestIntervalOverlay(t *testing.T) {
+	path := writeConfigFile(t, "updates_page:\n  update_check_group:\n    interval: 90m\n")
+	cfg, err := loadFromPath(path)
+	if err != nil {
+		t.Fatalf("loadFromPath(%q): %v", path, err)
+	}
+
+	got := cfg.UpdatesPage["update_check_group"]
+	if got.Interval != "90m" || !got.Enabled {
+		t.Errorf("update_check_group = %+v, want interval 90m and enabled", got)
+	}
+}
+
 // TestExplicitEmptySliceOverlayClearsDefault asserts that an explicit empty
 // slice (`actions: []`, `bundles_paths: []`) overlays to an empty (len==0)
 // slice rather than restoring the default slice.
@@ -392,17 +434,19 @@ func repoRoot() string {
 }
 
 // TestUpdatesPageDefaultGroupSetIsExact asserts that defaultConfig()'s
-// updates_page group set is exactly the four groups the Updates page view
+// updates_page group set is exactly the six groups the Updates page view
 // still builds. This is an exact-set equality check (length plus every
 // expected key present), not a single named-key absence lookup, so it fails
 // loudly whether a formerly-shipped, now-removed group is silently
 // re-added under its old name or under any new one.
 func TestUpdatesPageDefaultGroupSetIsExact(t *testing.T) {
 	want := map[string]bool{
+		"update_all_group":      true,
 		"bootc_updates_group":   true,
 		"flatpak_updates_group": true,
 		"brew_updates_group":    true,
 		"brew_trust_group":      true,
+		"update_check_group":    true,
 	}
 
 	got := defaultConfig().UpdatesPage
@@ -0,0 +1,344 @@
+// Package containers lists and manages Podman and Docker containers and
+// images through each engine's API socket. Podman serves the Docker
+// compatible API, so one client covers both. The user's rootless Podman
+// socket needs no privileges; the Docker socket needs membership in the
+// docker group.
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+)
+
+// apiVersion is the Docker API version requested; Podman 4 and later and
+// Docker 20.10 and later serve it
+const apiVersion = "v1.41"
+
+// StopTimeout bounds stopping a container; the engine itself kills it
+// after 10 seconds
+const StopTimeout = 2 * time.Minute
+
+var (
+	dryRun  = false
+	timeout = 30 * time.Second
+)
+
+// SetDryRun enables/disables dry-run mode
+func SetDryRun(mode bool) {
+	dryRun = mode
+	log.Printf("Containers dry-run mode: %v", mode)
+}
+
+// IsDryRun returns whether dry-run mode is enabled
+func IsDryRun() bool {
+	return dryRun
+}
+
+// Error represents a failed API request, with the engine's own message
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Engine is a container engine reachable through an API socket
+type Engine struct {
+	ID     string // "podman" or "docker", also the config group prefix
+	Title  string
+	Socket string
+	// Installed reports the engine's command is on $PATH, even if its
+	// socket is not listening
+	Installed bool
+}
+
+// Running reports whether the engine's socket exists
+func (e *Engine) Running() bool {
+	info, err := os.Stat(e.Socket)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
+
+// PodmanSocketUnit is the user unit that starts Podman's API socket
+const PodmanSocketUnit = "podman.socket"
+
+// podmanSocket returns the rootless Podman API socket path
+func podmanSocket() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return filepath.Join(runtimeDir, "podman", "podman.sock")
+}
+
+// dockerSocket returns the Docker API socket from DOCKER_HOST, when that
+// is a unix socket, else the default
+func dockerSocket() string {
+	if host, ok := strings.CutPrefix(os.Getenv("DOCKER_HOST"), "unix://"); ok && host != "" {
+		return host
+	}
+	return "/var/run/docker.sock"
+}
+
+// Engines returns Podman and Docker, whether or not they are installed
+func Engines() []*Engine {
+	_, podmanErr := exec.LookPath("podman")
+	_, dockerErr := exec.LookPath("docker")
+	return []*Engine{
+		{ID: "podman", Title: "Podman", Socket: podmanSocket(), Installed: podmanErr == nil},
+		{ID: "docker", Title: "Docker", Socket: dockerSocket(), Installed: dockerErr == nil},
+	}
+}
+
+// Container is one container, running or not
+type Container struct {
+	ID      string
+	Name    string
+	Image   string
+	State   string // running, exited, created, paused, ...
+	Status  string // e.g. "Up 2 hours", "Exited (0) 3 days ago"
+	Created time.Time
+	Size    int64 // Writable layer, in bytes
+}
+
+// Running reports whether the container is running
+func (c Container) Running() bool {
+	return c.State == "running"
+}
+
+// Image is one local image
+type Image struct {
+	ID         string
+	Tags       []string
+	Size       int64
+	Created    time.Time
+	Containers int // Containers using the image; -1 when unknown
+}
+
+// Name returns the image's first tag, or its short ID when untagged
+func (i Image) Name() string {
+	for _, tag := range i.Tags {
+		if tag != "<none>:<none>" {
+			return tag
+		}
+	}
+	return shortID(i.ID)
+}
+
+// shortID trims an image or container ID for display
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// apiContainer is one entry of GET /containers/json
+type apiContainer struct {
+	ID      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	State   string   `json:"State"`
+	Status  string   `json:"Status"`
+	Created int64    `json:"Created"`
+	SizeRw  int64    `json:"SizeRw"`
+}
+
+// apiImage is one entry of GET /images/json
+type apiImage struct {
+	ID         string   `json:"Id"`
+	RepoTags   []string `json:"RepoTags"`
+	Size       int64    `json:"Size"`
+	Created    int64    `json:"Created"`
+	Containers int      `json:"Containers"`
+}
+
+// apiPruneReport is the body of POST /images/prune
+type apiPruneReport struct {
+	SpaceReclaimed int64 `json:"SpaceReclaimed"`
+}
+
+// parseContainers converts the API's containers, dropping the leading
+// slash the API puts on names
+func parseContainers(listed []apiContainer) []Container {
+	containers := make([]Container, 0, len(listed))
+	for _, c := range listed {
+		name := shortID(c.ID)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		containers = append(containers, Container{
+			ID:      c.ID,
+			Name:    name,
+			Image:   c.Image,
+			State:   c.State,
+			Status:  c.Status,
+			Created: time.Unix(c.Created, 0),
+			Size:    c.SizeRw,
+		})
+	}
+	return containers
+}
+
+// parseImages converts the API's images
+func parseImages(listed []apiImage) []Image {
+	images := make([]Image, 0, len(listed))
+	for _, i := range listed {
+		images = append(images, Image{
+			ID:         i.ID,
+			Tags:       i.RepoTags,
+			Size:       i.Size,
+			Created:    time.Unix(i.Created, 0),
+			Containers: i.Containers,
+		})
+	}
+	return images
+}
+
+// client returns an HTTP client that dials the engine's socket. Each
+// request gets its own connection, so nothing idles after it.
+func (e *Engine) client() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", e.Socket)
+			},
+		},
+	}
+}
+
+// request sends one API request and decodes a JSON response into out,
+// when out is not nil. A 304 (already started or stopped) is success.
+func (e *Engine) request(parent context.Context, limit time.Duration, method, path string, query url.Values, out any) error {
+	ctx, cancel := context.WithTimeout(parent, limit)
+	defer cancel()
+
+	u := "http://d/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return &Error{Message: err.Error()}
+	}
+
+	resp, err := e.client().Do(req)
+	if err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &Error{Message: fmt.Sprintf("%s %s timed out", e.Title, path)}
+		}
+		return &Error{Message: fmt.Sprintf("cannot reach %s: %v", e.Title, err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("reading %s response: %v", e.Title, err)}
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return &Error{Message: fmt.Sprintf("%s: %s", e.Title, apiErr.Message)}
+		}
+		return &Error{Message: fmt.Sprintf("%s: %s", e.Title, strings.TrimSpace(string(body)))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &Error{Message: fmt.Sprintf("failed to parse %s response: %v", e.Title, err)}
+	}
+	return nil
+}
+
+// ListContainers returns every container, running or not, with the size
+// of its writable layer
+func (e *Engine) ListContainers(ctx context.Context) ([]Container, error) {
+	var listed []apiContainer
+	query := url.Values{"all": {"true"}, "size": {"true"}}
+	if err := e.request(ctx, timeout, http.MethodGet, "/containers/json", query, &listed); err != nil {
+		return nil, err
+	}
+	return parseContainers(listed), nil
+}
+
+// ListImages returns the local images
+func (e *Engine) ListImages(ctx context.Context) ([]Image, error) {
+	var listed []apiImage
+	if err := e.request(ctx, timeout, http.MethodGet, "/images/json", nil, &listed); err != nil {
+		return nil, err
+	}
+	return parseImages(listed), nil
+}
+
+// change sends a state-changing request, or only logs it under dry-run,
+// and records it in the audit log as the equivalent CLI command
+func (e *Engine) change(ctx context.Context, limit time.Duration, method, path string, query url.Values, out any, cliArgs ...string) error {
+	if dryRun {
+		log.Printf("[DRY-RUN] Would execute: %s %s", e.ID, strings.Join(cliArgs, " "))
+		return nil
+	}
+	err := e.request(ctx, limit, method, path, query, out)
+	audit.Record(e.ID, cliArgs, err)
+	return err
+}
+
+// StartContainer starts a stopped container
+func (e *Engine) StartContainer(ctx context.Context, c Container) error {
+	return e.change(ctx, timeout, http.MethodPost, "/containers/"+url.PathEscape(c.ID)+"/start", nil, nil,
+		"start", c.Name)
+}
+
+// StopContainer stops a running container
+func (e *Engine) StopContainer(ctx context.Context, c Container) error {
+	return e.change(ctx, StopTimeout, http.MethodPost, "/containers/"+url.PathEscape(c.ID)+"/stop", nil, nil,
+		"stop", c.Name)
+}
+
+// RemoveContainer removes a stopped container and its anonymous volumes
+func (e *Engine) RemoveContainer(ctx context.Context, c Container) error {
+	return e.change(ctx, timeout, http.MethodDelete, "/containers/"+url.PathEscape(c.ID), url.Values{"v": {"true"}}, nil,
+		"rm", "--volumes", c.Name)
+}
+
+// RemoveImage removes an image no container uses
+func (e *Engine) RemoveImage(ctx context.Context, i Image) error {
+	return e.change(ctx, timeout, http.MethodDelete, "/images/"+url.PathEscape(i.ID), nil, nil,
+		"rmi", i.Name())
+}
+
+// PruneImages removes every image no container uses, not only dangling
+// ones, and returns how much space was freed
+func (e *Engine) PruneImages(ctx context.Context) (int64, error) {
+	var report apiPruneReport
+	query := url.Values{"filters": {`{"dangling":["false"]}`}}
+	err := e.change(ctx, StopTimeout, http.MethodPost, "/images/prune", query, &report,
+		"image", "prune", "--all", "--force")
+	return report.SpaceReclaimed, err
+}
@@ -0,0 +1,156 @@
+package containers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEngine serves handler on a unix socket and returns an Engine that
+// talks to it
+func fakeEngine(t *testing.T, handler http.HandlerFunc) *Engine {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "engine.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return &Engine{ID: "podman", Title: "Podman", Socket: socket, Installed: true}
+}
+
+func TestParseContainers(t *testing.T) {
+	got := parseContainers([]apiContainer{
+		{ID: "0123456789abcdef", Names: []string{"/web"}, Image: "nginx:latest", State: "running", Status: "Up 2 hours", Created: 1700000000, SizeRw: 4096},
+		{ID: "fedcba9876543210", State: "exited"},
+	})
+	if len(got) != 2 {
+		t.Fatalf("parseContainers() returned %d containers, want 2", len(got))
+	}
+	if got[0].Name != "web" || !got[0].Running() || got[0].Size != 4096 || !got[0].Created.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("parseContainers()[0] = %+v", got[0])
+	}
+	if got[1].Name != "fedcba987654" || got[1].Running() {
+		t.Errorf("parseContainers()[1] = %+v, want short ID as name, not running", got[1])
+	}
+}
+
+func TestImageName(t *testing.T) {
+	tests := []struct {
+		image Image
+		want  string
+	}{
+		{Image{ID: "sha256:0123456789abcdef", Tags: []string{"docker.io/library/alpine:3"}}, "docker.io/library/alpine:3"},
+		{Image{ID: "sha256:0123456789abcdef", Tags: []string{"<none>:<none>"}}, "0123456789ab"},
+		{Image{ID: "sha256:0123456789abcdef"}, "0123456789ab"},
+	}
+	for _, tt := range tests {
+		if got := tt.image.Name(); got != tt.want {
+			t.Errorf("Name() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRunning(t *testing.T) {
+	engine := fakeEngine(t, func(http.ResponseWriter, *http.Request) {})
+	if !engine.Running() {
+		t.Error("Running() = false for a listening socket")
+	}
+	missing := &Engine{Socket: filepath.Join(t.TempDir(), "missing.sock")}
+	if missing.Running() {
+		t.Error("Running() = true for a missing socket")
+	}
+}
+
+func TestDockerSocket(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///run/user/1000/docker.sock")
+	if got := dockerSocket(); got != "/run/user/1000/docker.sock" {
+		t.Errorf("dockerSocket() = %q", got)
+	}
+	t.Setenv("DOCKER_HOST", "tcp://example.com:2376")
+	if got := dockerSocket(); got != "/var/run/docker.sock" {
+		t.Errorf("dockerSocket() with tcp host = %q, want the default", got)
+	}
+}
+
+func TestListContainers(t *testing.T) {
+	engine := fakeEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+apiVersion+"/containers/json" || r.URL.Query().Get("all") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[{"Id":"abc","Names":["/db"],"Image":"postgres:16","State":"exited","Status":"Exited (0) 3 days ago","SizeRw":10}]`))
+	})
+	got, err := engine.ListContainers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "db" || got[0].Image != "postgres:16" {
+		t.Errorf("ListContainers() = %+v", got)
+	}
+}
+
+func TestRequestError(t *testing.T) {
+	engine := fakeEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message":"image is in use by a container"}`))
+	})
+	err := engine.request(context.Background(), timeout, http.MethodDelete, "/images/abc", nil, nil)
+	if err == nil || err.Error() != "Podman: image is in use by a container" {
+		t.Errorf("request() error = %v, want the engine's message", err)
+	}
+}
+
+func TestRequestNotModified(t *testing.T) {
+	engine := fakeEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	if err := engine.request(context.Background(), timeout, http.MethodPost, "/containers/abc/start", nil, nil); err != nil {
+		t.Errorf("request() error = %v, want nil for an already started container", err)
+	}
+}
+
+func TestRequestUnreachable(t *testing.T) {
+	engine := &Engine{Title: "Docker", Socket: filepath.Join(t.TempDir(), "missing.sock")}
+	_, err := engine.ListImages(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot reach Docker") {
+		t.Errorf("ListImages() error = %v, want cannot reach", err)
+	}
+}
+
+func TestPruneImages(t *testing.T) {
+	engine := fakeEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Query().Get("filters") != `{"dangling":["false"]}` {
+			http.Error(w, `{"message":"bad request"}`, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"ImagesDeleted":[{"Deleted":"sha256:abc"}],"SpaceReclaimed":123456}`))
+	})
+	freed, err := engine.PruneImages(context.Background())
+	if err != nil || freed != 123456 {
+		t.Errorf("PruneImages() = %d, %v, want 123456, nil", freed, err)
+	}
+}
+
+func TestDryRunSkipsRequest(t *testing.T) {
+	called := false
+	engine := fakeEngine(t, func(w http.ResponseWriter, r *http.Request) { called = true })
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	if err := engine.RemoveContainer(context.Background(), Container{ID: "abc", Name: "web"}); err != nil {
+		t.Errorf("RemoveContainer() under dry-run = %v, want nil", err)
+	}
+	if freed, err := engine.PruneImages(context.Background()); err != nil || freed != 0 {
+		t.Errorf("PruneImages() under dry-run = %d, %v, want 0, nil", freed, err)
+	}
+	if called {
+		t.Error("dry-run sent a request to the engine")
+	}
+}
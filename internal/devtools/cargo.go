@@ -0,0 +1,87 @@
+package devtools
+
+import (
+	"context"
+	"strings"
+)
+
+func listCargo(ctx context.Context) ([]Tool, error) {
+	output, err := runCommand(ctx, timeout, "cargo", "install", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return parseCargoList(output), nil
+}
+
+// parseCargoList reads cargo install --list. Each crate is an unindented
+// "name vX.Y.Z:" line, followed by its indented binaries; crates installed
+// from a path or git carry the source in parentheses after the version.
+func parseCargoList(output string) []Tool {
+	var tools []Tool
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		line = strings.TrimSuffix(strings.TrimSpace(line), ":")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		tools = append(tools, Tool{
+			Name:     fields[0],
+			Version:  strings.TrimPrefix(fields[1], "v"),
+			registry: len(fields) == 2,
+		})
+	}
+	return tools
+}
+
+// outdatedCargo looks each registry crate up on crates.io. cargo has no
+// outdated command without the cargo-update plugin.
+func outdatedCargo(ctx context.Context, installed []Tool) ([]Tool, error) {
+	var outdated []Tool
+	for _, tool := range installed {
+		if !tool.registry {
+			continue
+		}
+		output, err := runCommand(ctx, timeout, "cargo", "search", tool.Name, "--limit", "1")
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if latest, ok := parseCargoSearch(output, tool.Name); ok && latest != tool.Version {
+			tool.NewVersion = latest
+			outdated = append(outdated, tool)
+		}
+	}
+	return outdated, nil
+}
+
+// parseCargoSearch reads the `name = "version"    # description` line for
+// crate from cargo search output. Search is fuzzy, so the first result
+// only counts if its name matches exactly.
+func parseCargoSearch(output, crate string) (string, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		name, rest, ok := strings.Cut(line, " = ")
+		if !ok || strings.TrimSpace(name) != crate {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, `"`) {
+			continue
+		}
+		version, _, ok := strings.Cut(rest[1:], `"`)
+		if ok && version != "" {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// upgradeCargoArgs reinstalls the crate; cargo install replaces an older
+// installed version and does nothing when it is current.
+func upgradeCargoArgs(name string) []string {
+	return []string{"install", name}
+}
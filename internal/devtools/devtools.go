@@ -0,0 +1,162 @@
+// Package devtools wraps the language package managers developers use for
+// globally installed command-line tools outside Homebrew — pipx, cargo
+// install and npm -g — behind one Manager type for listing tools, finding
+// outdated ones and upgrading them.
+package devtools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/batch"
+)
+
+var (
+	dryRun  = false
+	timeout = 60 * time.Second
+)
+
+// UpgradeTimeout bounds a single tool upgrade. cargo install builds from
+// source, which can take many minutes.
+const UpgradeTimeout = 15 * time.Minute
+
+// SetDryRun enables/disables dry-run mode
+func SetDryRun(mode bool) {
+	dryRun = mode
+	log.Printf("Devtools dry-run mode: %v", mode)
+}
+
+// IsDryRun returns whether dry-run mode is enabled
+func IsDryRun() bool {
+	return dryRun
+}
+
+// Error represents a failed package manager command
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Tool is one globally installed tool
+type Tool struct {
+	Name       string
+	Version    string
+	NewVersion string // Set by Outdated
+
+	// registry is false for cargo crates installed from a path or git,
+	// which have no published version to compare against
+	registry bool
+}
+
+// Manager is one language package manager
+type Manager struct {
+	// ID names the manager's config group (<ID>_group) and sidebar entry
+	ID string
+	// Title is shown as the group title
+	Title string
+	// Command is the binary looked up on $PATH
+	Command string
+
+	list        func(ctx context.Context) ([]Tool, error)
+	outdated    func(ctx context.Context, installed []Tool) ([]Tool, error)
+	upgradeArgs func(name string) []string
+}
+
+// Managers lists every supported manager, in the order their groups appear
+var Managers = []*Manager{
+	{ID: "pipx", Title: "pipx", Command: "pipx", list: listPipx, outdated: outdatedPipx, upgradeArgs: upgradePipxArgs},
+	{ID: "cargo", Title: "Cargo", Command: "cargo", list: listCargo, outdated: outdatedCargo, upgradeArgs: upgradeCargoArgs},
+	{ID: "npm", Title: "npm (global)", Command: "npm", list: listNpm, outdated: outdatedNpm, upgradeArgs: upgradeNpmArgs},
+}
+
+// IsInstalled reports whether the manager's binary is on $PATH
+func (m *Manager) IsInstalled() bool {
+	_, err := exec.LookPath(m.Command)
+	return err == nil
+}
+
+// Available returns the managers whose binary is installed
+func Available() []*Manager {
+	var found []*Manager
+	for _, m := range Managers {
+		if m.IsInstalled() {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// List returns the installed tools
+func (m *Manager) List(ctx context.Context) ([]Tool, error) {
+	return m.list(ctx)
+}
+
+// Outdated returns the installed tools with a newer version available,
+// with NewVersion set
+func (m *Manager) Outdated(ctx context.Context) ([]Tool, error) {
+	installed, err := m.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.outdated(ctx, installed)
+}
+
+// Upgrade upgrades one tool to its newest version
+func (m *Manager) Upgrade(ctx context.Context, name string) error {
+	args := m.upgradeArgs(name)
+	if dryRun {
+		log.Printf("[DRY-RUN] Would execute: %s %s", m.Command, strings.Join(args, " "))
+		return nil
+	}
+	_, err := runCommand(ctx, UpgradeTimeout, m.Command, args...)
+	audit.Record(m.Command, args, err)
+	return err
+}
+
+// UpgradeAll upgrades tools one at a time, calling done after each.
+// Managers share build caches and lock files, so tools never upgrade
+// concurrently.
+func (m *Manager) UpgradeAll(ctx context.Context, tools []Tool, done func(Tool, error)) []error {
+	return batch.Run(tools, 1, func(tool Tool) error {
+		return m.Upgrade(ctx, tool.Name)
+	}, done)
+}
+
+// runCommand runs a read or upgrade command with limit as its timeout. On
+// a non-zero exit the captured stdout is still returned, since npm
+// outdated exits 1 whenever it finds something.
+func runCommand(parent context.Context, limit time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, limit)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return "", parent.Err()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", &Error{Message: fmt.Sprintf("Command '%s %s' timed out", name, strings.Join(args, " "))}
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return stdout.String(), &Error{Message: fmt.Sprintf("%s failed: %s", name, strings.TrimSpace(stderr.String()))}
+		}
+		return "", &Error{Message: err.Error()}
+	}
+
+	return stdout.String(), nil
+}
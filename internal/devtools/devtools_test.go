@@ -0,0 +1,122 @@
+package devtools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePipxList(t *testing.T) {
+	output := `{
+  "pipx_spec_version": "0.1",
+  "venvs": {
+    "ruff": {"metadata": {"main_package": {"package": "ruff", "package_version": "0.4.4"}}},
+    "black": {"metadata": {"main_package": {"package": "black", "package_version": "24.4.2"}}}
+  }
+}`
+	tools, err := parsePipxList(output)
+	if err != nil {
+		t.Fatalf("parsePipxList: %v", err)
+	}
+	want := []Tool{
+		{Name: "black", Version: "24.4.2", registry: true},
+		{Name: "ruff", Version: "0.4.4", registry: true},
+	}
+	if !reflect.DeepEqual(tools, want) {
+		t.Errorf("parsePipxList() = %+v, want %+v", tools, want)
+	}
+	if _, err := parsePipxList("not json"); err == nil {
+		t.Error("parsePipxList(not json) = nil error, want one")
+	}
+}
+
+func TestParsePipOutdated(t *testing.T) {
+	output := `[{"name": "click", "version": "8.1.6", "latest_version": "8.1.7"},
+{"name": "Poetry_Core", "version": "1.8.0", "latest_version": "1.9.0"}]`
+
+	if got, ok := parsePipOutdated(output, "poetry-core"); !ok || got != "1.9.0" {
+		t.Errorf("parsePipOutdated(poetry-core) = %q, %v, want 1.9.0, true", got, ok)
+	}
+	// Only the venv's main package counts, not its outdated dependencies
+	if got, ok := parsePipOutdated(output, "black"); ok {
+		t.Errorf("parsePipOutdated(black) = %q, true, want not found", got)
+	}
+}
+
+func TestParseCargoList(t *testing.T) {
+	output := `bat v0.24.0:
+    bat
+mytool v0.1.0 (/home/user/src/mytool):
+    mytool
+ripgrep v14.1.0:
+    rg
+`
+	want := []Tool{
+		{Name: "bat", Version: "0.24.0", registry: true},
+		{Name: "mytool", Version: "0.1.0"},
+		{Name: "ripgrep", Version: "14.1.0", registry: true},
+	}
+	if got := parseCargoList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCargoList() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCargoSearch(t *testing.T) {
+	output := `ripgrep = "14.1.1"    # ripgrep is a line-oriented search tool
+... and 123 crates more (use --limit N to see more)
+`
+	if got, ok := parseCargoSearch(output, "ripgrep"); !ok || got != "14.1.1" {
+		t.Errorf("parseCargoSearch(ripgrep) = %q, %v, want 14.1.1, true", got, ok)
+	}
+	// A fuzzy match on another crate is not an answer
+	if got, ok := parseCargoSearch(`ripgrep_all = "0.10.6"    # rga`, "ripgrep"); ok {
+		t.Errorf("parseCargoSearch(fuzzy) = %q, true, want not found", got)
+	}
+}
+
+func TestParseNpmList(t *testing.T) {
+	output := `{"name": "lib", "dependencies": {"typescript": {"version": "5.4.5"}, "npm": {"version": "10.5.0"}}}`
+	tools, err := parseNpmList(output)
+	if err != nil {
+		t.Fatalf("parseNpmList: %v", err)
+	}
+	want := []Tool{
+		{Name: "npm", Version: "10.5.0", registry: true},
+		{Name: "typescript", Version: "5.4.5", registry: true},
+	}
+	if !reflect.DeepEqual(tools, want) {
+		t.Errorf("parseNpmList() = %+v, want %+v", tools, want)
+	}
+}
+
+func TestParseNpmOutdated(t *testing.T) {
+	output := `{
+  "typescript": {"current": "5.4.5", "wanted": "5.5.2", "latest": "5.5.2"},
+  "npm": {"current": "10.5.0", "wanted": "10.5.0", "latest": "10.5.0"}
+}`
+	tools, err := parseNpmOutdated(output)
+	if err != nil {
+		t.Fatalf("parseNpmOutdated: %v", err)
+	}
+	want := []Tool{{Name: "typescript", Version: "5.4.5", NewVersion: "5.5.2", registry: true}}
+	if !reflect.DeepEqual(tools, want) {
+		t.Errorf("parseNpmOutdated() = %+v, want %+v", tools, want)
+	}
+	if tools, err := parseNpmOutdated(""); err != nil || tools != nil {
+		t.Errorf("parseNpmOutdated(\"\") = %+v, %v, want nil, nil", tools, err)
+	}
+}
+
+// TestManagersAreDistinct guards the config group and sidebar names derived
+// from each manager's ID.
+func TestManagersAreDistinct(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, m := range Managers {
+		if m.ID == "" || m.Command == "" || m.list == nil || m.outdated == nil || m.upgradeArgs == nil {
+			t.Errorf("manager %+v is incomplete", m)
+		}
+		if seen[m.ID] {
+			t.Errorf("duplicate manager ID %q", m.ID)
+		}
+		seen[m.ID] = true
+	}
+}
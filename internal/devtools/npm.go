@@ -0,0 +1,76 @@
+package devtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+func listNpm(ctx context.Context) ([]Tool, error) {
+	// npm ls exits 1 when it finds problems such as invalid peer
+	// dependencies, but still lists what is installed.
+	output, err := runCommand(ctx, timeout, "npm", "ls", "--global", "--depth=0", "--json")
+	if err != nil && output == "" {
+		return nil, err
+	}
+	return parseNpmList(output)
+}
+
+// parseNpmList reads npm ls --global --depth=0 --json
+func parseNpmList(output string) ([]Tool, error) {
+	var data struct {
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("Failed to parse npm ls: %v", err)}
+	}
+
+	tools := make([]Tool, 0, len(data.Dependencies))
+	for name, dep := range data.Dependencies {
+		tools = append(tools, Tool{Name: name, Version: dep.Version, registry: true})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+func outdatedNpm(ctx context.Context, _ []Tool) ([]Tool, error) {
+	// npm outdated exits 1 when anything is outdated; the JSON on stdout
+	// is still the answer then.
+	output, err := runCommand(ctx, timeout, "npm", "outdated", "--global", "--json")
+	if err != nil && output == "" {
+		return nil, err
+	}
+	return parseNpmOutdated(output)
+}
+
+// parseNpmOutdated reads npm outdated --global --json. Empty output means
+// nothing is outdated.
+func parseNpmOutdated(output string) ([]Tool, error) {
+	if output == "" {
+		return nil, nil
+	}
+	var data map[string]struct {
+		Current string `json:"current"`
+		Latest  string `json:"latest"`
+	}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("Failed to parse npm outdated: %v", err)}
+	}
+
+	var tools []Tool
+	for name, info := range data {
+		if info.Latest == "" || info.Latest == info.Current {
+			continue
+		}
+		tools = append(tools, Tool{Name: name, Version: info.Current, NewVersion: info.Latest, registry: true})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+func upgradeNpmArgs(name string) []string {
+	return []string{"install", "--global", name + "@latest"}
+}
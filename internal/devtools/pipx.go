@@ -0,0 +1,89 @@
+package devtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func listPipx(ctx context.Context) ([]Tool, error) {
+	output, err := runCommand(ctx, timeout, "pipx", "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePipxList(output)
+}
+
+// parsePipxList reads pipx list --json. Each venv is named after its main
+// package.
+func parsePipxList(output string) ([]Tool, error) {
+	var data struct {
+		Venvs map[string]struct {
+			Metadata struct {
+				MainPackage struct {
+					Package        string `json:"package"`
+					PackageVersion string `json:"package_version"`
+				} `json:"main_package"`
+			} `json:"metadata"`
+		} `json:"venvs"`
+	}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("Failed to parse pipx list: %v", err)}
+	}
+
+	tools := make([]Tool, 0, len(data.Venvs))
+	for venv, info := range data.Venvs {
+		tools = append(tools, Tool{Name: venv, Version: info.Metadata.MainPackage.PackageVersion, registry: true})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+// outdatedPipx asks each venv's pip which packages are outdated and keeps
+// the venv's main package. pipx has no outdated command of its own.
+func outdatedPipx(ctx context.Context, installed []Tool) ([]Tool, error) {
+	var outdated []Tool
+	for _, tool := range installed {
+		output, err := runCommand(ctx, timeout, "pipx", "runpip", tool.Name, "list", "--outdated", "--format=json")
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// One broken venv should not hide the others' updates
+			continue
+		}
+		if latest, ok := parsePipOutdated(output, tool.Name); ok {
+			tool.NewVersion = latest
+			outdated = append(outdated, tool)
+		}
+	}
+	return outdated, nil
+}
+
+// parsePipOutdated finds pkg in pip list --outdated --format=json output
+// and returns its latest version. pip normalizes names, so "-" and "_"
+// and case are ignored.
+func parsePipOutdated(output, pkg string) (string, bool) {
+	var entries []struct {
+		Name          string `json:"name"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return "", false
+	}
+	normalize := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(s), "_", "-")
+	}
+	for _, e := range entries {
+		if normalize(e.Name) == normalize(pkg) {
+			return e.LatestVersion, true
+		}
+	}
+	return "", false
+}
+
+func upgradePipxArgs(name string) []string {
+	return []string{"upgrade", name}
+}
@@ -0,0 +1,27 @@
+package flatpak
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// InstallationDirs returns where the system and user installations keep
+// their repository and deployed applications, following flatpak's own
+// FLATPAK_SYSTEM_DIR and FLATPAK_USER_DIR overrides
+func InstallationDirs() (system, user string) {
+	system = os.Getenv("FLATPAK_SYSTEM_DIR")
+	if system == "" {
+		system = "/var/lib/flatpak"
+	}
+
+	user = os.Getenv("FLATPAK_USER_DIR")
+	if user == "" {
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			home, _ := os.UserHomeDir()
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		user = filepath.Join(dataHome, "flatpak")
+	}
+	return system, user
+}
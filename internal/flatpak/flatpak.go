@@ -4,12 +4,19 @@ package flatpak
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/batch"
+	"github.com/frostyard/chairlift/internal/pkgcache"
+	"github.com/frostyard/chairlift/internal/preview"
 )
 
 var (
@@ -37,6 +44,16 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// InstallTimeout bounds installing, updating or changing the version of
+// one application, which can pull in a runtime of several hundred
+// megabytes
+const InstallTimeout = 30 * time.Minute
+
+// UpdateAllTimeout bounds updating every application and runtime in an
+// installation, the same bound as a whole Update Everything run. A
+// caller's earlier deadline still applies.
+const UpdateAllTimeout = 2 * time.Hour
+
 // NotFoundError is returned when Flatpak is not installed
 type NotFoundError struct {
 	Message string
@@ -55,25 +72,54 @@ type Application struct {
 	Origin        string `json:"origin"`
 	Installation  string `json:"installation"` // "user" or "system"
 	Ref           string `json:"ref"`
+	Description   string `json:"description"` // AppStream summary, may be empty
+	Size          int64  `json:"size"`        // Installed size in bytes, 0 if unknown
 }
 
 // stateChangingCommands are commands that modify system state
 var stateChangingCommands = map[string]bool{
-	"install":   true,
-	"uninstall": true,
-	"remove":    true,
-	"update":    true,
+	"install":       true,
+	"uninstall":     true,
+	"remove":        true,
+	"update":        true,
+	"remote-add":    true,
+	"remote-delete": true,
+	"mask":          true,
 }
 
 // runFlatpakCommand executes a flatpak command and returns the output
-func runFlatpakCommand(args ...string) (string, error) {
+func runFlatpakCommand(ctx context.Context, args ...string) (string, error) {
+	return runFlatpakCommandFor(ctx, timeout, args...)
+}
+
+// runFlatpakCommandFor is runFlatpakCommand with its own time limit, for
+// installs and updates that outlast the default timeout
+func runFlatpakCommandFor(ctx context.Context, limit time.Duration, args ...string) (string, error) {
 	if len(args) > 0 && stateChangingCommands[args[0]] && dryRun {
 		msg := fmt.Sprintf("[DRY-RUN] Would execute: flatpak %s", strings.Join(args, " "))
 		log.Println(msg)
 		return msg, nil
 	}
+	if len(args) > 0 && stateChangingCommands[args[0]] {
+		defer pkgcache.InvalidateAll()
+		output, err := runFlatpak(ctx, limit, args...)
+		audit.Record("flatpak", args, err)
+		return output, err
+	}
+
+	return runFlatpak(ctx, limit, args...)
+}
+
+// runFlatpakReadCommand executes a flatpak command without the dry-run
+// skip. Only for read-only invocations of subcommands that also have a
+// state-changing form, such as listing masks with `flatpak mask`.
+func runFlatpakReadCommand(parent context.Context, args ...string) (string, error) {
+	return runFlatpak(parent, timeout, args...)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// runFlatpak runs flatpak under limit, or parent's deadline if earlier
+func runFlatpak(parent context.Context, limit time.Duration, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, limit)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "flatpak", args...)
@@ -83,6 +129,10 @@ func runFlatpakCommand(args ...string) (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return "", parent.Err()
+		}
 		if ctx.Err() == context.DeadlineExceeded {
 			return "", &Error{Message: fmt.Sprintf("Command 'flatpak %s' timed out", strings.Join(args, " "))}
 		}
@@ -122,19 +172,19 @@ func IsInstalledCached() bool {
 }
 
 // ListUserApplications returns all user-installed Flatpak applications
-func ListUserApplications() ([]Application, error) {
-	return listApplications("--user")
+func ListUserApplications(ctx context.Context) ([]Application, error) {
+	return listApplications(ctx, "--user")
 }
 
 // ListSystemApplications returns all system-installed Flatpak applications
-func ListSystemApplications() ([]Application, error) {
-	return listApplications("--system")
+func ListSystemApplications(ctx context.Context) ([]Application, error) {
+	return listApplications(ctx, "--system")
 }
 
 // listApplications lists installed applications for a given installation type
-func listApplications(installFlag string) ([]Application, error) {
+func listApplications(ctx context.Context, installFlag string) ([]Application, error) {
 	// Use columns format for structured output
-	output, err := runFlatpakCommand("list", installFlag, "--app", "--columns=name,application,version,branch,origin,ref")
+	output, err := runFlatpakCommand(ctx, "list", installFlag, "--app", "--columns=name,application,version,branch,origin,ref,description,size")
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +240,12 @@ func parseApplicationList(output string, installFlag string) ([]Application, err
 		if len(fields) >= 6 {
 			app.Ref = strings.TrimSpace(fields[5])
 		}
+		if len(fields) >= 7 {
+			app.Description = strings.TrimSpace(fields[6])
+		}
+		if len(fields) >= 8 {
+			app.Size, _ = preview.ParseSize(strings.TrimSpace(fields[7]))
+		}
 
 		apps = append(apps, app)
 	}
@@ -197,8 +253,60 @@ func parseApplicationList(output string, installFlag string) ([]Application, err
 	return apps, nil
 }
 
-// Install installs a Flatpak application
-func Install(appID string, user bool) error {
+// SearchResult is an application found in the AppStream data of the
+// configured remotes
+type SearchResult struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	ApplicationID string `json:"application_id"`
+	Version       string `json:"version"`
+	Remotes       string `json:"remotes"`
+}
+
+// Search looks up applications matching query in the AppStream data of
+// every configured remote, user and system
+func Search(ctx context.Context, query string) ([]SearchResult, error) {
+	output, err := runFlatpakCommand(ctx, "search", "--columns=name,description,application,version,remotes", query)
+	if err != nil {
+		return nil, err
+	}
+	return parseSearchList(output), nil
+}
+
+// parseSearchList parses tab-separated flatpak search output. Descriptions
+// contain spaces, so unlike the list parsers this never splits on them.
+// flatpak prints "No matches found" instead of an empty table.
+func parseSearchList(output string) []SearchResult {
+	var results []SearchResult
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		result := SearchResult{
+			Name:          fields[0],
+			Description:   fields[1],
+			ApplicationID: fields[2],
+		}
+		if len(fields) > 3 {
+			result.Version = fields[3]
+		}
+		if len(fields) > 4 {
+			result.Remotes = fields[4]
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Install installs a Flatpak application, bounded by InstallTimeout
+func Install(ctx context.Context, appID string, user bool) error {
 	args := []string{"install", "-y"}
 	if user {
 		args = append(args, "--user")
@@ -207,12 +315,31 @@ func Install(appID string, user bool) error {
 	}
 	args = append(args, appID)
 
-	_, err := runFlatpakCommand(args...)
+	_, err := runFlatpakCommandFor(ctx, InstallTimeout, args...)
+	return err
+}
+
+// InstallFrom installs a Flatpak application from the named remote, for
+// reproducing an installation where the app ID alone may match several
+// remotes. An empty remote lets flatpak choose, as Install does.
+func InstallFrom(ctx context.Context, remote, appID string, user bool) error {
+	args := []string{"install", "-y"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	if remote != "" {
+		args = append(args, remote)
+	}
+	args = append(args, appID)
+
+	_, err := runFlatpakCommandFor(ctx, InstallTimeout, args...)
 	return err
 }
 
 // Uninstall removes a Flatpak application
-func Uninstall(appID string, user bool) error {
+func Uninstall(ctx context.Context, appID string, user bool) error {
 	args := []string{"uninstall", "-y"}
 	if user {
 		args = append(args, "--user")
@@ -221,26 +348,116 @@ func Uninstall(appID string, user bool) error {
 	}
 	args = append(args, appID)
 
-	_, err := runFlatpakCommand(args...)
+	_, err := runFlatpakCommand(ctx, args...)
 	return err
 }
 
-// Update updates a Flatpak application or all applications
-func Update(appID string, user bool) error {
+// Update updates a single Flatpak application. An empty appID is an error
+// rather than a silent update of everything; use UpdateAll for that.
+func Update(ctx context.Context, appID string, user bool) error {
+	if appID == "" {
+		return &Error{Message: "No application specified to update"}
+	}
+
 	args := []string{"update", "-y"}
 	if user {
 		args = append(args, "--user")
 	} else {
 		args = append(args, "--system")
 	}
-	if appID != "" {
-		args = append(args, appID)
+	args = append(args, appID)
+
+	_, err := runFlatpakCommandFor(ctx, InstallTimeout, args...)
+	return err
+}
+
+// UpdateAll updates every application and runtime in an installation,
+// bounded by UpdateAllTimeout or ctx's deadline, whichever comes first
+func UpdateAll(ctx context.Context, user bool) error {
+	args := []string{"update", "-y"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
 	}
 
-	_, err := runFlatpakCommand(args...)
+	_, err := runFlatpakCommandFor(ctx, UpdateAllTimeout, args...)
 	return err
 }
 
+// BatchWorkers bounds how many flatpak updates UpdateBatch runs at once.
+// Flatpak serialises writes to an installation's repo itself, so extra
+// workers mostly overlap the downloads.
+const BatchWorkers = 3
+
+// UpdateBatch updates each listed application in its own installation,
+// at most BatchWorkers at a time. It returns one error per update, in
+// order; done, if non-nil, is called from a worker goroutine as each
+// update finishes.
+func UpdateBatch(ctx context.Context, updates []UpdateInfo, done func(UpdateInfo, error)) []error {
+	return batch.Run(updates, BatchWorkers, func(u UpdateInfo) error {
+		return Update(ctx, u.ApplicationID, u.Installation == "user")
+	}, done)
+}
+
+// PreviewUpdate returns what UpdateBatch would do for updates: one
+// command per application and the download size flatpak reports for each
+// pending update. A failed size lookup only leaves the size out.
+func PreviewUpdate(ctx context.Context, updates []UpdateInfo) preview.Plan {
+	plan := preview.Plan{}
+	for _, u := range updates {
+		flag := "--system"
+		if u.Installation == "user" {
+			flag = "--user"
+		}
+		plan.Commands = append(plan.Commands, fmt.Sprintf("flatpak update -y %s %s", flag, u.ApplicationID))
+		plan.Changes = append(plan.Changes, preview.Change{Name: u.Name, Action: preview.ActionUpgrade, To: u.NewVersion})
+	}
+
+	for _, user := range []bool{true, false} {
+		flag, installation := "--system", "system"
+		if user {
+			flag, installation = "--user", "user"
+		}
+		var wanted bool
+		for _, u := range updates {
+			wanted = wanted || u.Installation == installation
+		}
+		if !wanted {
+			continue
+		}
+
+		output, err := runFlatpakCommand(ctx, "remote-ls", "--updates", flag, "--columns=application,download-size")
+		if err != nil {
+			log.Printf("Failed to get flatpak download sizes: %v", err)
+			continue
+		}
+		sizes := parseDownloadSizes(output)
+		for _, u := range updates {
+			if u.Installation == installation {
+				plan.DownloadSize += sizes[u.ApplicationID]
+			}
+		}
+	}
+	return plan
+}
+
+// parseDownloadSizes parses `flatpak remote-ls --updates
+// --columns=application,download-size` output into bytes per application
+func parseDownloadSizes(output string) map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, line := range strings.Split(output, "\n") {
+		appID, size, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if bytes, ok := preview.ParseSize(size); ok {
+			sizes[strings.TrimSpace(appID)] += bytes
+		}
+	}
+	return sizes
+}
+
 // UpdateInfo represents an available Flatpak update
 type UpdateInfo struct {
 	Name          string `json:"name"`
@@ -252,7 +469,7 @@ type UpdateInfo struct {
 }
 
 // ListUpdates returns available updates for Flatpak applications
-func ListUpdates(user bool) ([]UpdateInfo, error) {
+func ListUpdates(ctx context.Context, user bool) ([]UpdateInfo, error) {
 	args := []string{"remote-ls", "--updates", "--columns=name,application,version,branch,origin"}
 	if user {
 		args = append(args, "--user")
@@ -260,7 +477,7 @@ func ListUpdates(user bool) ([]UpdateInfo, error) {
 		args = append(args, "--system")
 	}
 
-	output, err := runFlatpakCommand(args...)
+	output, err := runFlatpakCommand(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -268,6 +485,33 @@ func ListUpdates(user bool) ([]UpdateInfo, error) {
 	return parseUpdateList(output, user)
 }
 
+// ListAllUpdates returns the available updates of both installations. One
+// installation failing only loses its own updates, and is logged; it is
+// an error only when both fail, so an empty list always means up to date.
+func ListAllUpdates(ctx context.Context) ([]UpdateInfo, error) {
+	return collectUpdates(ctx, ListUpdates)
+}
+
+// collectUpdates is ListAllUpdates with the per-installation lister passed
+// in, so tests can fake it
+func collectUpdates(ctx context.Context, list func(context.Context, bool) ([]UpdateInfo, error)) ([]UpdateInfo, error) {
+	var all []UpdateInfo
+	var errs []error
+	for _, user := range []bool{true, false} {
+		updates, err := list(ctx, user)
+		if err != nil {
+			log.Printf("Error loading flatpak updates (user=%v): %v", user, err)
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, updates...)
+	}
+	if len(errs) == 2 {
+		return nil, errors.Join(errs...)
+	}
+	return all, nil
+}
+
 // parseUpdateList parses the tabular output from flatpak remote-ls --updates
 func parseUpdateList(output string, user bool) ([]UpdateInfo, error) {
 	var updates []UpdateInfo
@@ -321,7 +565,7 @@ func parseUpdateList(output string, user bool) ([]UpdateInfo, error) {
 }
 
 // GetRemotes returns the list of configured remotes
-func GetRemotes(user bool) ([]string, error) {
+func GetRemotes(ctx context.Context, user bool) ([]string, error) {
 	args := []string{"remotes", "--columns=name"}
 	if user {
 		args = append(args, "--user")
@@ -329,7 +573,7 @@ func GetRemotes(user bool) ([]string, error) {
 		args = append(args, "--system")
 	}
 
-	output, err := runFlatpakCommand(args...)
+	output, err := runFlatpakCommand(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +590,162 @@ func GetRemotes(user bool) ([]string, error) {
 	return remotes, nil
 }
 
+// Remote represents a configured Flatpak remote
+type Remote struct {
+	Name         string `json:"name"`
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Installation string `json:"installation"` // "user" or "system"
+	Disabled     bool   `json:"disabled"`
+}
+
+// ListRemotes returns the remotes configured for an installation
+func ListRemotes(ctx context.Context, user bool) ([]Remote, error) {
+	args := []string{"remotes", "--columns=name,title,url,options"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+
+	output, err := runFlatpakCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRemoteList(output, user), nil
+}
+
+// parseRemoteList parses the tabular output from flatpak remotes
+func parseRemoteList(output string, user bool) []Remote {
+	var remotes []Remote
+
+	installation := "system"
+	if user {
+		installation = "user"
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Titles may contain spaces, so only tabs separate columns here.
+		fields := strings.Split(line, "\t")
+		remote := Remote{
+			Name:         strings.TrimSpace(fields[0]),
+			Installation: installation,
+		}
+		if remote.Name == "" {
+			continue
+		}
+		if len(fields) >= 2 {
+			remote.Title = strings.TrimSpace(fields[1])
+		}
+		if len(fields) >= 3 {
+			remote.URL = strings.TrimSpace(fields[2])
+		}
+		if len(fields) >= 4 {
+			for _, opt := range strings.Split(fields[3], ",") {
+				if strings.TrimSpace(opt) == "disabled" {
+					remote.Disabled = true
+				}
+			}
+		}
+
+		remotes = append(remotes, remote)
+	}
+
+	return remotes
+}
+
+// ValidateRemote checks a remote name and location before they are passed
+// to flatpak remote-add. Names are limited to the characters flatpak itself
+// accepts, and neither value may start with "-" so it cannot be read as an
+// option.
+func ValidateRemote(name, location string) error {
+	if err := validateRemoteName(name); err != nil {
+		return err
+	}
+
+	if location == "" {
+		return &Error{Message: "Remote location is required"}
+	}
+	if strings.HasPrefix(location, "-") || strings.ContainsAny(location, " \t\n") {
+		return &Error{Message: fmt.Sprintf("Invalid remote location %q", location)}
+	}
+
+	return nil
+}
+
+// validateRemoteName checks a remote name on its own, for commands that
+// take no location.
+func validateRemoteName(name string) error {
+	if name == "" {
+		return &Error{Message: "Remote name is required"}
+	}
+	if strings.HasPrefix(name, "-") || strings.HasPrefix(name, ".") {
+		return &Error{Message: fmt.Sprintf("Invalid remote name %q", name)}
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return &Error{Message: fmt.Sprintf("Invalid remote name %q", name)}
+		}
+	}
+	return nil
+}
+
+// AddRemote adds a remote from a repository URL or .flatpakrepo file. A
+// remote with that name already in the installation is an error, checked
+// first so dry-run reports it too, rather than a silent success.
+func AddRemote(ctx context.Context, name, location string, user bool) error {
+	if err := ValidateRemote(name, location); err != nil {
+		return err
+	}
+
+	existing, err := GetRemotes(ctx, user)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(existing, name) {
+		installation := "system"
+		if user {
+			installation = "user"
+		}
+		return &Error{Message: fmt.Sprintf("A remote named %s already exists in the %s installation", name, installation)}
+	}
+
+	args := []string{"remote-add"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, name, location)
+
+	_, err = runFlatpakCommand(ctx, args...)
+	return err
+}
+
+// RemoveRemote deletes a remote. Flatpak refuses while applications
+// installed from it remain, and that error is returned as-is.
+func RemoveRemote(ctx context.Context, name string, user bool) error {
+	if err := validateRemoteName(name); err != nil {
+		return err
+	}
+
+	args := []string{"remote-delete"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, name)
+
+	_, err := runFlatpakCommand(ctx, args...)
+	return err
+}
+
 // ApplicationInfo represents detailed info about a Flatpak application
 type ApplicationInfo struct {
 	Application
@@ -355,7 +755,7 @@ type ApplicationInfo struct {
 }
 
 // Info gets detailed information about a Flatpak application
-func Info(appID string, user bool) (*ApplicationInfo, error) {
+func Info(ctx context.Context, appID string, user bool) (*ApplicationInfo, error) {
 	args := []string{"info", "--show-metadata"}
 	if user {
 		args = append(args, "--user")
@@ -364,7 +764,7 @@ func Info(appID string, user bool) (*ApplicationInfo, error) {
 	}
 	args = append(args, appID)
 
-	output, err := runFlatpakCommand(args...)
+	output, err := runFlatpakCommand(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -409,7 +809,239 @@ func Info(appID string, user bool) (*ApplicationInfo, error) {
 	return info, nil
 }
 
+// Dependencies is what an installed application needs: the runtime it
+// runs on and the extensions installed for it, as refs without the
+// "runtime/" kind prefix (org.gnome.Platform/x86_64/47)
+type Dependencies struct {
+	Runtime    string
+	Extensions []string
+}
+
+// Runtimes returns the runtime and installed extensions of appID
+func Runtimes(ctx context.Context, appID string, user bool) (*Dependencies, error) {
+	scope := "--system"
+	if user {
+		scope = "--user"
+	}
+
+	runtime, err := runFlatpakCommand(ctx, "info", "--show-runtime", scope, appID)
+	if err != nil {
+		return nil, err
+	}
+	extensions, err := runFlatpakCommand(ctx, "info", "--show-extensions", scope, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dependencies{
+		Runtime:    strings.TrimSpace(runtime),
+		Extensions: parseExtensions(extensions),
+	}, nil
+}
+
+// parseExtensions reads the "Extension: runtime/<id>/<arch>/<branch>" lines
+// of flatpak info --show-extensions
+func parseExtensions(output string) []string {
+	var refs []string
+	for _, line := range strings.Split(output, "\n") {
+		ref, ok := strings.CutPrefix(strings.TrimSpace(line), "Extension:")
+		if !ok {
+			continue
+		}
+		ref = strings.TrimSpace(ref)
+		ref = strings.TrimPrefix(ref, "runtime/")
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// RuntimeUsers returns the installed applications, user and system, that
+// run on runtime. A runtime stays installed while any of them remain.
+func RuntimeUsers(ctx context.Context, runtime string) ([]string, error) {
+	output, err := runFlatpakCommand(ctx, "list", "--app", "--columns=application,runtime")
+	if err != nil {
+		return nil, err
+	}
+	return parseRuntimeUsers(output, runtime), nil
+}
+
+// parseRuntimeUsers picks the applications whose runtime column is runtime.
+// An app installed both per-user and system-wide is listed once.
+func parseRuntimeUsers(output, runtime string) []string {
+	var apps []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[1] != runtime || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		apps = append(apps, fields[0])
+	}
+	return apps
+}
+
+// Commit is one entry in a ref's history on its remote
+type Commit struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+	Date    string `json:"date"`
+}
+
+// ListCommits returns the history of an installed application's ref on its
+// origin remote, newest first.
+func ListCommits(ctx context.Context, app Application, user bool) ([]Commit, error) {
+	if app.Origin == "" || app.Ref == "" {
+		return nil, &Error{Message: fmt.Sprintf("No origin or ref known for %s", app.ApplicationID)}
+	}
+
+	args := []string{"remote-info", "--log"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, app.Origin, app.Ref)
+
+	output, err := runFlatpakCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitLog(output), nil
+}
+
+// parseCommitLog parses the "Commit:/Subject:/Date:" blocks printed by
+// flatpak remote-info --log. The header block describes the current head
+// and the History section repeats it, so commits are de-duplicated.
+func parseCommitLog(output string) []Commit {
+	var commits []Commit
+	seen := make(map[string]bool)
+	var current *Commit
+
+	flush := func() {
+		if current != nil && current.Commit != "" && !seen[current.Commit] {
+			seen[current.Commit] = true
+			commits = append(commits, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Commit":
+			flush()
+			current = &Commit{Commit: value}
+		case "Subject":
+			if current != nil {
+				current.Subject = value
+			}
+		case "Date":
+			if current != nil {
+				current.Date = value
+			}
+		}
+	}
+	flush()
+
+	return commits
+}
+
+// InstalledCommit returns the commit an application is currently deployed at
+func InstalledCommit(ctx context.Context, appID string, user bool) (string, error) {
+	args := []string{"info", "--show-commit"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, appID)
+
+	output, err := runFlatpakCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// Downgrade deploys a specific commit of an installed ref, which may be
+// older than the one installed
+func Downgrade(ctx context.Context, ref, commit string, user bool) error {
+	if ref == "" || commit == "" || strings.HasPrefix(commit, "-") {
+		return &Error{Message: "A ref and commit are required to change versions"}
+	}
+
+	args := []string{"update", "-y", "--commit=" + commit}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, ref)
+
+	_, err := runFlatpakCommandFor(ctx, InstallTimeout, args...)
+	return err
+}
+
+// ListMasked returns the patterns masked from updates and automatic installs
+func ListMasked(ctx context.Context, user bool) ([]string, error) {
+	args := []string{"mask"}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+
+	// Listing does not change anything, so bypass the dry-run skip that
+	// "mask" is otherwise subject to.
+	output, err := runFlatpakReadCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, ":") {
+			// Skip blanks and the "Masked patterns:" heading
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// Mask holds an application at its current version by masking it from
+// updates. With remove set, an existing mask is lifted instead.
+func Mask(ctx context.Context, appID string, remove, user bool) error {
+	if appID == "" || strings.HasPrefix(appID, "-") {
+		return &Error{Message: "No application specified to hold"}
+	}
+
+	args := []string{"mask"}
+	if remove {
+		args = append(args, "--remove")
+	}
+	if user {
+		args = append(args, "--user")
+	} else {
+		args = append(args, "--system")
+	}
+	args = append(args, appID)
+
+	_, err := runFlatpakCommand(ctx, args...)
+	return err
+}
+
 // UninstallUnused removes unused Flatpak runtimes and extensions
-func UninstallUnused() (string, error) {
-	return runFlatpakCommand("uninstall", "--unused", "-y")
+func UninstallUnused(ctx context.Context) (string, error) {
+	return runFlatpakCommand(ctx, "uninstall", "--unused", "-y")
 }
@@ -0,0 +1,313 @@
+package flatpak
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectUpdates(t *testing.T) {
+	userUpdate := UpdateInfo{ApplicationID: "org.gnome.Loupe", Installation: "user"}
+	systemUpdate := UpdateInfo{ApplicationID: "org.gnome.Maps", Installation: "system"}
+	boom := errors.New("boom")
+	lister := func(userErr, systemErr error) func(context.Context, bool) ([]UpdateInfo, error) {
+		return func(_ context.Context, user bool) ([]UpdateInfo, error) {
+			if user {
+				return []UpdateInfo{userUpdate}, userErr
+			}
+			return []UpdateInfo{systemUpdate}, systemErr
+		}
+	}
+
+	got, err := collectUpdates(context.Background(), lister(nil, nil))
+	if err != nil || !reflect.DeepEqual(got, []UpdateInfo{userUpdate, systemUpdate}) {
+		t.Errorf("collectUpdates(both ok) = %+v, %v", got, err)
+	}
+
+	// One installation failing only loses its own updates
+	got, err = collectUpdates(context.Background(), lister(nil, boom))
+	if err != nil || !reflect.DeepEqual(got, []UpdateInfo{userUpdate}) {
+		t.Errorf("collectUpdates(system failed) = %+v, %v", got, err)
+	}
+
+	// Both failing is an error, not an empty "up to date" list
+	got, err = collectUpdates(context.Background(), lister(boom, boom))
+	if !errors.Is(err, boom) || got != nil {
+		t.Errorf("collectUpdates(both failed) = %+v, %v, want an error", got, err)
+	}
+}
+
+func TestParseRemoteList(t *testing.T) {
+	output := "flathub\tFlathub\thttps://dl.flathub.org/repo/\tsystem\n" +
+		"corp\tCorp Apps\thttps://flatpak.example.com/repo/\tsystem,disabled\n" +
+		"\n" +
+		"bare\n"
+
+	got := parseRemoteList(output, false)
+	want := []Remote{
+		{Name: "flathub", Title: "Flathub", URL: "https://dl.flathub.org/repo/", Installation: "system"},
+		{Name: "corp", Title: "Corp Apps", URL: "https://flatpak.example.com/repo/", Installation: "system", Disabled: true},
+		{Name: "bare", Installation: "system"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseRemoteList() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	for _, r := range parseRemoteList(output, true) {
+		if r.Installation != "user" {
+			t.Errorf("parseRemoteList(user=true) remote %q Installation = %q, want user", r.Name, r.Installation)
+		}
+	}
+}
+
+func TestValidateRemote(t *testing.T) {
+	tests := []struct {
+		name     string
+		remote   string
+		location string
+		wantErr  bool
+	}{
+		{"flathub repo file", "flathub", "https://dl.flathub.org/repo/flathub.flatpakrepo", false},
+		{"dotted name", "org.example.apps", "https://example.com/repo/", false},
+		{"empty name", "", "https://example.com/repo/", true},
+		{"option-like name", "--system", "https://example.com/repo/", true},
+		{"leading dot", ".hidden", "https://example.com/repo/", true},
+		{"space in name", "my remote", "https://example.com/repo/", true},
+		{"slash in name", "a/b", "https://example.com/repo/", true},
+		{"empty location", "flathub", "", true},
+		{"option-like location", "flathub", "--from=x", true},
+		{"space in location", "flathub", "https://example.com/my repo", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRemote(tt.remote, tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRemote(%q, %q) error = %v, wantErr %v", tt.remote, tt.location, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestUpdateRequiresAppID guards against an empty appID turning a per-app
+// Update click into an update of the whole installation. The check runs
+// before any command, so it holds in both dry-run and live mode.
+func TestUpdateRequiresAppID(t *testing.T) {
+	for _, user := range []bool{true, false} {
+		if err := Update(context.Background(), "", user); err == nil {
+			t.Errorf("Update(\"\", %v) = nil, want an error", user)
+		}
+	}
+}
+
+func TestParseApplicationListDescription(t *testing.T) {
+	output := "Loupe\torg.gnome.Loupe\t48.1\tstable\tflathub\tapp/org.gnome.Loupe/x86_64/stable\tView images\n" +
+		"Tool\torg.example.Tool\t1.0\tstable\tcorp\tapp/org.example.Tool/x86_64/stable\t\n" +
+		"Maps\torg.gnome.Maps\t47.0\tstable\tflathub\tapp/org.gnome.Maps/x86_64/stable\tFind places\t12.5 MB\n"
+
+	apps, err := parseApplicationList(output, "--user")
+	if err != nil {
+		t.Fatalf("parseApplicationList: %v", err)
+	}
+	want := []Application{
+		{Name: "Loupe", ApplicationID: "org.gnome.Loupe", Version: "48.1", Branch: "stable", Origin: "flathub", Installation: "user", Ref: "app/org.gnome.Loupe/x86_64/stable", Description: "View images"},
+		{Name: "Tool", ApplicationID: "org.example.Tool", Version: "1.0", Branch: "stable", Origin: "corp", Installation: "user", Ref: "app/org.example.Tool/x86_64/stable"},
+		{Name: "Maps", ApplicationID: "org.gnome.Maps", Version: "47.0", Branch: "stable", Origin: "flathub", Installation: "user", Ref: "app/org.gnome.Maps/x86_64/stable", Description: "Find places", Size: 12_500_000},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Fatalf("parseApplicationList() =\n%+v\nwant\n%+v", apps, want)
+	}
+}
+
+// flatpak list prints sizes with a no-break space between the number and
+// the unit, as copied from flatpak 1.16:
+// flatpak list --user --app --columns=name,application,version,branch,origin,ref,description,size
+func TestParseApplicationListSize(t *testing.T) {
+	output := "Loupe\torg.gnome.Loupe\t48.1\tstable\tflathub\tapp/org.gnome.Loupe/x86_64/stable\tView images\t5.3\u00a0MB\n" +
+		"Extension Manager\tcom.mattjakeman.ExtensionManager\t0.6.3\tstable\tflathub\tapp/com.mattjakeman.ExtensionManager/x86_64/stable\tBrowse, install and manage GNOME Shell Extensions\t2.7\u00a0MB\n" +
+		"Tiny\torg.example.Tiny\t1.0\tstable\tflathub\tapp/org.example.Tiny/x86_64/stable\tA small app\t824\u00a0bytes\n"
+
+	apps, err := parseApplicationList(output, "--user")
+	if err != nil {
+		t.Fatalf("parseApplicationList: %v", err)
+	}
+	want := map[string]int64{
+		"org.gnome.Loupe":                  5_300_000,
+		"com.mattjakeman.ExtensionManager": 2_700_000,
+		"org.example.Tiny":                 824,
+	}
+	if len(apps) != len(want) {
+		t.Fatalf("parseApplicationList() returned %d apps, want %d", len(apps), len(want))
+	}
+	for _, app := range apps {
+		if app.Size != want[app.ApplicationID] {
+			t.Errorf("%s Size = %d, want %d", app.ApplicationID, app.Size, want[app.ApplicationID])
+		}
+	}
+}
+
+func TestParseCommitLog(t *testing.T) {
+	output := `
+Loupe - View images
+
+        ID: org.gnome.Loupe
+       Ref: app/org.gnome.Loupe/x86_64/stable
+   Version: 48.1
+
+    Commit: aaa111
+    Parent: bbb222
+   Subject: Update to 48.1
+      Date: 2026-05-01 10:00:00 +0000
+   History:
+
+    Commit: aaa111
+   Subject: Update to 48.1
+      Date: 2026-05-01 10:00:00 +0000
+
+    Commit: bbb222
+   Subject: Update to 48.0
+      Date: 2026-04-01 10:00:00 +0000
+`
+	want := []Commit{
+		{Commit: "aaa111", Subject: "Update to 48.1", Date: "2026-05-01 10:00:00 +0000"},
+		{Commit: "bbb222", Subject: "Update to 48.0", Date: "2026-04-01 10:00:00 +0000"},
+	}
+	if got := parseCommitLog(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseCommitLog() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestDowngradeRejectsBadArgs checks that Downgrade refuses to run without a
+// ref and commit, and never passes a commit that would parse as a flag.
+func TestDowngradeRejectsBadArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		ref    string
+		commit string
+	}{
+		{"empty ref", "", "aaa111"},
+		{"empty commit", "app/org.gnome.Loupe/x86_64/stable", ""},
+		{"flag commit", "app/org.gnome.Loupe/x86_64/stable", "--system"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Downgrade(context.Background(), tt.ref, tt.commit, true); err == nil {
+				t.Errorf("Downgrade(%q, %q) = nil, want an error", tt.ref, tt.commit)
+			}
+		})
+	}
+}
+
+func TestParseSearchList(t *testing.T) {
+	output := "Loupe\tView images\torg.gnome.Loupe\t48.1\tflathub\n" +
+		"Tool\tA tool with spaces in its summary\torg.example.Tool\t\tcorp,flathub\n"
+
+	want := []SearchResult{
+		{Name: "Loupe", Description: "View images", ApplicationID: "org.gnome.Loupe", Version: "48.1", Remotes: "flathub"},
+		{Name: "Tool", Description: "A tool with spaces in its summary", ApplicationID: "org.example.Tool", Remotes: "corp,flathub"},
+	}
+	if got := parseSearchList(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseSearchList() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := parseSearchList("No matches found\n"); len(got) != 0 {
+		t.Errorf("parseSearchList(no matches) = %+v, want none", got)
+	}
+}
+
+func TestParseDownloadSizes(t *testing.T) {
+	output := "org.gnome.Loupe\t12.3 MB\n" +
+		"org.gnome.Platform\t1.0 kB\n" +
+		"org.gnome.Maps\t4.2\u00a0MB\n" +
+		"org.example.Odd\tunknown\n" +
+		"\n"
+
+	want := map[string]int64{
+		"org.gnome.Loupe":    12_300_000,
+		"org.gnome.Platform": 1000,
+		"org.gnome.Maps":     4_200_000,
+	}
+	if got := parseDownloadSizes(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDownloadSizes() = %v, want %v", got, want)
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	output := `Extension: runtime/org.gnome.Loupe.Locale/x86_64/stable
+         ID: org.gnome.Loupe.Locale
+     Origin: flathub
+
+Extension: runtime/org.freedesktop.Platform.GL.default/x86_64/24.08
+         ID: org.freedesktop.Platform.GL.default
+     Origin: flathub
+`
+	want := []string{"org.gnome.Loupe.Locale/x86_64/stable", "org.freedesktop.Platform.GL.default/x86_64/24.08"}
+	if got := parseExtensions(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseExtensions() = %v, want %v", got, want)
+	}
+}
+
+func TestParseRuntimeUsers(t *testing.T) {
+	output := "org.gnome.Loupe\torg.gnome.Platform/x86_64/47\n" +
+		"org.mozilla.firefox\torg.freedesktop.Platform/x86_64/24.08\n" +
+		"org.gnome.Maps\torg.gnome.Platform/x86_64/47\n" +
+		"org.gnome.Loupe\torg.gnome.Platform/x86_64/47\n"
+
+	want := []string{"org.gnome.Loupe", "org.gnome.Maps"}
+	if got := parseRuntimeUsers(output, "org.gnome.Platform/x86_64/47"); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRuntimeUsers() = %v, want %v", got, want)
+	}
+}
+
+func TestInstallationDirs(t *testing.T) {
+	t.Setenv("FLATPAK_SYSTEM_DIR", "")
+	t.Setenv("FLATPAK_USER_DIR", "")
+	t.Setenv("XDG_DATA_HOME", "/home/u/.data")
+	system, user := InstallationDirs()
+	if system != "/var/lib/flatpak" || user != "/home/u/.data/flatpak" {
+		t.Errorf("InstallationDirs() = %q, %q, want /var/lib/flatpak, /home/u/.data/flatpak", system, user)
+	}
+
+	t.Setenv("FLATPAK_USER_DIR", "/srv/flatpak-user")
+	if _, user := InstallationDirs(); user != "/srv/flatpak-user" {
+		t.Errorf("InstallationDirs() user = %q, want FLATPAK_USER_DIR", user)
+	}
+}
+
+// fakeFlatpakOnPath puts a script named flatpak first on $PATH.
+func fakeFlatpakOnPath(t *testing.T, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flatpak"), []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestUpdateAllOutlastsDefaultTimeout checks that updating a whole
+// installation is bounded by UpdateAllTimeout, not the default timeout
+// meant for listing.
+func TestUpdateAllOutlastsDefaultTimeout(t *testing.T) {
+	fakeFlatpakOnPath(t, "sleep 0.3\n")
+	saved := timeout
+	timeout = 50 * time.Millisecond
+	t.Cleanup(func() { timeout = saved })
+
+	if err := UpdateAll(context.Background(), true); err != nil {
+		t.Errorf("UpdateAll() = %v, want nil", err)
+	}
+	if err := Install(context.Background(), "org.gnome.Loupe", true); err != nil {
+		t.Errorf("Install() = %v, want nil", err)
+	}
+
+	// The same command under the default timeout is cut off
+	if _, err := GetRemotes(context.Background(), true); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("GetRemotes() = %v, want a timeout", err)
+	}
+}
@@ -0,0 +1,113 @@
+package flatpak
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/pkgcache"
+	"github.com/frostyard/chairlift/internal/systemhelper"
+)
+
+// RepairTimeout bounds a repair, which re-verifies every object in the
+// installation and can take a long time on a large one
+const RepairTimeout = 30 * time.Minute
+
+// repairCommand returns the command that repairs the user or system
+// installation. flatpak repair --system must run as root, so it goes
+// through the system helper via pkexec, under its own polkit action
+// (org.frostyard.ChairLift.flatpak.repair).
+func repairCommand(user bool) (string, []string) {
+	if user {
+		return "flatpak", []string{"repair", "--user"}
+	}
+	return "pkexec", []string{systemhelper.Path, systemhelper.FlatpakRepair}
+}
+
+// Repair runs flatpak repair for the user or system installation, sending
+// each output line to lineCh. lineCh is closed when done. Repair removes
+// corrupt objects, re-pulls what they belonged to and deletes refs it
+// cannot fix, so it counts as state-changing.
+func Repair(parent context.Context, user bool, lineCh chan<- string) error {
+	name, args := repairCommand(user)
+	if dryRun {
+		msg := fmt.Sprintf("[DRY-RUN] Would execute: %s %s", name, strings.Join(args, " "))
+		log.Println(msg)
+		lineCh <- msg
+		close(lineCh)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, RepairTimeout)
+	defer cancel()
+
+	err := runFlatpakStreaming(ctx, lineCh, name, args...)
+	audit.Record(name, args, err)
+	return err
+}
+
+// runFlatpakStreaming runs a long flatpak command, streaming stdout+stderr
+// lines to lineCh. It closes lineCh before returning. Takes the command
+// name so tests can run a local fake script.
+func runFlatpakStreaming(ctx context.Context, lineCh chan<- string, name string, args ...string) error {
+	defer close(lineCh)
+	defer pkgcache.InvalidateAll()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("failed to create stdout pipe: %v", err)}
+	}
+	// Problems found are reported on stderr
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		if execErr, ok := err.(*exec.Error); ok && execErr.Err == exec.ErrNotFound {
+			return &NotFoundError{Message: fmt.Sprintf("%s not found", name)}
+		}
+		return &Error{Message: fmt.Sprintf("failed to start %s: %v", name, err)}
+	}
+
+	var lastLine string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lastLine = line
+
+		select {
+		case lineCh <- line:
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait() // reap the killed child; error is expected here
+			return ctx.Err()
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &Error{Message: fmt.Sprintf("Command '%s %s' timed out", name, strings.Join(args, " "))}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			msg := fmt.Sprintf("%s %s failed (exit %d)", name, strings.Join(args, " "), exitErr.ExitCode())
+			if lastLine != "" {
+				msg += ": " + lastLine
+			}
+			return &Error{Message: msg}
+		}
+		return &Error{Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package flatpak
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake-flatpak")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func collectLines(ch <-chan string) []string {
+	var lines []string
+	for line := range ch {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRepairCommand(t *testing.T) {
+	name, args := repairCommand(true)
+	if name != "flatpak" || !reflect.DeepEqual(args, []string{"repair", "--user"}) {
+		t.Errorf("repairCommand(user) = %s %v", name, args)
+	}
+	// The system installation can only be repaired as root, through the
+	// fixed system helper
+	name, args = repairCommand(false)
+	if name != "pkexec" || !reflect.DeepEqual(args, []string{"/usr/bin/chairlift-system-helper", "flatpak-repair"}) {
+		t.Errorf("repairCommand(system) = %s %v", name, args)
+	}
+}
+
+func TestRunFlatpakStreaming(t *testing.T) {
+	script := writeScript(t, `echo "Verifying flathub:app/org.gnome.Maps/x86_64/stable…"
+echo ""
+echo "Object missing: 1a2b.file" >&2
+echo "Checking remotes..."`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan string)
+	done := make(chan error, 1)
+	go func() { done <- runFlatpakStreaming(ctx, ch, script) }()
+
+	lines := collectLines(ch)
+	if err := <-done; err != nil {
+		t.Fatalf("runFlatpakStreaming: %v", err)
+	}
+	// Blank lines are dropped; stderr is merged in order
+	if len(lines) != 3 || lines[1] != "Object missing: 1a2b.file" {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestRunFlatpakStreamingFailure(t *testing.T) {
+	script := writeScript(t, `echo "Verifying..."
+echo "error: Can't open repo" >&2
+exit 1`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan string)
+	done := make(chan error, 1)
+	go func() { done <- runFlatpakStreaming(ctx, ch, script) }()
+
+	collectLines(ch)
+	err := <-done
+	if err == nil {
+		t.Fatal("runFlatpakStreaming = nil, want an error for exit 1")
+	}
+	if !strings.Contains(err.Error(), "Can't open repo") {
+		t.Errorf("error %q does not carry the last output line", err)
+	}
+}
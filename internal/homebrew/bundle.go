@@ -0,0 +1,206 @@
+package homebrew
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/pkgcache"
+)
+
+// BundleTimeout bounds a streamed brew bundle install. A Brewfile can pull
+// in dozens of bottles, so this is far longer than the per-command timeout.
+const BundleTimeout = 30 * time.Minute
+
+// BundleContext returns a context with the BundleTimeout, cancelled early
+// if parent is
+func BundleContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, BundleTimeout)
+}
+
+// BundleStatus is what brew bundle last reported for one Brewfile entry
+type BundleStatus string
+
+const (
+	// BundleUsing means the entry was already installed
+	BundleUsing BundleStatus = "using"
+	// BundleInstalled means brew bundle installed or upgraded the entry
+	BundleInstalled BundleStatus = "installed"
+	// BundleFailed means brew bundle reported the entry as failed
+	BundleFailed BundleStatus = "failed"
+)
+
+// BundleEvent is one line of brew bundle install output. Package and Status
+// are set when the line reports on a Brewfile entry.
+type BundleEvent struct {
+	Line    string
+	Package string
+	Status  BundleStatus
+}
+
+// BundleInstallStreaming runs brew bundle install for the Brewfile at path,
+// sending each output line to eventCh. eventCh is closed when done.
+func BundleInstallStreaming(ctx context.Context, path string, eventCh chan<- BundleEvent) error {
+	args := []string{"bundle", "install", "--file=" + path}
+	if dryRun {
+		msg := fmt.Sprintf("[DRY-RUN] Would execute: brew %s", strings.Join(args, " "))
+		log.Println(msg)
+		eventCh <- BundleEvent{Line: msg}
+		close(eventCh)
+		return nil
+	}
+	err := runBrewStreaming(ctx, eventCh, "brew", args...)
+	audit.Record("brew", args, err)
+	return err
+}
+
+// UpgradeAllStreaming runs brew upgrade for every outdated package,
+// sending each output line to eventCh. eventCh is closed when done.
+// Upgrading lines carry the package name with BundleInstalled.
+func UpgradeAllStreaming(ctx context.Context, eventCh chan<- BundleEvent) error {
+	if dryRun {
+		msg := "[DRY-RUN] Would execute: brew upgrade"
+		log.Println(msg)
+		eventCh <- BundleEvent{Line: msg}
+		close(eventCh)
+		return nil
+	}
+	err := runBrewStreaming(ctx, eventCh, "brew", "upgrade")
+	audit.Record("brew", []string{"upgrade"}, err)
+	return err
+}
+
+// runBrewStreaming runs a long brew command (bundle install, upgrade),
+// streaming stdout+stderr lines to eventCh. It closes eventCh before
+// returning. Takes the command name so tests can run a local fake script.
+func runBrewStreaming(ctx context.Context, eventCh chan<- BundleEvent, name string, args ...string) error {
+	defer close(eventCh)
+	defer pkgcache.InvalidateAll()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("failed to create stdout pipe: %v", err)}
+	}
+	// Failed installs explain themselves on stderr.
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		if execErr, ok := err.(*exec.Error); ok && execErr.Err == exec.ErrNotFound {
+			return &NotFoundError{Message: "Homebrew not found. Please install Homebrew first."}
+		}
+		return &Error{Message: fmt.Sprintf("failed to start %s: %v", name, err)}
+	}
+
+	var lastLine string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lastLine = line
+
+		event := BundleEvent{Line: line}
+		if pkg, status, ok := parseBundleLine(line); ok {
+			event.Package = pkg
+			event.Status = status
+		}
+		select {
+		case eventCh <- event:
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait() // reap the killed child; error is expected here
+			return ctx.Err()
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &Error{Message: fmt.Sprintf("Command 'brew %s' timed out", strings.Join(args, " "))}
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			msg := fmt.Sprintf("brew %s failed (exit %d)", strings.Join(args, " "), exitErr.ExitCode())
+			if lastLine != "" {
+				msg += ": " + lastLine
+			}
+			if isUntrustedTapMessage(lastLine) {
+				return &UntrustedTapError{Message: msg}
+			}
+			return &Error{Message: msg}
+		}
+		return &Error{Message: err.Error()}
+	}
+
+	return nil
+}
+
+// parseBundleLine recognises the per-entry lines brew bundle install prints:
+// "Using <name>", "Installing <name>", "Upgrading <name>" and
+// "Installing <name> has failed!".
+func parseBundleLine(line string) (string, BundleStatus, bool) {
+	verb, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return "", "", false
+	}
+
+	if name, failed := strings.CutSuffix(rest, " has failed!"); failed {
+		return name, BundleFailed, name != ""
+	}
+	if strings.Contains(rest, " ") {
+		return "", "", false
+	}
+
+	switch verb {
+	case "Using":
+		return rest, BundleUsing, true
+	case "Installing", "Upgrading":
+		return rest, BundleInstalled, true
+	}
+	return "", "", false
+}
+
+// BundleSummary counts the final status of each entry seen in a bundle run
+// and lists the entries that failed, sorted by name.
+func BundleSummary(statuses map[string]BundleStatus) (installed, using int, failed []string) {
+	for name, status := range statuses {
+		switch status {
+		case BundleInstalled:
+			installed++
+		case BundleUsing:
+			using++
+		case BundleFailed:
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return installed, using, failed
+}
+
+// ListBundles returns the Brewfiles found directly inside dirs. Missing
+// directories are skipped; hidden files and subdirectories are ignored.
+func ListBundles(dirs []string) []string {
+	var bundles []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			bundles = append(bundles, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return bundles
+}
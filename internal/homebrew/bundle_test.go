@@ -0,0 +1,120 @@
+package homebrew
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeScript writes an executable shell script and returns its path.
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake-brew")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func collectBundleEvents(ch <-chan BundleEvent) []BundleEvent {
+	var events []BundleEvent
+	for e := range ch {
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestParseBundleLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantPkg    string
+		wantStatus BundleStatus
+		wantOK     bool
+	}{
+		{"Using wget", "wget", BundleUsing, true},
+		{"Installing jq", "jq", BundleInstalled, true},
+		{"Upgrading node", "node", BundleInstalled, true},
+		{"Installing foo has failed!", "foo", BundleFailed, true},
+		{"Homebrew Bundle complete! 3 Brewfile dependencies now installed.", "", "", false},
+		{"Installing dependencies for jq: oniguruma", "", "", false},
+		{"Tapping homebrew/cask", "", "", false},
+	}
+
+	for _, tt := range tests {
+		pkg, status, ok := parseBundleLine(tt.line)
+		if pkg != tt.wantPkg || status != tt.wantStatus || ok != tt.wantOK {
+			t.Errorf("parseBundleLine(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.line, pkg, status, ok, tt.wantPkg, tt.wantStatus, tt.wantOK)
+		}
+	}
+}
+
+func TestBundleSummary(t *testing.T) {
+	installed, using, failed := BundleSummary(map[string]BundleStatus{
+		"wget": BundleUsing,
+		"jq":   BundleInstalled,
+		"node": BundleInstalled,
+		"zed":  BundleFailed,
+		"foo":  BundleFailed,
+	})
+	if installed != 2 || using != 1 {
+		t.Errorf("BundleSummary counts = (%d installed, %d using), want (2, 1)", installed, using)
+	}
+	if want := []string{"foo", "zed"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("BundleSummary failed = %v, want %v", failed, want)
+	}
+}
+
+func TestRunBundleStreamingFailure(t *testing.T) {
+	script := writeScript(t, `echo "Using wget"
+echo "Installing foo has failed!"
+echo "Homebrew Bundle failed! 1 Brewfile dependency failed to install." >&2
+exit 1`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch := make(chan BundleEvent)
+	done := make(chan error, 1)
+	go func() { done <- runBrewStreaming(ctx, ch, script) }()
+
+	events := collectBundleEvents(ch)
+	err := <-done
+	if err == nil {
+		t.Fatal("runBrewStreaming = nil, want an error for exit 1")
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events %+v, want 3", len(events), events)
+	}
+	if events[0].Package != "wget" || events[0].Status != BundleUsing {
+		t.Errorf("event[0] = %+v, want wget using", events[0])
+	}
+	if events[1].Package != "foo" || events[1].Status != BundleFailed {
+		t.Errorf("event[1] = %+v, want foo failed", events[1])
+	}
+	if events[2].Package != "" {
+		t.Errorf("event[2] = %+v, want a plain line", events[2])
+	}
+}
+
+func TestListBundles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"dev.Brewfile", "fonts.Brewfile", ".hidden"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "subdir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ListBundles([]string{dir, filepath.Join(dir, "missing")})
+	want := []string{filepath.Join(dir, "dev.Brewfile"), filepath.Join(dir, "fonts.Brewfile")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListBundles() = %v, want %v", got, want)
+	}
+}
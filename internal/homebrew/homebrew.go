@@ -6,11 +6,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"log"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/batch"
+	"github.com/frostyard/chairlift/internal/pkgcache"
+	"github.com/frostyard/chairlift/internal/preview"
 )
 
 var (
@@ -51,9 +58,11 @@ func (e *NotFoundError) Error() string {
 type Package struct {
 	Name               string   `json:"name"`
 	Version            string   `json:"version"`
+	NewVersion         string   `json:"new_version,omitempty"`
 	InstalledOnRequest bool     `json:"installed_on_request"`
 	Pinned             bool     `json:"pinned"`
 	Outdated           bool     `json:"outdated"`
+	Size               int64    `json:"size,omitempty"` // Bytes on disk, 0 if unknown
 	Dependencies       []string `json:"dependencies,omitempty"`
 }
 
@@ -62,6 +71,9 @@ type SearchResult struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Homepage    string `json:"homepage"`
+	Version     string `json:"version"`
+	IsCask      bool   `json:"is_cask"`
+	Installed   bool   `json:"installed"`
 }
 
 // stateChangingCommands are commands that modify system state
@@ -79,14 +91,27 @@ var stateChangingCommands = map[string]bool{
 }
 
 // runBrewCommand executes a brew command and returns the output
-func runBrewCommand(args ...string) (string, error) {
+func runBrewCommand(parent context.Context, args ...string) (string, error) {
 	if len(args) > 0 && stateChangingCommands[args[0]] && dryRun {
 		msg := fmt.Sprintf("[DRY-RUN] Would execute: brew %s", strings.Join(args, " "))
 		log.Println(msg)
 		return msg, nil
 	}
+	if len(args) > 0 && stateChangingCommands[args[0]] {
+		defer pkgcache.InvalidateAll()
+		output, err := runBrewReadCommand(parent, args...)
+		audit.Record("brew", args, err)
+		return output, err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	return runBrewReadCommand(parent, args...)
+}
+
+// runBrewReadCommand executes a brew command without the dry-run skip.
+// Only for read-only invocations of subcommands that also have a
+// state-changing form, such as `brew upgrade --dry-run`.
+func runBrewReadCommand(parent context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "brew", args...)
@@ -96,6 +121,10 @@ func runBrewCommand(args ...string) (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return "", parent.Err()
+		}
 		if ctx.Err() == context.DeadlineExceeded {
 			return "", &Error{Message: fmt.Sprintf("Command 'brew %s' timed out", strings.Join(args, " "))}
 		}
@@ -138,23 +167,83 @@ func IsInstalledCached() bool {
 }
 
 // ListInstalledFormulae returns all installed formulae
-func ListInstalledFormulae() ([]Package, error) {
-	output, err := runBrewCommand("info", "--installed", "--json=v2", "--formula")
+func ListInstalledFormulae(ctx context.Context) ([]Package, error) {
+	output, err := runBrewCommand(ctx, "info", "--installed", "--json=v2", "--formula")
 	if err != nil {
 		return nil, err
 	}
 
-	return parsePackagesJSON(output, true)
+	packages, err := parsePackagesJSON(output, true)
+	if err != nil {
+		return nil, err
+	}
+	addSizes(ctx, packages, "--cellar")
+	return packages, nil
 }
 
 // ListInstalledCasks returns all installed casks
-func ListInstalledCasks() ([]Package, error) {
-	output, err := runBrewCommand("info", "--installed", "--json=v2", "--cask")
+func ListInstalledCasks(ctx context.Context) ([]Package, error) {
+	output, err := runBrewCommand(ctx, "info", "--installed", "--json=v2", "--cask")
 	if err != nil {
 		return nil, err
 	}
 
-	return parsePackagesJSON(output, false)
+	packages, err := parsePackagesJSON(output, false)
+	if err != nil {
+		return nil, err
+	}
+	addSizes(ctx, packages, "--caskroom")
+	return packages, nil
+}
+
+// addSizes fills in each package's Size from its directory under the
+// Cellar (dirFlag "--cellar") or Caskroom ("--caskroom"). brew info does
+// not report sizes. They are only informational, so a failure is logged
+// and leaves them at 0.
+func addSizes(ctx context.Context, packages []Package, dirFlag string) {
+	output, err := runBrewReadCommand(ctx, dirFlag)
+	if err != nil {
+		log.Printf("brew %s: %v", dirFlag, err)
+		return
+	}
+	root := strings.TrimSpace(output)
+	for i := range packages {
+		packages[i].Size = dirSize(filepath.Join(root, packages[i].Name))
+	}
+}
+
+// Locations returns the Cellar, where installed formulae live, and the
+// download cache, the two directories `brew cleanup` trims
+func Locations(ctx context.Context) (cellar, cache string, err error) {
+	output, err := runBrewReadCommand(ctx, "--cellar")
+	if err != nil {
+		return "", "", err
+	}
+	cellar = strings.TrimSpace(output)
+	output, err = runBrewReadCommand(ctx, "--cache")
+	if err != nil {
+		return "", "", err
+	}
+	return cellar, strings.TrimSpace(output), nil
+}
+
+// dirSize sums the regular files under dir without following symlinks, so
+// files linked in from other kegs are not counted twice. A missing dir
+// is 0.
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
 }
 
 // parsePackagesJSON parses the JSON output from brew info
@@ -212,13 +301,18 @@ func parsePackagesJSON(jsonData string, isFormula bool) ([]Package, error) {
 	return packages, nil
 }
 
-// ListOutdated returns all outdated packages
-func ListOutdated() ([]Package, error) {
-	output, err := runBrewCommand("outdated", "--json=v2")
+// ListOutdated returns all outdated packages with their installed Version
+// and the NewVersion an upgrade would bring
+func ListOutdated(ctx context.Context) ([]Package, error) {
+	output, err := runBrewCommand(ctx, "outdated", "--json=v2")
 	if err != nil {
 		return nil, err
 	}
+	return parseOutdatedJSON(output)
+}
 
+// parseOutdatedJSON parses the JSON output from brew outdated --json=v2
+func parseOutdatedJSON(jsonData string) ([]Package, error) {
 	var data struct {
 		Formulae []struct {
 			Name              string   `json:"name"`
@@ -233,24 +327,26 @@ func ListOutdated() ([]Package, error) {
 		} `json:"casks"`
 	}
 
-	if err := json.Unmarshal([]byte(output), &data); err != nil {
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
 		return nil, &Error{Message: fmt.Sprintf("Failed to parse JSON: %v", err)}
 	}
 
 	var packages []Package
 	for _, f := range data.Formulae {
 		packages = append(packages, Package{
-			Name:     f.Name,
-			Version:  strings.Join(f.InstalledVersions, ", "),
-			Outdated: true,
-			Pinned:   f.Pinned,
+			Name:       f.Name,
+			Version:    strings.Join(f.InstalledVersions, ", "),
+			NewVersion: f.CurrentVersion,
+			Outdated:   true,
+			Pinned:     f.Pinned,
 		})
 	}
 	for _, c := range data.Casks {
 		packages = append(packages, Package{
-			Name:     c.Name,
-			Version:  strings.Join(c.InstalledVersions, ", "),
-			Outdated: true,
+			Name:       c.Name,
+			Version:    strings.Join(c.InstalledVersions, ", "),
+			NewVersion: c.CurrentVersion,
+			Outdated:   true,
 		})
 	}
 
@@ -258,79 +354,356 @@ func ListOutdated() ([]Package, error) {
 }
 
 // Search searches for formulae matching the query
-func Search(query string) ([]SearchResult, error) {
-	output, err := runBrewCommand("search", "--formula", query)
+func Search(ctx context.Context, query string) ([]SearchResult, error) {
+	output, err := runBrewCommand(ctx, "search", "--formula", query)
 	if err != nil {
 		return nil, err
 	}
+	return parseSearchOutput(output, false), nil
+}
 
+// SearchCasks searches for casks matching the query
+func SearchCasks(ctx context.Context, query string) ([]SearchResult, error) {
+	output, err := runBrewCommand(ctx, "search", "--cask", query)
+	if err != nil {
+		return nil, err
+	}
+	return parseSearchOutput(output, true), nil
+}
+
+// parseSearchOutput parses the one-name-per-line output of brew search,
+// skipping section headers. brew appends ✔ to names that are installed.
+func parseSearchOutput(output string, isCask bool) []SearchResult {
 	var results []SearchResult
 	lines := strings.Split(strings.TrimSpace(output), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "==>") {
-			results = append(results, SearchResult{Name: line})
+		name, installed := strings.CutSuffix(line, "✔")
+		name = strings.TrimSpace(name)
+		if name != "" && !strings.HasPrefix(name, "==>") {
+			results = append(results, SearchResult{Name: name, IsCask: isCask, Installed: installed})
+		}
+	}
+	return results
+}
+
+// maxDescribedResults caps how many search results Describe looks up, since
+// brew info loads every named formula and a broad query can match hundreds.
+const maxDescribedResults = 30
+
+// Describe fills in the description, homepage and version of search results
+// with one batched brew info call per kind (formula, cask). Only the first
+// maxDescribedResults of each kind are looked up; the rest are returned
+// unchanged.
+func Describe(ctx context.Context, results []SearchResult) ([]SearchResult, error) {
+	return describe(ctx, results, runBrewCommand)
+}
+
+// describe is Describe with the brew runner passed in, so tests can fake
+// brew info. brew info fails as a whole when any one name does not resolve,
+// such as a formula renamed since the search index was built, so a failed
+// batch is retried one name at a time and the names that resolve are kept.
+func describe(ctx context.Context, results []SearchResult, run func(context.Context, ...string) (string, error)) ([]SearchResult, error) {
+	described := results
+	for _, isCask := range []bool{false, true} {
+		flag := "--formula"
+		if isCask {
+			flag = "--cask"
+		}
+		var names []string
+		for _, r := range results {
+			if r.IsCask == isCask && len(names) < maxDescribedResults {
+				names = append(names, r.Name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		output, err := run(ctx, append([]string{"info", "--json=v2", flag}, names...)...)
+		if err == nil {
+			if described, err = applySearchInfo(described, output); err != nil {
+				return described, err
+			}
+			continue
+		}
+		if ctx.Err() != nil || len(names) == 1 {
+			return described, err
+		}
+
+		resolved := false
+		for _, name := range names {
+			output, nameErr := run(ctx, "info", "--json=v2", flag, name)
+			if nameErr != nil {
+				if ctx.Err() != nil {
+					return described, nameErr
+				}
+				continue
+			}
+			if described, nameErr = applySearchInfo(described, output); nameErr != nil {
+				return described, nameErr
+			}
+			resolved = true
+		}
+		if !resolved {
+			return described, err
+		}
+	}
+	return described, nil
+}
+
+// applySearchInfo merges brew info --json=v2 output into search results,
+// matching formulae by name and casks by token, and returns a new slice.
+func applySearchInfo(results []SearchResult, jsonData string) ([]SearchResult, error) {
+	var data struct {
+		Formulae []struct {
+			Name     string `json:"name"`
+			Desc     string `json:"desc"`
+			Homepage string `json:"homepage"`
+			Versions struct {
+				Stable string `json:"stable"`
+			} `json:"versions"`
+		} `json:"formulae"`
+		Casks []struct {
+			Token    string `json:"token"`
+			Desc     string `json:"desc"`
+			Homepage string `json:"homepage"`
+			Version  string `json:"version"`
+		} `json:"casks"`
+	}
+
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		return results, &Error{Message: fmt.Sprintf("Failed to parse JSON: %v", err)}
+	}
+
+	described := make([]SearchResult, len(results))
+	copy(described, results)
+
+	type key struct {
+		name   string
+		isCask bool
+	}
+	index := make(map[key]int, len(described))
+	for i, r := range described {
+		index[key{r.Name, r.IsCask}] = i
+	}
+	for _, f := range data.Formulae {
+		if i, ok := index[key{f.Name, false}]; ok {
+			described[i].Description = f.Desc
+			described[i].Homepage = f.Homepage
+			described[i].Version = f.Versions.Stable
+		}
+	}
+	for _, c := range data.Casks {
+		if i, ok := index[key{c.Token, true}]; ok {
+			described[i].Description = c.Desc
+			described[i].Homepage = c.Homepage
+			described[i].Version = c.Version
 		}
 	}
 
-	return results, nil
+	return described, nil
 }
 
 // Install installs a package
-func Install(name string, isCask bool) error {
+func Install(ctx context.Context, name string, isCask bool) error {
 	args := []string{"install"}
 	if isCask {
 		args = append(args, "--cask")
 	}
 	args = append(args, name)
 
-	_, err := runBrewCommand(args...)
+	_, err := runBrewCommand(ctx, args...)
 	return err
 }
 
 // Uninstall removes a package
-func Uninstall(name string, isCask bool) error {
+func Uninstall(ctx context.Context, name string, isCask bool) error {
 	args := []string{"uninstall"}
 	if isCask {
 		args = append(args, "--cask")
 	}
 	args = append(args, name)
 
-	_, err := runBrewCommand(args...)
+	_, err := runBrewCommand(ctx, args...)
 	return err
 }
 
 // Upgrade upgrades a package or all packages
-func Upgrade(name string) error {
+func Upgrade(ctx context.Context, name string) error {
 	args := []string{"upgrade"}
 	if name != "" {
 		args = append(args, name)
 	}
 
-	_, err := runBrewCommand(args...)
+	_, err := runBrewCommand(ctx, args...)
 	return err
 }
 
+// BatchWorkers bounds how many brew upgrades UpgradeBatch runs at once.
+// brew locks shared dependencies and its download cache per process, so
+// parallel upgrades fail with "already locked"; they run one at a time.
+const BatchWorkers = 1
+
+// UpgradeBatch upgrades each named package, at most BatchWorkers at a
+// time, so one failure does not stop the rest. It returns one error per
+// name, in order; done, if non-nil, is called as each upgrade finishes.
+func UpgradeBatch(ctx context.Context, names []string, done func(string, error)) []error {
+	return batch.Run(names, BatchWorkers, func(name string) error {
+		if name == "" {
+			return &Error{Message: "No package specified to upgrade"}
+		}
+		return Upgrade(ctx, name)
+	}, done)
+}
+
+// PreviewUpgrade returns what upgrading names would do, from
+// `brew upgrade --dry-run`. Dependencies brew would pull in are included.
+func PreviewUpgrade(ctx context.Context, names []string) (preview.Plan, error) {
+	plan := preview.Plan{}
+	for _, name := range names {
+		plan.Commands = append(plan.Commands, "brew upgrade "+name)
+	}
+
+	output, err := runBrewReadCommand(ctx, append([]string{"upgrade", "--dry-run"}, names...)...)
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = parseDryRun(output)
+	return plan, nil
+}
+
+// PreviewInstall returns what installing name would do, from
+// `brew install --dry-run`, including the dependencies it adds
+func PreviewInstall(ctx context.Context, name string, isCask bool) (preview.Plan, error) {
+	kind, command := "--formula", "brew install "+name
+	if isCask {
+		kind, command = "--cask", "brew install --cask "+name
+	}
+	plan := preview.Plan{Commands: []string{command}}
+
+	output, err := runBrewReadCommand(ctx, "install", "--dry-run", kind, name)
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = parseDryRun(output)
+	return plan, nil
+}
+
+// parseDryRun reads the "==> Would install/upgrade ..." sections brew
+// prints under --dry-run. Entries are either "name old -> new" lines or
+// space-separated names.
+func parseDryRun(output string) []preview.Change {
+	var changes []preview.Change
+	var action preview.Action
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if header, ok := strings.CutPrefix(line, "==> "); ok {
+			switch {
+			case strings.HasPrefix(header, "Would install"):
+				action = preview.ActionInstall
+			case strings.HasPrefix(header, "Would upgrade"):
+				action = preview.ActionUpgrade
+			case strings.HasPrefix(header, "Would uninstall"), strings.HasPrefix(header, "Would remove"):
+				action = preview.ActionRemove
+			default:
+				action = ""
+			}
+			continue
+		}
+		if action == "" || line == "" {
+			continue
+		}
+
+		if name, versions, ok := strings.Cut(line, " "); ok && strings.Contains(versions, " -> ") {
+			from, to, _ := strings.Cut(versions, " -> ")
+			changes = append(changes, preview.Change{Name: name, Action: action, From: strings.TrimSpace(from), To: strings.TrimSpace(to)})
+			continue
+		}
+		for _, name := range strings.Fields(line) {
+			changes = append(changes, preview.Change{Name: name, Action: action})
+		}
+	}
+	return changes
+}
+
+// Dependency is one entry of a dependency tree. Depth 0 is a direct
+// dependency of the package asked about, 1 a dependency of that, and so on.
+type Dependency struct {
+	Name  string
+	Depth int
+}
+
+// Deps returns the installed dependency tree of a formula or cask, in
+// brew's depth-first order
+func Deps(ctx context.Context, name string, isCask bool) ([]Dependency, error) {
+	args := []string{"deps", "--tree", "--installed"}
+	if isCask {
+		args = append(args, "--cask")
+	}
+	args = append(args, name)
+
+	output, err := runBrewCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDepsTree(output), nil
+}
+
+// parseDepsTree reads brew deps --tree output. The first line is the
+// package itself; each dependency line is indented four columns per level
+// ("│   " or "    ") ahead of a "├── " or "└── " branch.
+func parseDepsTree(output string) []Dependency {
+	var deps []Dependency
+	for _, line := range strings.Split(output, "\n") {
+		idx := strings.Index(line, "── ")
+		if idx < 0 {
+			continue
+		}
+		// The branch character sits just before "── "
+		prefix := []rune(line[:idx])
+		if len(prefix) == 0 {
+			continue
+		}
+		name := strings.TrimSpace(line[idx+len("── "):])
+		if name == "" {
+			continue
+		}
+		deps = append(deps, Dependency{Name: name, Depth: (len(prefix) - 1) / 4})
+	}
+	return deps
+}
+
+// Uses returns the installed packages that depend directly on the formula
+// name. brew uninstall refuses to remove a formula while any remain.
+func Uses(ctx context.Context, name string) ([]string, error) {
+	output, err := runBrewCommand(ctx, "uses", "--installed", name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
 // Update updates Homebrew itself
-func Update() error {
-	_, err := runBrewCommand("update")
+func Update(ctx context.Context) error {
+	_, err := runBrewCommand(ctx, "update")
 	return err
 }
 
 // Pin pins a package
-func Pin(name string) error {
-	_, err := runBrewCommand("pin", name)
+func Pin(ctx context.Context, name string) error {
+	_, err := runBrewCommand(ctx, "pin", name)
 	return err
 }
 
 // Unpin unpins a package
-func Unpin(name string) error {
-	_, err := runBrewCommand("unpin", name)
+func Unpin(ctx context.Context, name string) error {
+	_, err := runBrewCommand(ctx, "unpin", name)
 	return err
 }
 
 // BundleDump dumps installed packages to a Brewfile
-func BundleDump(path string, force bool) error {
+func BundleDump(ctx context.Context, path string, force bool) error {
 	args := []string{"bundle", "dump"}
 	if path != "" {
 		args = append(args, "--file="+path)
@@ -339,22 +712,28 @@ func BundleDump(path string, force bool) error {
 		args = append(args, "--force")
 	}
 
-	_, err := runBrewCommand(args...)
+	_, err := runBrewCommand(ctx, args...)
 	return err
 }
 
+// BundleDumpContent returns the Brewfile for the installed packages
+// without writing a file. It only reads, so it runs under dry-run too.
+func BundleDumpContent(ctx context.Context) (string, error) {
+	return runBrewReadCommand(ctx, "bundle", "dump", "--file=-")
+}
+
 // BundleInstall installs packages from a Brewfile
-func BundleInstall(path string) error {
+func BundleInstall(ctx context.Context, path string) error {
 	args := []string{"bundle", "install"}
 	if path != "" {
 		args = append(args, "--file="+path)
 	}
 
-	_, err := runBrewCommand(args...)
+	_, err := runBrewCommand(ctx, args...)
 	return err
 }
 
 // Cleanup removes old versions, outdated downloads, and clears cache
-func Cleanup() (string, error) {
-	return runBrewCommand("cleanup")
+func Cleanup(ctx context.Context) (string, error) {
+	return runBrewCommand(ctx, "cleanup")
 }
@@ -0,0 +1,233 @@
+package homebrew
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/frostyard/chairlift/internal/preview"
+)
+
+const searchInfoJSON = `{
+  "formulae": [
+    {"name": "wget", "desc": "Internet file retriever", "homepage": "https://www.gnu.org/software/wget/", "versions": {"stable": "1.25.0"}},
+    {"name": "wget2", "desc": "Successor of GNU Wget", "homepage": "https://gitlab.com/gnuwget/wget2", "versions": {"stable": "2.2.0"}}
+  ],
+  "casks": [
+    {"token": "wget", "desc": "Cask of the same name", "homepage": "https://example.com/wget", "version": "9.9"}
+  ]
+}`
+
+func TestApplySearchInfo(t *testing.T) {
+	results := []SearchResult{{Name: "wget"}, {Name: "wget2"}, {Name: "wgetpaste"}, {Name: "wget", IsCask: true}}
+
+	got, err := applySearchInfo(results, searchInfoJSON)
+	if err != nil {
+		t.Fatalf("applySearchInfo: %v", err)
+	}
+	want := []SearchResult{
+		{Name: "wget", Description: "Internet file retriever", Homepage: "https://www.gnu.org/software/wget/", Version: "1.25.0"},
+		{Name: "wget2", Description: "Successor of GNU Wget", Homepage: "https://gitlab.com/gnuwget/wget2", Version: "2.2.0"},
+		{Name: "wgetpaste"},
+		{Name: "wget", IsCask: true, Description: "Cask of the same name", Homepage: "https://example.com/wget", Version: "9.9"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applySearchInfo() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// The input slice is left untouched so a failed lookup can fall back to it.
+	if results[0].Description != "" {
+		t.Errorf("applySearchInfo modified its input: %+v", results[0])
+	}
+}
+
+func TestApplySearchInfoBadJSON(t *testing.T) {
+	results := []SearchResult{{Name: "wget"}}
+	got, err := applySearchInfo(results, "not json")
+	if err == nil {
+		t.Fatal("applySearchInfo(bad json) = nil error, want one")
+	}
+	if !reflect.DeepEqual(got, results) {
+		t.Errorf("applySearchInfo(bad json) = %+v, want the input back", got)
+	}
+}
+
+// TestDescribeFallsBackPerName checks that one name brew info cannot
+// resolve costs only its own description, not the whole batch's.
+func TestDescribeFallsBackPerName(t *testing.T) {
+	info := map[string]string{
+		"wget":   `{"formulae": [{"name": "wget", "desc": "Internet file retriever", "versions": {"stable": "1.25.0"}}], "casks": []}`,
+		"wget2":  `{"formulae": [{"name": "wget2", "desc": "Successor of GNU Wget", "versions": {"stable": "2.2.0"}}], "casks": []}`,
+		"iterm2": `{"formulae": [], "casks": [{"token": "iterm2", "desc": "Terminal emulator", "version": "3.5.14"}]}`,
+	}
+	var calls [][]string
+	run := func(_ context.Context, args ...string) (string, error) {
+		calls = append(calls, args)
+		names := args[3:]
+		if len(names) != 1 {
+			for _, name := range names {
+				if _, ok := info[name]; !ok {
+					return "", &Error{Message: "No available formula with the name \"" + name + "\""}
+				}
+			}
+			return info[names[0]], nil
+		}
+		out, ok := info[names[0]]
+		if !ok {
+			return "", &Error{Message: "No available formula with the name \"" + names[0] + "\""}
+		}
+		return out, nil
+	}
+
+	results := []SearchResult{{Name: "wget"}, {Name: "wget-renamed"}, {Name: "wget2"}, {Name: "iterm2", IsCask: true}}
+	got, err := describe(context.Background(), results, run)
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	want := []SearchResult{
+		{Name: "wget", Description: "Internet file retriever", Version: "1.25.0"},
+		{Name: "wget-renamed"},
+		{Name: "wget2", Description: "Successor of GNU Wget", Version: "2.2.0"},
+		{Name: "iterm2", IsCask: true, Description: "Terminal emulator", Version: "3.5.14"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describe() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Formulae and casks are never queried together
+	for _, args := range calls {
+		if args[2] == "--cask" && !reflect.DeepEqual(args[3:], []string{"iterm2"}) {
+			t.Errorf("cask lookup %v, want only iterm2", args)
+		}
+	}
+	if len(calls) != 5 {
+		t.Errorf("describe ran brew %d times, want 5 (batch, 3 retries, casks): %v", len(calls), calls)
+	}
+}
+
+// TestDescribeAllFail checks that a brew that resolves nothing reports the
+// batch's error.
+func TestDescribeAllFail(t *testing.T) {
+	run := func(context.Context, ...string) (string, error) {
+		return "", &Error{Message: "brew is broken"}
+	}
+	results := []SearchResult{{Name: "wget"}, {Name: "wget2"}}
+	got, err := describe(context.Background(), results, run)
+	if err == nil || err.Error() != "brew is broken" {
+		t.Errorf("describe() error = %v, want the batch error", err)
+	}
+	if !reflect.DeepEqual(got, results) {
+		t.Errorf("describe() = %+v, want the input back", got)
+	}
+}
+
+func TestParseSearchOutput(t *testing.T) {
+	output := "==> Formulae\nwget ✔\nwget2\n\n"
+
+	got := parseSearchOutput(output, false)
+	want := []SearchResult{{Name: "wget", Installed: true}, {Name: "wget2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSearchOutput(formula) = %+v, want %+v", got, want)
+	}
+
+	got = parseSearchOutput("firefox\n", true)
+	want = []SearchResult{{Name: "firefox", IsCask: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSearchOutput(cask) = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseOutdatedJSON(t *testing.T) {
+	output := `{
+  "formulae": [
+    {"name": "wget", "installed_versions": ["1.24.5"], "current_version": "1.25.0", "pinned": false},
+    {"name": "node", "installed_versions": ["22.1.0", "22.2.0"], "current_version": "23.0.0", "pinned": true}
+  ],
+  "casks": [
+    {"name": "firefox", "installed_versions": ["130.0"], "current_version": "131.0"}
+  ]
+}`
+
+	got, err := parseOutdatedJSON(output)
+	if err != nil {
+		t.Fatalf("parseOutdatedJSON: %v", err)
+	}
+	want := []Package{
+		{Name: "wget", Version: "1.24.5", NewVersion: "1.25.0", Outdated: true},
+		{Name: "node", Version: "22.1.0, 22.2.0", NewVersion: "23.0.0", Outdated: true, Pinned: true},
+		{Name: "firefox", Version: "130.0", NewVersion: "131.0", Outdated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOutdatedJSON() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseDryRun(t *testing.T) {
+	output := `==> Would upgrade 2 outdated packages:
+wget 1.21.3 -> 1.21.4
+jq 1.6 -> 1.7.1
+==> Would install 2 dependencies for jq:
+oniguruma libyaml
+==> Fetching downloads for: jq
+`
+	want := []preview.Change{
+		{Name: "wget", Action: preview.ActionUpgrade, From: "1.21.3", To: "1.21.4"},
+		{Name: "jq", Action: preview.ActionUpgrade, From: "1.6", To: "1.7.1"},
+		{Name: "oniguruma", Action: preview.ActionInstall},
+		{Name: "libyaml", Action: preview.ActionInstall},
+	}
+	if got := parseDryRun(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDryRun() = %+v, want %+v", got, want)
+	}
+
+	if got := parseDryRun("Warning: No available formula with the name \"nope\".\n"); len(got) != 0 {
+		t.Errorf("parseDryRun(no sections) = %+v, want none", got)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	keg := filepath.Join(t.TempDir(), "jq")
+	if err := os.MkdirAll(filepath.Join(keg, "1.7.1", "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(keg, "1.7.1", "bin", "jq"), make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(keg, "1.7.1", "README"), make([]byte, 24), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A symlink is not followed, so its target is counted once.
+	if err := os.Symlink(filepath.Join(keg, "1.7.1", "bin", "jq"), filepath.Join(keg, "jq-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := dirSize(keg); got != 1024 {
+		t.Errorf("dirSize() = %d, want 1024", got)
+	}
+	if got := dirSize(filepath.Join(keg, "missing")); got != 0 {
+		t.Errorf("dirSize(missing) = %d, want 0", got)
+	}
+}
+
+func TestParseDepsTree(t *testing.T) {
+	output := "ffmpeg\n" +
+		"├── aom\n" +
+		"│   └── jpeg-xl\n" +
+		"│       └── brotli\n" +
+		"└── x264\n"
+
+	want := []Dependency{
+		{Name: "aom", Depth: 0},
+		{Name: "jpeg-xl", Depth: 1},
+		{Name: "brotli", Depth: 2},
+		{Name: "x264", Depth: 0},
+	}
+	if got := parseDepsTree(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDepsTree() = %+v, want %+v", got, want)
+	}
+	if got := parseDepsTree("jq\n"); got != nil {
+		t.Errorf("parseDepsTree(no deps) = %+v, want nil", got)
+	}
+}
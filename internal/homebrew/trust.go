@@ -1,6 +1,7 @@
 package homebrew
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -132,8 +133,8 @@ func installedCasksByTap(caskroomDir string) map[string][]string {
 }
 
 // brewPrefix returns Homebrew's installation prefix.
-func brewPrefix() (string, error) {
-	output, err := runBrewCommand("--prefix")
+func brewPrefix(ctx context.Context) (string, error) {
+	output, err := runBrewCommand(ctx, "--prefix")
 	if err != nil {
 		return "", err
 	}
@@ -142,8 +143,8 @@ func brewPrefix() (string, error) {
 
 // ListUntrustedTaps returns untrusted taps that have at least one package
 // installed, with qualified package names ready for `brew trust`.
-func ListUntrustedTaps() ([]UntrustedTap, error) {
-	output, err := runBrewCommand("tap-info", "--installed", "--json")
+func ListUntrustedTaps(ctx context.Context) ([]UntrustedTap, error) {
+	output, err := runBrewCommand(ctx, "tap-info", "--installed", "--json")
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +156,7 @@ func ListUntrustedTaps() ([]UntrustedTap, error) {
 		return nil, nil
 	}
 
-	prefix, err := brewPrefix()
+	prefix, err := brewPrefix(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -202,16 +203,16 @@ func isUntrustedTapMessage(s string) bool {
 
 // TrustPackages trusts every installed package from the given tap using
 // `brew trust`. Trust is per-user (~/.homebrew/trust.json); no root needed.
-func TrustPackages(tap UntrustedTap) error {
+func TrustPackages(ctx context.Context, tap UntrustedTap) error {
 	if len(tap.Formulae) > 0 {
 		args := append([]string{"trust", "--formula"}, tap.Formulae...)
-		if _, err := runBrewCommand(args...); err != nil {
+		if _, err := runBrewCommand(ctx, args...); err != nil {
 			return err
 		}
 	}
 	if len(tap.Casks) > 0 {
 		args := append([]string{"trust", "--cask"}, tap.Casks...)
-		if _, err := runBrewCommand(args...); err != nil {
+		if _, err := runBrewCommand(ctx, args...); err != nil {
 			return err
 		}
 	}
@@ -1,6 +1,7 @@
 package homebrew
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -142,7 +143,7 @@ func TestIsUntrustedTapMessage(t *testing.T) {
 func TestTrustPackagesDryRun(t *testing.T) {
 	SetDryRun(true)
 	defer SetDryRun(false)
-	err := TrustPackages(UntrustedTap{
+	err := TrustPackages(context.Background(), UntrustedTap{
 		Name:     "multica-ai/tap",
 		Formulae: []string{"multica-ai/tap/multica"},
 	})
@@ -0,0 +1,338 @@
+// Package manifest exports what ChairLift manages on a machine — Flatpak
+// applications, the Homebrew Brewfile and enabled features — to a single
+// file, and applies such a file to reproduce the setup on another machine.
+//
+// Applying reuses updateall's steps and Reporter, so the Maintenance page
+// shows an import the same way the Updates page shows Update Everything.
+// Like updateall, it is free of any puregotk/GTK import so it can be
+// unit-tested on a headless host.
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/updateall"
+	"github.com/frostyard/chairlift/internal/updex"
+)
+
+// FormatVersion is written to every manifest. Parse rejects manifests from
+// a newer ChairLift, whose fields it might silently drop.
+const FormatVersion = 1
+
+// DefaultName is the file name offered when exporting
+const DefaultName = "chairlift-manifest.json"
+
+// Flatpak is one installed Flatpak application
+type Flatpak struct {
+	ID     string `json:"id"`
+	Origin string `json:"origin,omitempty"`
+	User   bool   `json:"user"`
+}
+
+// Manifest is the exported state of a machine
+type Manifest struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Hostname string    `json:"hostname,omitempty"`
+	Flatpaks []Flatpak `json:"flatpaks,omitempty"`
+	Brewfile string    `json:"brewfile,omitempty"`
+	Features []string  `json:"features,omitempty"`
+}
+
+// Sources says which package managers are available on this system. Export
+// leaves out the unavailable ones; Steps reports their entries as failed.
+type Sources struct {
+	Flatpak  bool
+	Homebrew bool
+	Features bool
+}
+
+// Export collects the current state of the available sources
+func Export(ctx context.Context, sources Sources) (*Manifest, error) {
+	m := &Manifest{Version: FormatVersion, Created: time.Now().UTC()}
+	m.Hostname, _ = os.Hostname()
+
+	if sources.Flatpak {
+		for _, user := range []bool{true, false} {
+			var apps []flatpak.Application
+			var err error
+			if user {
+				apps, err = flatpak.ListUserApplications(ctx)
+			} else {
+				apps, err = flatpak.ListSystemApplications(ctx)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("listing Flatpak applications: %w", err)
+			}
+			for _, app := range apps {
+				m.Flatpaks = append(m.Flatpaks, Flatpak{ID: app.ApplicationID, Origin: app.Origin, User: user})
+			}
+		}
+	}
+
+	if sources.Homebrew {
+		brewfile, err := homebrew.BundleDumpContent(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dumping Brewfile: %w", err)
+		}
+		m.Brewfile = brewfile
+	}
+
+	if sources.Features {
+		features, err := updex.ListFeatures(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing features: %w", err)
+		}
+		for _, feature := range features {
+			if feature.Enabled {
+				m.Features = append(m.Features, feature.Name)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Marshal encodes m as indented JSON, so the file can be read and edited by
+// hand before it is applied
+func Marshal(m *Manifest) ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Parse decodes a manifest and checks its format version
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("not a ChairLift manifest: %w", err)
+	}
+	if m.Version == 0 {
+		return nil, errors.New("not a ChairLift manifest: no version")
+	}
+	if m.Version > FormatVersion {
+		return nil, fmt.Errorf("manifest version %d is newer than this ChairLift supports (%d)", m.Version, FormatVersion)
+	}
+	for _, app := range m.Flatpaks {
+		if app.ID == "" {
+			return nil, errors.New("manifest lists a Flatpak without an ID")
+		}
+	}
+	return &m, nil
+}
+
+// Write saves m to path
+func Write(m *Manifest, path string) error {
+	data, err := Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Read loads and parses the manifest at path
+func Read(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Summary describes what a manifest contains, for the import confirmation
+func (m *Manifest) Summary() string {
+	brewfile := "no Brewfile"
+	if m.Brewfile != "" {
+		brewfile = "a Brewfile"
+	}
+	from := ""
+	if m.Hostname != "" {
+		from = fmt.Sprintf(" from %s", m.Hostname)
+	}
+	return fmt.Sprintf("%d Flatpak applications, %s and %d features%s",
+		len(m.Flatpaks), brewfile, len(m.Features), from)
+}
+
+// Steps returns the steps that bring this system in line with m, for
+// updateall.Run. Installed Flatpaks and enabled features are left alone;
+// nothing is removed. Entries for an unavailable source get a step that
+// fails, so an import never silently drops part of the manifest.
+func Steps(m *Manifest, sources Sources) []updateall.Step {
+	var steps []updateall.Step
+	if len(m.Flatpaks) > 0 {
+		step := updateall.Step{Name: "Flatpak applications", Run: applyFlatpaks(m.Flatpaks)}
+		if !sources.Flatpak {
+			step.Run = unavailable("Flatpak")
+		}
+		steps = append(steps, step)
+	}
+	if m.Brewfile != "" {
+		step := updateall.Step{Name: "Homebrew packages", Run: applyBrewfile(m.Brewfile)}
+		if !sources.Homebrew {
+			step.Run = unavailable("Homebrew")
+		}
+		steps = append(steps, step)
+	}
+	if len(m.Features) > 0 {
+		step := updateall.Step{Name: "Features", Run: applyFeatures(m.Features)}
+		if !sources.Features {
+			step.Run = unavailable("Features")
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func unavailable(source string) func(context.Context, func(string)) error {
+	return func(context.Context, func(string)) error {
+		return fmt.Errorf("%s is not available on this system", source)
+	}
+}
+
+// missingFlatpaks returns the wanted applications not already installed in
+// the same installation
+func missingFlatpaks(want []Flatpak, user, system []flatpak.Application) []Flatpak {
+	installed := make(map[Flatpak]bool)
+	for _, app := range user {
+		installed[Flatpak{ID: app.ApplicationID, User: true}] = true
+	}
+	for _, app := range system {
+		installed[Flatpak{ID: app.ApplicationID}] = true
+	}
+
+	var missing []Flatpak
+	for _, app := range want {
+		if !installed[Flatpak{ID: app.ID, User: app.User}] {
+			missing = append(missing, app)
+		}
+	}
+	return missing
+}
+
+func applyFlatpaks(want []Flatpak) func(context.Context, func(string)) error {
+	return func(ctx context.Context, progress func(string)) error {
+		progress("Checking installed applications...")
+		user, err := flatpak.ListUserApplications(ctx)
+		if err != nil {
+			return err
+		}
+		system, err := flatpak.ListSystemApplications(ctx)
+		if err != nil {
+			return err
+		}
+
+		missing := missingFlatpaks(want, user, system)
+		var errs []error
+		for i, app := range missing {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			progress(fmt.Sprintf("Installing %s (%d of %d)...", app.ID, i+1, len(missing)))
+			if err := flatpak.InstallFrom(ctx, app.Origin, app.ID, app.User); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", app.ID, err))
+			}
+		}
+		if len(missing) == 0 {
+			progress("All applications already installed")
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func applyBrewfile(brewfile string) func(context.Context, func(string)) error {
+	return func(ctx context.Context, progress func(string)) error {
+		dir, err := os.MkdirTemp("", "chairlift-manifest-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "Brewfile")
+		if err := os.WriteFile(path, []byte(brewfile), 0o600); err != nil {
+			return err
+		}
+
+		eventCh := make(chan homebrew.BundleEvent)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- homebrew.BundleInstallStreaming(ctx, path, eventCh)
+		}()
+		statuses := make(map[string]homebrew.BundleStatus)
+		for event := range eventCh {
+			if event.Package != "" {
+				statuses[event.Package] = event.Status
+			}
+			progress(event.Line)
+		}
+		if err := <-errCh; err != nil {
+			return err
+		}
+		if _, _, failed := homebrew.BundleSummary(statuses); len(failed) > 0 {
+			return fmt.Errorf("%d failed: %s", len(failed), strings.Join(failed, ", "))
+		}
+		return nil
+	}
+}
+
+// featuresToEnable returns the wanted features that are not enabled yet,
+// and the ones this system does not offer at all
+func featuresToEnable(want []string, have []updex.Feature) (enable, unknown []string) {
+	enabled := make(map[string]bool)
+	for _, feature := range have {
+		enabled[feature.Name] = feature.Enabled
+	}
+	for _, name := range want {
+		isEnabled, known := enabled[name]
+		switch {
+		case !known:
+			unknown = append(unknown, name)
+		case !isEnabled:
+			enable = append(enable, name)
+		}
+	}
+	return enable, unknown
+}
+
+func applyFeatures(want []string) func(context.Context, func(string)) error {
+	return func(ctx context.Context, progress func(string)) error {
+		progress("Checking features...")
+		have, err := updex.ListFeatures(ctx)
+		if err != nil {
+			return err
+		}
+
+		enable, unknown := featuresToEnable(want, have)
+		var errs []error
+		for _, name := range enable {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			progress(fmt.Sprintf("Enabling %s...", name))
+			if err := updex.EnableFeature(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+		if len(enable) > 0 && len(errs) < len(enable) {
+			// Enabling only marks features for download; fetch them now.
+			progress("Downloading features...")
+			if err := updex.UpdateFeatures(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(unknown) > 0 {
+			errs = append(errs, fmt.Errorf("not offered on this system: %s", strings.Join(unknown, ", ")))
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,129 @@
+package manifest
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/updex"
+)
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	m := &Manifest{
+		Version:  FormatVersion,
+		Created:  time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+		Hostname: "old-laptop",
+		Flatpaks: []Flatpak{
+			{ID: "org.gnome.Calculator", Origin: "flathub", User: true},
+			{ID: "org.mozilla.firefox", Origin: "flathub"},
+		},
+		Brewfile: "brew \"jq\"\ncask \"font-fira-code\"\n",
+		Features: []string{"devtools"},
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultName)
+	if err := Write(m, path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Read() = %+v, want %+v", got, m)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "minimal", data: `{"version": 1}`},
+		{name: "not json", data: `brew "jq"`, wantErr: "not a ChairLift manifest"},
+		{name: "no version", data: `{"flatpaks": []}`, wantErr: "no version"},
+		{name: "newer version", data: `{"version": 99}`, wantErr: "newer than this ChairLift supports"},
+		{name: "flatpak without id", data: `{"version": 1, "flatpaks": [{"origin": "flathub"}]}`, wantErr: "without an ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	m := &Manifest{Flatpaks: make([]Flatpak, 3), Brewfile: "brew \"jq\"\n", Features: []string{"a"}, Hostname: "box"}
+	if got, want := m.Summary(), "3 Flatpak applications, a Brewfile and 1 features from box"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	empty := &Manifest{}
+	if got, want := empty.Summary(), "0 Flatpak applications, no Brewfile and 0 features"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+// TestMissingFlatpaks checks that an app installed in the other
+// installation still counts as missing.
+func TestMissingFlatpaks(t *testing.T) {
+	want := []Flatpak{
+		{ID: "org.gnome.Calculator", User: true},
+		{ID: "org.mozilla.firefox"},
+		{ID: "org.gimp.GIMP", User: true},
+	}
+	user := []flatpak.Application{{ApplicationID: "org.gnome.Calculator"}}
+	system := []flatpak.Application{{ApplicationID: "org.gimp.GIMP"}, {ApplicationID: "org.mozilla.firefox"}}
+
+	got := missingFlatpaks(want, user, system)
+	if !reflect.DeepEqual(got, []Flatpak{{ID: "org.gimp.GIMP", User: true}}) {
+		t.Errorf("missingFlatpaks() = %+v", got)
+	}
+}
+
+func TestFeaturesToEnable(t *testing.T) {
+	have := []updex.Feature{
+		{Name: "devtools", Enabled: true},
+		{Name: "docker"},
+		{Name: "incus"},
+	}
+	enable, unknown := featuresToEnable([]string{"devtools", "docker", "nvidia"}, have)
+	if !reflect.DeepEqual(enable, []string{"docker"}) {
+		t.Errorf("enable = %v, want [docker]", enable)
+	}
+	if !reflect.DeepEqual(unknown, []string{"nvidia"}) {
+		t.Errorf("unknown = %v, want [nvidia]", unknown)
+	}
+}
+
+// TestStepsUnavailableSource checks that entries for a missing package
+// manager become a failing step rather than being dropped.
+func TestStepsUnavailableSource(t *testing.T) {
+	m := &Manifest{Version: FormatVersion, Brewfile: "brew \"jq\"\n", Features: []string{"devtools"}}
+
+	steps := Steps(m, Sources{Features: true})
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Homebrew packages", "Features"}) {
+		t.Fatalf("step names = %v", names)
+	}
+	err := steps[0].Run(context.Background(), func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "Homebrew is not available") {
+		t.Errorf("Homebrew step error = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package network tracks whether the machine can reach the internet, so
+// the views can explain a failure as "offline" and hold back actions that
+// need a download instead of letting them fail with a command's own error.
+//
+// Connectivity comes from NetworkManager (`nmcli networking connectivity`)
+// when it is installed, and otherwise from a TCP connection to a package
+// host. Until the first check finishes the machine is assumed online.
+package network
+
+import (
+	"context"
+	"net"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// PollInterval is how often Watch re-checks connectivity
+const PollInterval = 30 * time.Second
+
+// probeTimeout bounds one connectivity check
+const probeTimeout = 5 * time.Second
+
+// probeAddress is dialled when NetworkManager is not available. Flathub
+// serves both Flatpak metadata and downloads, so reaching it is a fair
+// stand-in for "package operations can work".
+const probeAddress = "dl.flathub.org:443"
+
+// offline is stored inverted so the zero value means online
+var offline atomic.Bool
+
+// probe runs one check. Tests replace it.
+var probe = defaultProbe
+
+// IsOnline reports the result of the latest check
+func IsOnline() bool {
+	return !offline.Load()
+}
+
+// Check runs one connectivity check, records it for IsOnline and returns it
+func Check(ctx context.Context) bool {
+	online := probe(ctx)
+	offline.Store(!online)
+	return online
+}
+
+// Watch checks connectivity every interval until ctx is cancelled, calling
+// onChange whenever the result differs from the previous one. The first
+// check always calls onChange. It blocks, so run it in its own goroutine.
+func Watch(ctx context.Context, interval time.Duration, onChange func(online bool)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	first := true
+	var last bool
+	for {
+		online := Check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if first || online != last {
+			onChange(online)
+		}
+		first, last = false, online
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// defaultProbe asks NetworkManager, falling back to dialling probeAddress
+func defaultProbe(parent context.Context) bool {
+	ctx, cancel := context.WithTimeout(parent, probeTimeout)
+	defer cancel()
+
+	if _, err := exec.LookPath("nmcli"); err == nil {
+		output, err := exec.CommandContext(ctx, "nmcli", "networking", "connectivity").Output()
+		if err == nil {
+			if online, known := parseConnectivity(string(output)); known {
+				return online
+			}
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", probeAddress)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// parseConnectivity reads `nmcli networking connectivity` output. known is
+// false for "unknown", which NetworkManager reports when its own checking
+// is disabled.
+func parseConnectivity(output string) (online, known bool) {
+	switch strings.TrimSpace(output) {
+	case "full":
+		return true, true
+	case "none", "limited", "portal":
+		// limited and portal mean a network without internet access, or
+		// one behind a captive login page; downloads fail on both
+		return false, true
+	default:
+		return false, false
+	}
+}
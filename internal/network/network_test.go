@@ -0,0 +1,100 @@
+package network
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseConnectivity(t *testing.T) {
+	tests := []struct {
+		output string
+		online bool
+		known  bool
+	}{
+		{"full\n", true, true},
+		{"none\n", false, true},
+		{"limited\n", false, true},
+		{"portal\n", false, true},
+		{"unknown\n", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		online, known := parseConnectivity(tt.output)
+		if online != tt.online || known != tt.known {
+			t.Errorf("parseConnectivity(%q) = %v, %v, want %v, %v", tt.output, online, known, tt.online, tt.known)
+		}
+	}
+}
+
+// setProbe replaces probe for one test, restoring it and the recorded
+// state afterwards
+func setProbe(t *testing.T, fn func(context.Context) bool) {
+	t.Helper()
+	saved := probe
+	probe = fn
+	t.Cleanup(func() {
+		probe = saved
+		offline.Store(false)
+	})
+}
+
+func TestCheckRecordsResult(t *testing.T) {
+	if !IsOnline() {
+		t.Fatal("IsOnline() = false before any check, want true")
+	}
+
+	setProbe(t, func(context.Context) bool { return false })
+	if Check(context.Background()) {
+		t.Error("Check() = true, want false")
+	}
+	if IsOnline() {
+		t.Error("IsOnline() = true after a failed check, want false")
+	}
+}
+
+// TestWatchReportsChanges checks that Watch reports the first result and
+// then only transitions, not every poll.
+func TestWatchReportsChanges(t *testing.T) {
+	results := []bool{true, true, false, false, true}
+	var calls atomic.Int32
+	setProbe(t, func(context.Context) bool {
+		i := int(calls.Add(1)) - 1
+		return results[min(i, len(results)-1)]
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan bool, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(ctx, time.Millisecond, func(online bool) {
+			changes <- online
+		})
+	}()
+	// Stop the watcher before setProbe's cleanup restores the probe
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	want := []bool{true, false, true}
+	for i, w := range want {
+		select {
+		case got := <-changes:
+			if got != w {
+				t.Fatalf("change %d = %v, want %v", i, got, w)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for change %d", i)
+		}
+	}
+
+	// The probe keeps reporting online; no further change may arrive
+	select {
+	case got := <-changes:
+		t.Errorf("unexpected change %v after the last transition", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
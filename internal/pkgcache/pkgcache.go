@@ -0,0 +1,133 @@
+// Package pkgcache keeps the last result of a package list command (brew
+// outdated, flatpak list, ...) so the views can show it instantly and
+// refresh it in the background. The Homebrew and Flatpak wrappers call
+// InvalidateAll after every state-changing command, so a list is never
+// served from before an install, upgrade or removal.
+package pkgcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a cached list is served without a refresh
+const DefaultTTL = 5 * time.Minute
+
+var (
+	registryMu sync.Mutex
+	registry   []invalidator
+)
+
+type invalidator interface {
+	Invalidate()
+}
+
+// InvalidateAll marks every cache created by New as stale
+func InvalidateAll() {
+	registryMu.Lock()
+	caches := append([]invalidator(nil), registry...)
+	registryMu.Unlock()
+
+	for _, c := range caches {
+		c.Invalidate()
+	}
+}
+
+// Cache holds the last value returned by fetch and when it was fetched
+type Cache[T any] struct {
+	ttl   time.Duration
+	fetch func(context.Context) (T, error)
+	now   func() time.Time
+
+	mu         sync.Mutex
+	value      T
+	fetched    time.Time
+	ok         bool
+	generation int
+
+	// fetchMu serialises fetches, so concurrent loads share one command
+	fetchMu sync.Mutex
+}
+
+// New returns an empty cache that fills itself with fetch. It is
+// registered for InvalidateAll.
+func New[T any](ttl time.Duration, fetch func(context.Context) (T, error)) *Cache[T] {
+	c := &Cache[T]{ttl: ttl, fetch: fetch, now: time.Now}
+
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+
+	return c
+}
+
+// Peek returns the cached value, when it was fetched, and whether there
+// is one, without fetching
+func (c *Cache[T]) Peek() (T, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value, c.fetched, c.ok
+}
+
+// Fresh reports whether a cached value exists and is younger than the TTL
+func (c *Cache[T]) Fresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ok && c.now().Sub(c.fetched) < c.ttl
+}
+
+// Invalidate marks the cached value stale. It is still returned by Peek
+// until the next fetch replaces it.
+func (c *Cache[T]) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetched = time.Time{}
+	c.generation++
+}
+
+// Get returns the cached value if it is fresh, and fetches otherwise.
+// Errors are returned but not cached.
+func (c *Cache[T]) Get(ctx context.Context) (T, error) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// Another caller may have fetched while this one waited
+	if c.Fresh() {
+		value, _, _ := c.Peek()
+		return value, nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := c.fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// An invalidation during the fetch means the command may have run
+	// before a change; keep the result out of the cache.
+	if generation == c.generation {
+		c.value = value
+		c.fetched = c.now()
+		c.ok = true
+	}
+	return value, nil
+}
+
+// Load calls show with the cached value, if any, straight away. If that
+// value is missing or stale it then fetches and calls show again with the
+// result. show runs on the calling goroutine.
+func (c *Cache[T]) Load(ctx context.Context, show func(T, error)) {
+	if value, _, ok := c.Peek(); ok {
+		show(value, nil)
+		if c.Fresh() {
+			return
+		}
+	}
+	show(c.Get(ctx))
+}
@@ -0,0 +1,109 @@
+package pkgcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newCounterCache returns a cache whose fetch yields 1, 2, 3, ..., its
+// fake clock, which tests move forward by hand, and the fetch count
+func newCounterCache(ttl time.Duration) (*Cache[int], *time.Time, *int) {
+	calls := 0
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(ttl, func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	})
+	c.now = func() time.Time { return clock }
+	return c, &clock, &calls
+}
+
+// TestGetServesFreshValue checks that a value is reused within the TTL
+// and fetched again after it.
+func TestGetServesFreshValue(t *testing.T) {
+	c, clock, calls := newCounterCache(time.Minute)
+	ctx := context.Background()
+
+	if v, _ := c.Get(ctx); v != 1 {
+		t.Fatalf("first Get = %d, want 1", v)
+	}
+	*clock = clock.Add(30 * time.Second)
+	if v, _ := c.Get(ctx); v != 1 || *calls != 1 {
+		t.Errorf("Get within TTL = %d after %d fetches, want 1 after 1", v, *calls)
+	}
+	*clock = clock.Add(time.Minute)
+	if v, _ := c.Get(ctx); v != 2 {
+		t.Errorf("Get after TTL = %d, want 2", v)
+	}
+}
+
+// TestInvalidateAll checks that InvalidateAll forces the next Get to fetch
+// while Peek keeps returning the old value.
+func TestInvalidateAll(t *testing.T) {
+	c, _, _ := newCounterCache(time.Hour)
+	ctx := context.Background()
+	c.Get(ctx)
+
+	InvalidateAll()
+
+	if c.Fresh() {
+		t.Error("Fresh() = true after InvalidateAll")
+	}
+	if v, _, ok := c.Peek(); !ok || v != 1 {
+		t.Errorf("Peek() = (%d, %v), want the stale 1", v, ok)
+	}
+	if v, _ := c.Get(ctx); v != 2 {
+		t.Errorf("Get after InvalidateAll = %d, want 2", v)
+	}
+}
+
+// TestGetDoesNotCacheErrors checks that a failed fetch leaves the previous
+// value in place and is retried next time.
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	fail := true
+	c := New(time.Hour, func(context.Context) ([]string, error) {
+		if fail {
+			return nil, errors.New("brew exploded")
+		}
+		return []string{"wget"}, nil
+	})
+
+	if _, err := c.Get(context.Background()); err == nil {
+		t.Fatal("Get = nil error, want the fetch error")
+	}
+	if _, _, ok := c.Peek(); ok {
+		t.Error("Peek() ok after a failed fetch")
+	}
+
+	fail = false
+	if v, err := c.Get(context.Background()); err != nil || len(v) != 1 {
+		t.Errorf("Get = (%v, %v), want [wget]", v, err)
+	}
+}
+
+// TestLoad checks that Load shows a cached value first and refreshes it
+// only when stale.
+func TestLoad(t *testing.T) {
+	c, clock, _ := newCounterCache(time.Minute)
+	ctx := context.Background()
+
+	var shown []int
+	show := func(v int, _ error) { shown = append(shown, v) }
+
+	c.Load(ctx, show) // empty: fetch once
+	c.Load(ctx, show) // fresh: cached only
+	*clock = clock.Add(2 * time.Minute)
+	c.Load(ctx, show) // stale: cached, then refreshed
+
+	want := []int{1, 1, 1, 2}
+	if len(shown) != len(want) {
+		t.Fatalf("shown = %v, want %v", shown, want)
+	}
+	for i := range want {
+		if shown[i] != want[i] {
+			t.Fatalf("shown = %v, want %v", shown, want)
+		}
+	}
+}
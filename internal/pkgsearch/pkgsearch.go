@@ -0,0 +1,226 @@
+// Package pkgsearch searches Homebrew and Flatpak concurrently and
+// normalises their results into one model, so the Applications page can
+// offer a single search box across package managers.
+package pkgsearch
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+)
+
+// Source identifies where a result comes from and how it installs
+type Source string
+
+const (
+	SourceFormula Source = "formula"
+	SourceCask    Source = "cask"
+	SourceFlatpak Source = "flatpak"
+)
+
+// Result is one package found by a search
+type Result struct {
+	// Name is the display name: the formula name, cask token, or the
+	// Flatpak application's AppStream name.
+	Name string
+	// ID is what the source installs by: the formula name or cask token,
+	// or the Flatpak application ID.
+	ID          string
+	Source      Source
+	Description string
+	Version     string
+	Homepage    string
+	Installed   bool
+	// Remote and User say where a Flatpak application installs from: the
+	// remote it was found in, and whether that remote belongs to the user
+	// installation. Remote is empty when unknown, and flatpak chooses.
+	Remote string
+	User   bool
+}
+
+// Results holds the outcome of a search per source. A source that is not
+// available on this system appears in neither map.
+type Results struct {
+	Items  map[Source][]Result
+	Errors map[Source]error
+}
+
+// searcher runs a query against one source
+type searcher func(ctx context.Context, query string) ([]Result, error)
+
+// Search queries each of sources that is available on this system
+// concurrently. Cancelling ctx stops the searches still running; they
+// report ctx's error.
+func Search(ctx context.Context, query string, sources []Source) Results {
+	available := make(map[Source]searcher)
+	if homebrew.IsInstalledCached() {
+		available[SourceFormula] = searchFormulae
+		available[SourceCask] = searchCasks
+	}
+	if flatpak.IsInstalledCached() {
+		available[SourceFlatpak] = searchFlatpak
+	}
+	searchers := make(map[Source]searcher)
+	for _, source := range sources {
+		if search, ok := available[source]; ok {
+			searchers[source] = search
+		}
+	}
+	return fanOut(ctx, query, searchers)
+}
+
+// fanOut runs each searcher in its own goroutine and collects the results.
+// One source failing does not hide the others.
+func fanOut(ctx context.Context, query string, searchers map[Source]searcher) Results {
+	results := Results{
+		Items:  make(map[Source][]Result),
+		Errors: make(map[Source]error),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for source, search := range searchers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items, err := search(ctx, query)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results.Errors[source] = err
+				return
+			}
+			results.Items[source] = items
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func searchFormulae(ctx context.Context, query string) ([]Result, error) {
+	found, err := homebrew.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return fromHomebrew(describe(ctx, found)), nil
+}
+
+func searchCasks(ctx context.Context, query string) ([]Result, error) {
+	found, err := homebrew.SearchCasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return fromHomebrew(describe(ctx, found)), nil
+}
+
+// describe adds descriptions to Homebrew results. They are a nicety; on
+// failure the bare names are kept.
+func describe(ctx context.Context, found []homebrew.SearchResult) []homebrew.SearchResult {
+	described, err := homebrew.Describe(ctx, found)
+	if err != nil {
+		log.Printf("Failed to describe search results: %v", err)
+		return found
+	}
+	return described
+}
+
+func searchFlatpak(ctx context.Context, query string) ([]Result, error) {
+	found, err := flatpak.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// flatpak search does not say what is installed, so check both
+	// installations. A failed listing only costs the Installed marks.
+	installed := make(map[string]bool)
+	for _, list := range []func(context.Context) ([]flatpak.Application, error){flatpak.ListUserApplications, flatpak.ListSystemApplications} {
+		apps, err := list(ctx)
+		if err != nil {
+			log.Printf("Failed to list installed Flatpaks for search: %v", err)
+			continue
+		}
+		for _, app := range apps {
+			installed[app.ApplicationID] = true
+		}
+	}
+
+	// Map each enabled remote to its installation, so a result installs
+	// where it was found. A name in both installations maps to the user
+	// one, which needs no privileges.
+	remotes := make(map[string]bool)
+	for _, user := range []bool{false, true} {
+		listed, err := flatpak.ListRemotes(ctx, user)
+		if err != nil {
+			log.Printf("Failed to list Flatpak remotes for search: %v", err)
+			continue
+		}
+		for _, remote := range listed {
+			if !remote.Disabled {
+				remotes[remote.Name] = user
+			}
+		}
+	}
+
+	return fromFlatpak(found, installed, remotes), nil
+}
+
+// fromHomebrew normalises Homebrew search results
+func fromHomebrew(found []homebrew.SearchResult) []Result {
+	results := make([]Result, 0, len(found))
+	for _, r := range found {
+		source := SourceFormula
+		if r.IsCask {
+			source = SourceCask
+		}
+		results = append(results, Result{
+			Name:        r.Name,
+			ID:          r.Name,
+			Source:      source,
+			Description: r.Description,
+			Version:     r.Version,
+			Homepage:    r.Homepage,
+			Installed:   r.Installed,
+		})
+	}
+	return results
+}
+
+// fromFlatpak normalises Flatpak search results, marking the application
+// IDs in installed. remotes maps each configured remote to whether it
+// belongs to the user installation.
+func fromFlatpak(found []flatpak.SearchResult, installed map[string]bool, remotes map[string]bool) []Result {
+	results := make([]Result, 0, len(found))
+	for _, r := range found {
+		remote, user := origin(r.Remotes, remotes)
+		results = append(results, Result{
+			Name:        r.Name,
+			ID:          r.ApplicationID,
+			Source:      SourceFlatpak,
+			Description: r.Description,
+			Version:     r.Version,
+			Installed:   installed[r.ApplicationID],
+			Remote:      remote,
+			User:        user,
+		})
+	}
+	return results
+}
+
+// origin picks the remote a search result installs from: the first of its
+// comma-separated remotes that is configured, and whether that remote is
+// the user installation's. With none known it returns no remote and the
+// user installation, so flatpak chooses as a plain install would.
+func origin(listed string, remotes map[string]bool) (string, bool) {
+	for _, name := range strings.Split(listed, ",") {
+		name = strings.TrimSpace(name)
+		if user, ok := remotes[name]; ok {
+			return name, user
+		}
+	}
+	return "", true
+}
@@ -0,0 +1,101 @@
+package pkgsearch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+)
+
+// TestFanOut checks that every searcher's results land under its source
+// and that one failing source does not hide the others.
+func TestFanOut(t *testing.T) {
+	boom := errors.New("boom")
+	searchers := map[Source]searcher{
+		SourceFormula: func(_ context.Context, q string) ([]Result, error) {
+			return []Result{{Name: q + "-formula", Source: SourceFormula}}, nil
+		},
+		SourceCask: func(context.Context, string) ([]Result, error) {
+			return nil, boom
+		},
+		SourceFlatpak: func(_ context.Context, q string) ([]Result, error) {
+			return []Result{{Name: q + "-app", Source: SourceFlatpak}}, nil
+		},
+	}
+
+	got := fanOut(context.Background(), "wget", searchers)
+
+	wantItems := map[Source][]Result{
+		SourceFormula: {{Name: "wget-formula", Source: SourceFormula}},
+		SourceFlatpak: {{Name: "wget-app", Source: SourceFlatpak}},
+	}
+	if !reflect.DeepEqual(got.Items, wantItems) {
+		t.Errorf("Items = %+v, want %+v", got.Items, wantItems)
+	}
+	if len(got.Errors) != 1 || got.Errors[SourceCask] != boom {
+		t.Errorf("Errors = %+v, want only the cask error", got.Errors)
+	}
+}
+
+// TestFanOutNoSources checks that an unavailable source is simply absent.
+func TestFanOutNoSources(t *testing.T) {
+	got := fanOut(context.Background(), "wget", nil)
+	if len(got.Items) != 0 || len(got.Errors) != 0 {
+		t.Errorf("fanOut(no searchers) = %+v, want empty", got)
+	}
+}
+
+func TestFromHomebrew(t *testing.T) {
+	got := fromHomebrew([]homebrew.SearchResult{
+		{Name: "wget", Description: "Internet file retriever", Homepage: "https://www.gnu.org/software/wget/", Version: "1.25.0", Installed: true},
+		{Name: "firefox", IsCask: true},
+	})
+	want := []Result{
+		{Name: "wget", ID: "wget", Source: SourceFormula, Description: "Internet file retriever", Version: "1.25.0", Homepage: "https://www.gnu.org/software/wget/", Installed: true},
+		{Name: "firefox", ID: "firefox", Source: SourceCask},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fromHomebrew() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestFromFlatpak(t *testing.T) {
+	got := fromFlatpak([]flatpak.SearchResult{
+		{Name: "Loupe", Description: "View images", ApplicationID: "org.gnome.Loupe", Version: "48.1", Remotes: "flathub"},
+		{Name: "Tool", ApplicationID: "org.example.Tool", Remotes: "corp"},
+		{Name: "Other", ApplicationID: "org.example.Other"},
+	}, map[string]bool{"org.gnome.Loupe": true}, map[string]bool{"flathub": true, "corp": false})
+	want := []Result{
+		{Name: "Loupe", ID: "org.gnome.Loupe", Source: SourceFlatpak, Description: "View images", Version: "48.1", Installed: true, Remote: "flathub", User: true},
+		{Name: "Tool", ID: "org.example.Tool", Source: SourceFlatpak, Remote: "corp"},
+		{Name: "Other", ID: "org.example.Other", Source: SourceFlatpak, User: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fromFlatpak() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	remotes := map[string]bool{"flathub": true, "corp": false}
+	tests := []struct {
+		listed     string
+		wantRemote string
+		wantUser   bool
+	}{
+		{"flathub", "flathub", true},
+		{"corp", "corp", false},
+		// The first configured remote wins; unknown ones are skipped
+		{"gone, corp, flathub", "corp", false},
+		{"gone", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		remote, user := origin(tt.listed, remotes)
+		if remote != tt.wantRemote || user != tt.wantUser {
+			t.Errorf("origin(%q) = (%q, %v), want (%q, %v)", tt.listed, remote, user, tt.wantRemote, tt.wantUser)
+		}
+	}
+}
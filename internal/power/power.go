@@ -0,0 +1,105 @@
+// Package power restarts the machine through systemd-logind, either now or
+// at a scheduled time, so a system update staged for the next boot can be
+// applied without leaving ChairLift. systemctl and shutdown ask logind,
+// whose polkit rules let the user of an active local session restart
+// without a password.
+package power
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// TonightHour is the local hour a "tonight" restart is scheduled for
+const TonightHour = 3
+
+var (
+	dryRun  = false
+	timeout = 30 * time.Second
+)
+
+// SetDryRun enables/disables dry-run mode
+func SetDryRun(mode bool) {
+	dryRun = mode
+	log.Printf("Power dry-run mode: %v", mode)
+}
+
+// IsDryRun returns whether dry-run mode is enabled
+func IsDryRun() bool {
+	return dryRun
+}
+
+// Error represents a failed restart request
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Tonight returns the next TonightHour:00 after now, in now's location
+func Tonight(now time.Time) time.Time {
+	at := time.Date(now.Year(), now.Month(), now.Day(), TonightHour, 0, 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// scheduleArgs returns the shutdown arguments that restart at the given
+// local time. shutdown takes only hh:mm, meaning the next time the clock
+// reads it, so at must be within the next 24 hours.
+func scheduleArgs(at time.Time) []string {
+	return []string{"--reboot", at.Format("15:04"), "ChairLift: restarting to apply a system update"}
+}
+
+// Reboot restarts the machine now
+func Reboot(ctx context.Context) error {
+	return run(ctx, "systemctl", "reboot")
+}
+
+// ScheduleReboot asks logind to restart the machine at at, replacing any
+// restart already scheduled
+func ScheduleReboot(ctx context.Context, at time.Time) error {
+	return run(ctx, "shutdown", scheduleArgs(at)...)
+}
+
+// CancelScheduled cancels a restart scheduled with ScheduleReboot
+func CancelScheduled(ctx context.Context) error {
+	return run(ctx, "shutdown", "-c")
+}
+
+// run executes a power command, or only logs it under dry-run
+func run(parent context.Context, name string, args ...string) error {
+	if dryRun {
+		log.Printf("[DRY-RUN] Would execute: %s %s", name, strings.Join(args, " "))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return &Error{Message: fmt.Sprintf("Command '%s %s' timed out", name, strings.Join(args, " "))}
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return &Error{Message: fmt.Sprintf("%s failed: %s", name, strings.TrimSpace(stderr.String()))}
+		}
+		return &Error{Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package power
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTonight(t *testing.T) {
+	loc := time.FixedZone("test", 2*60*60)
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		// Evening: early tomorrow morning
+		{time.Date(2026, 10, 17, 21, 30, 0, 0, loc), time.Date(2026, 10, 18, 3, 0, 0, 0, loc)},
+		// After midnight but before the hour: later the same night
+		{time.Date(2026, 10, 18, 1, 0, 0, 0, loc), time.Date(2026, 10, 18, 3, 0, 0, 0, loc)},
+		// Exactly on the hour has passed
+		{time.Date(2026, 10, 18, 3, 0, 0, 0, loc), time.Date(2026, 10, 19, 3, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := Tonight(tt.now); !got.Equal(tt.want) {
+			t.Errorf("Tonight(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleArgs(t *testing.T) {
+	at := time.Date(2026, 10, 18, 3, 0, 0, 0, time.Local)
+	got := scheduleArgs(at)
+	if got[0] != "--reboot" || got[1] != "03:00" {
+		t.Errorf("scheduleArgs = %q, want --reboot 03:00 ...", got)
+	}
+}
+
+func TestDryRunRunsNothing(t *testing.T) {
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	for name, fn := range map[string]func(context.Context) error{
+		"Reboot":          Reboot,
+		"CancelScheduled": CancelScheduled,
+		"ScheduleReboot": func(ctx context.Context) error {
+			return ScheduleReboot(ctx, Tonight(time.Now()))
+		},
+	} {
+		if err := fn(context.Background()); err != nil {
+			t.Errorf("dry-run %s = %v, want nil", name, err)
+		}
+	}
+}
+
+func TestRunReportsFailure(t *testing.T) {
+	err := run(context.Background(), "sh", "-c", "echo 'Access denied' >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "Access denied") {
+		t.Errorf("run(failing) = %v, want error with stderr", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Package preview describes what a package operation would do before it
+// runs: the commands, the packages it adds, upgrades or removes, and the
+// estimated download. The Homebrew and Flatpak wrappers build Plans from
+// their tools' own dry-run or listing output, and the views show them in
+// a confirmation dialog.
+package preview
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Action is what a plan does to one package
+type Action string
+
+const (
+	ActionInstall Action = "install"
+	ActionUpgrade Action = "upgrade"
+	ActionRemove  Action = "remove"
+)
+
+// Change is one package a plan touches. From and To are versions when
+// the tool reports them.
+type Change struct {
+	Name   string
+	Action Action
+	From   string
+	To     string
+}
+
+// Plan is what an operation would do
+type Plan struct {
+	// Commands are the command lines the real run executes
+	Commands []string
+	Changes  []Change
+	// DownloadSize is the estimated download in bytes; zero when the tool
+	// does not report one
+	DownloadSize int64
+}
+
+// Count returns how many changes of action the plan has
+func (p Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary is a one-line description of the plan, such as
+// "2 to install, 3 to upgrade, about 120.5 MB to download"
+func (p Plan) Summary() string {
+	var parts []string
+	for _, a := range []struct {
+		action Action
+		label  string
+	}{
+		{ActionInstall, "to install"},
+		{ActionUpgrade, "to upgrade"},
+		{ActionRemove, "to remove"},
+	} {
+		if n := p.Count(a.action); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, a.label))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "No package changes")
+	}
+	if p.DownloadSize > 0 {
+		parts = append(parts, fmt.Sprintf("about %s to download", FormatSize(p.DownloadSize)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Describe returns the version change of c for display, such as
+// "1.0 → 1.1", or the action when no versions are known
+func (c Change) Describe() string {
+	switch {
+	case c.From != "" && c.To != "":
+		return fmt.Sprintf("%s → %s", c.From, c.To)
+	case c.To != "":
+		return c.To
+	}
+	switch c.Action {
+	case ActionInstall:
+		return "New"
+	case ActionRemove:
+		return "Removed"
+	}
+	return "Upgrade"
+}
+
+// FormatSize formats bytes with decimal units, as Flatpak does
+func FormatSize(bytes int64) string {
+	const unit = 1000
+	if bytes < unit {
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+	value := float64(bytes)
+	for _, suffix := range []string{"kB", "MB", "GB"} {
+		value /= unit
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%.1f TB", value/unit)
+}
+
+// ParseSize parses a size as Flatpak prints it ("12.3 MB", "1.0 kB",
+// "524 bytes"). Flatpak separates the number and unit with a no-break
+// space, so any space counts. Unknown formats return zero and false.
+func ParseSize(s string) (int64, bool) {
+	var number, suffix string
+	switch fields := strings.FieldsFunc(s, unicode.IsSpace); len(fields) {
+	case 1:
+		number, suffix = fields[0], "bytes"
+	case 2:
+		number, suffix = fields[0], fields[1]
+	default:
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", "."), 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+
+	multipliers := map[string]float64{
+		"bytes": 1, "byte": 1, "B": 1,
+		"kB": 1e3, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12,
+	}
+	multiplier, ok := multipliers[suffix]
+	if !ok {
+		return 0, false
+	}
+	return int64(value * multiplier), true
+}
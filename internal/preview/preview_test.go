@@ -0,0 +1,92 @@
+package preview
+
+import "testing"
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		plan Plan
+		want string
+	}{
+		{"empty", Plan{}, "No package changes"},
+		{
+			"mixed with download",
+			Plan{
+				Changes: []Change{
+					{Name: "jq", Action: ActionInstall},
+					{Name: "oniguruma", Action: ActionInstall},
+					{Name: "wget", Action: ActionUpgrade, From: "1.0", To: "1.1"},
+				},
+				DownloadSize: 120_500_000,
+			},
+			"2 to install, 1 to upgrade, about 120.5 MB to download",
+		},
+		{"download only", Plan{DownloadSize: 999}, "No package changes, about 999 bytes to download"},
+	}
+	for _, tt := range tests {
+		if got := tt.plan.Summary(); got != tt.want {
+			t.Errorf("%s: Summary() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{Change{Action: ActionUpgrade, From: "1.0", To: "1.1"}, "1.0 → 1.1"},
+		{Change{Action: ActionUpgrade, To: "48.1"}, "48.1"},
+		{Change{Action: ActionInstall}, "New"},
+		{Change{Action: ActionRemove}, "Removed"},
+		{Change{Action: ActionUpgrade}, "Upgrade"},
+	}
+	for _, tt := range tests {
+		if got := tt.change.Describe(); got != tt.want {
+			t.Errorf("%+v.Describe() = %q, want %q", tt.change, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int64
+		wantOK bool
+	}{
+		{"12.3 MB", 12_300_000, true},
+		{"1.0 kB", 1000, true},
+		{"524 bytes", 524, true},
+		{"2,5 GB", 2_500_000_000, true},
+		{"12.3\u00a0MB", 12_300_000, true},
+		{" 4.1\u00a0kB\n", 4100, true},
+		{"1 2 MB", 0, false},
+		{"", 0, false},
+		{"lots", 0, false},
+		{"3 parsecs", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSize(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseSize(%q) = (%d, %v), want (%d, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 bytes"},
+		{999, "999 bytes"},
+		{1500, "1.5 kB"},
+		{120_500_000, "120.5 MB"},
+		{3_200_000_000, "3.2 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.in); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
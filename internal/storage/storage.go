@@ -0,0 +1,170 @@
+// Package storage reports mounted filesystems and their usage through df,
+// and measures directories through du, for the Storage page. It only
+// reads; cleanups belong to the wrapper package of whatever owns the space.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeout = 30 * time.Second
+	// duTimeout bounds measuring one directory, which can hold millions of
+	// files
+	duTimeout = 2 * time.Minute
+)
+
+// Error represents a failed df or du command
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Filesystem is one mounted filesystem
+type Filesystem struct {
+	Device string
+	Type   string
+	Mount  string
+	Size   int64 // bytes
+	Used   int64
+	Avail  int64
+}
+
+// UsedFraction is the share of the filesystem in use, from 0 to 1. Like
+// df's Use%, it counts space reserved for root as unavailable.
+func (f Filesystem) UsedFraction() float64 {
+	if f.Used+f.Avail <= 0 {
+		return 0
+	}
+	return float64(f.Used) / float64(f.Used+f.Avail)
+}
+
+// virtualTypes are filesystems that hold no user data on a disk
+var virtualTypes = []string{
+	"tmpfs", "devtmpfs", "overlay", "squashfs", "erofs", "efivarfs", "composefs", "fuse.portal",
+}
+
+// dfArgs returns the df arguments that list disk-backed filesystems in
+// bytes, one per line
+func dfArgs() []string {
+	args := []string{"--output=source,fstype,size,used,avail,target", "--block-size=1"}
+	for _, t := range virtualTypes {
+		args = append(args, "--exclude-type="+t)
+	}
+	return args
+}
+
+// parseDF parses df output. A device mounted more than once (bootc bind
+// mounts /sysroot's device at /var and elsewhere) is listed once, under
+// its shortest mount point.
+func parseDF(out string) []Filesystem {
+	byDevice := make(map[string]int)
+	var filesystems []Filesystem
+	for i, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 6 {
+			continue // header or blank
+		}
+		size, err1 := strconv.ParseInt(fields[2], 10, 64)
+		used, err2 := strconv.ParseInt(fields[3], 10, 64)
+		avail, err3 := strconv.ParseInt(fields[4], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || size == 0 {
+			continue
+		}
+		fs := Filesystem{
+			Device: fields[0],
+			Type:   fields[1],
+			Size:   size,
+			Used:   used,
+			Avail:  avail,
+			// A mount point with spaces spans the remaining fields
+			Mount: strings.Join(fields[5:], " "),
+		}
+
+		if j, ok := byDevice[fs.Device]; ok {
+			if len(fs.Mount) < len(filesystems[j].Mount) {
+				filesystems[j] = fs
+			}
+			continue
+		}
+		byDevice[fs.Device] = len(filesystems)
+		filesystems = append(filesystems, fs)
+	}
+	sort.Slice(filesystems, func(i, j int) bool { return filesystems[i].Mount < filesystems[j].Mount })
+	return filesystems
+}
+
+// ListFilesystems returns the mounted disk-backed filesystems, by mount
+// point
+func ListFilesystems(ctx context.Context) ([]Filesystem, error) {
+	out, err := runCommand(ctx, timeout, "df", dfArgs()...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDF(out), nil
+}
+
+// parseDU parses the total from `du --summarize --bytes`
+func parseDU(out string) (int64, bool) {
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	return size, err == nil
+}
+
+// DirSize returns the disk space used under path, counting hard links
+// once, as flatpak's deployments share files with its repository. Parts
+// the user cannot read are left out rather than failing the total. A
+// missing path is 0.
+func DirSize(ctx context.Context, path string) (int64, error) {
+	out, err := runCommand(ctx, duTimeout, "du", "--summarize", "--bytes", "--one-file-system", path)
+	if size, ok := parseDU(out); ok {
+		return size, nil // du exits 1 after unreadable files but still totals
+	}
+	if err != nil && strings.Contains(err.Error(), "No such file or directory") {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, &Error{Message: fmt.Sprintf("unexpected du output: %q", out)}
+}
+
+// runCommand runs a command with limit as its timeout, returning stdout
+// even when the command fails
+func runCommand(parent context.Context, limit time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, limit)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return "", parent.Err()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", &Error{Message: fmt.Sprintf("Command '%s %s' timed out", name, strings.Join(args, " "))}
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return stdout.String(), &Error{Message: fmt.Sprintf("%s failed: %s", name, strings.TrimSpace(stderr.String()))}
+		}
+		return "", &Error{Message: err.Error()}
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDF(t *testing.T) {
+	out := `Filesystem     Type      1B-blocks        Used       Avail Mounted on
+/dev/nvme0n1p3 btrfs  510770802688 98765432832 409876543488 /sysroot
+/dev/nvme0n1p3 btrfs  510770802688 98765432832 409876543488 /var
+/dev/nvme0n1p2 ext4     1020702720   210763776   739889152 /boot
+/dev/sda1      exfat   61505273856 20000000000 41505273856 /run/media/user/My Disk
+`
+	want := []Filesystem{
+		{Device: "/dev/nvme0n1p2", Type: "ext4", Mount: "/boot", Size: 1020702720, Used: 210763776, Avail: 739889152},
+		{Device: "/dev/sda1", Type: "exfat", Mount: "/run/media/user/My Disk", Size: 61505273856, Used: 20000000000, Avail: 41505273856},
+		{Device: "/dev/nvme0n1p3", Type: "btrfs", Mount: "/var", Size: 510770802688, Used: 98765432832, Avail: 409876543488},
+	}
+	if got := parseDF(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDF() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsedFraction(t *testing.T) {
+	fs := Filesystem{Size: 100, Used: 45, Avail: 45} // 10 reserved for root
+	if got := fs.UsedFraction(); got != 0.5 {
+		t.Errorf("UsedFraction() = %v, want 0.5", got)
+	}
+	if got := (Filesystem{}).UsedFraction(); got != 0 {
+		t.Errorf("empty UsedFraction() = %v, want 0", got)
+	}
+}
+
+func TestDfArgs(t *testing.T) {
+	args := strings.Join(dfArgs(), " ")
+	for _, want := range []string{"--block-size=1", "--exclude-type=tmpfs", "--exclude-type=overlay"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("dfArgs() = %q, want it to contain %q", args, want)
+		}
+	}
+}
+
+func TestParseDU(t *testing.T) {
+	if got, ok := parseDU("123456\t/var/lib/flatpak\n"); !ok || got != 123456 {
+		t.Errorf("parseDU() = %d, %v, want 123456, true", got, ok)
+	}
+	if _, ok := parseDU(""); ok {
+		t.Error("parseDU(\"\") ok, want not ok")
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 5000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	size, err := DirSize(context.Background(), dir)
+	if err != nil || size < 5000 {
+		t.Errorf("DirSize() = %d, %v, want at least 5000", size, err)
+	}
+	size, err = DirSize(context.Background(), filepath.Join(dir, "missing"))
+	if err != nil || size != 0 {
+		t.Errorf("DirSize(missing) = %d, %v, want 0, nil", size, err)
+	}
+}
@@ -0,0 +1,335 @@
+package systemd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+	"github.com/frostyard/chairlift/internal/systemhelper"
+)
+
+const (
+	journalctlCommand = "journalctl"
+	pkexecCommand     = "pkexec"
+)
+
+// JournalVacuumSize is what VacuumJournal trims the journal down to; the
+// system helper fixes it
+const JournalVacuumSize = systemhelper.JournalVacuumSize
+
+// Journal priorities, most severe first, as journalctl's --priority takes
+// them. Emergency, alert and critical are folded into PriorityError for
+// display.
+const (
+	PriorityError   = 3
+	PriorityWarning = 4
+	PriorityNotice  = 5
+	PriorityInfo    = 6
+	PriorityDebug   = 7
+)
+
+// JournalInstalled reports whether journalctl is on $PATH
+func JournalInstalled() bool {
+	_, err := exec.LookPath(journalctlCommand)
+	return err == nil
+}
+
+// JournalEntry is one journal record
+type JournalEntry struct {
+	Time       time.Time
+	Priority   int
+	Identifier string // SYSLOG_IDENTIFIER, else the process name
+	Unit       string // The systemd unit that logged it, if any
+	Message    string
+}
+
+// JournalQuery selects journal entries. The zero value is the newest
+// entries of every boot, at any priority.
+type JournalQuery struct {
+	User bool   // The user's own journal (journalctl --user)
+	Unit string // Only this unit
+	// Priority is the least severe priority to include; zero does not filter
+	Priority int
+	// Boot is a boot offset ("0" the current boot, "-1" the one before) or
+	// ID; empty does not filter
+	Boot  string
+	Grep  string // Only messages matching this pattern, case-insensitively
+	Lines int    // Only the newest Lines entries; zero for all
+}
+
+// args returns the journalctl filter arguments for the query
+func (q JournalQuery) args() []string {
+	args := scopeArgs(q.User, "--no-pager", "--quiet")
+	if q.Unit != "" {
+		args = append(args, "--unit", q.Unit)
+	}
+	if q.Priority > 0 {
+		args = append(args, "--priority", strconv.Itoa(q.Priority))
+	}
+	if q.Boot != "" {
+		args = append(args, "--boot", q.Boot)
+	}
+	if q.Grep != "" {
+		args = append(args, "--grep", q.Grep, "--case-sensitive=false")
+	}
+	if q.Lines > 0 {
+		args = append(args, "--lines", strconv.Itoa(q.Lines))
+	}
+	return args
+}
+
+// fieldString decodes a journal JSON field. journalctl writes binary or
+// oversized values as byte arrays and repeated fields as arrays; a
+// repeated field yields its first value.
+func fieldString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var ints []int
+	if json.Unmarshal(raw, &ints) == nil {
+		b := make([]byte, len(ints))
+		for i, v := range ints {
+			b[i] = byte(v)
+		}
+		return string(b)
+	}
+	var values []json.RawMessage
+	if json.Unmarshal(raw, &values) == nil && len(values) > 0 {
+		return fieldString(values[0])
+	}
+	return ""
+}
+
+// parseJournalLine parses one line of `journalctl --output=json`
+func parseJournalLine(line []byte) (JournalEntry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return JournalEntry{}, &Error{Message: fmt.Sprintf("failed to parse journal entry: %v", err)}
+	}
+
+	e := JournalEntry{Priority: PriorityInfo}
+	if usec, err := strconv.ParseInt(fieldString(fields["__REALTIME_TIMESTAMP"]), 10, 64); err == nil {
+		e.Time = time.UnixMicro(usec)
+	}
+	if p, err := strconv.Atoi(fieldString(fields["PRIORITY"])); err == nil {
+		e.Priority = p
+	}
+	e.Identifier = fieldString(fields["SYSLOG_IDENTIFIER"])
+	if e.Identifier == "" {
+		e.Identifier = fieldString(fields["_COMM"])
+	}
+	e.Unit = fieldString(fields["_SYSTEMD_UNIT"])
+	if userUnit := fieldString(fields["_SYSTEMD_USER_UNIT"]); userUnit != "" {
+		e.Unit = userUnit
+	}
+	e.Message = strings.TrimRight(fieldString(fields["MESSAGE"]), "\n")
+	return e, nil
+}
+
+// parseJournal parses `journalctl --output=json` output, one entry per line
+func parseJournal(out string) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-- ") {
+			continue // "-- No entries --" and other notes
+		}
+		e, err := parseJournalLine([]byte(line))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Query returns the journal entries matching q, oldest first. Without
+// membership in systemd-journal or adm, the system journal only holds what
+// the user may read, which is often nothing.
+func Query(ctx context.Context, q JournalQuery) ([]JournalEntry, error) {
+	out, err := runCommand(ctx, timeout, journalctlCommand, append(q.args(), "--output=json")...)
+	if err != nil {
+		// --grep exits 1 without a message when nothing matches
+		var cmdErr *Error
+		if q.Grep != "" && errors.As(err, &cmdErr) && cmdErr.Message == journalctlCommand+" failed: " {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseJournal(out)
+}
+
+// Journal returns a service's last lines journal entries, oldest first
+func Journal(ctx context.Context, name string, user bool, lines int) ([]JournalEntry, error) {
+	return Query(ctx, JournalQuery{User: user, Unit: name, Lines: lines})
+}
+
+// Follow calls onEntry with each new entry matching q as it is written,
+// until ctx is cancelled. q.Lines and q.Boot are ignored: following starts
+// at the end of the current boot's journal.
+func Follow(ctx context.Context, q JournalQuery, onEntry func(JournalEntry)) error {
+	q.Lines = 0
+	q.Boot = ""
+	args := append(q.args(), "--output=json", "--follow", "--lines", "0")
+
+	cmd := exec.CommandContext(ctx, journalctlCommand, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return &Error{Message: err.Error()}
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return &Error{Message: err.Error()}
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if e, err := parseJournalLine(scanner.Bytes()); err == nil {
+			onEntry(e)
+		}
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil // Stopped by the caller
+	}
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("%s failed: %s", journalctlCommand, strings.TrimSpace(stderr.String()))}
+	}
+	return nil
+}
+
+// Export writes the entries matching q to path as text, one line per entry
+// in journalctl's short-iso format
+func Export(ctx context.Context, q JournalQuery, path string) error {
+	out, err := runCommand(ctx, timeout, journalctlCommand, append(q.args(), "--output=short-iso")...)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+		return &Error{Message: fmt.Sprintf("failed to write %s: %v", path, err)}
+	}
+	return nil
+}
+
+// Boot is one boot recorded in the journal
+type Boot struct {
+	Index int // 0 for the current boot, -1 for the one before, ...
+	ID    string
+	First time.Time
+	Last  time.Time
+}
+
+// listedBoot is one entry of `journalctl --list-boots --output=json`
+type listedBoot struct {
+	Index      int    `json:"index"`
+	BootID     string `json:"boot_id"`
+	FirstEntry int64  `json:"first_entry"`
+	LastEntry  int64  `json:"last_entry"`
+}
+
+// parseBoots parses list-boots JSON, newest boot first
+func parseBoots(data []byte) ([]Boot, error) {
+	var listed []listedBoot
+	if err := json.Unmarshal(data, &listed); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("failed to parse journalctl --list-boots output: %v", err)}
+	}
+	boots := make([]Boot, 0, len(listed))
+	for _, l := range listed {
+		boots = append(boots, Boot{
+			Index: l.Index,
+			ID:    l.BootID,
+			First: time.UnixMicro(l.FirstEntry),
+			Last:  time.UnixMicro(l.LastEntry),
+		})
+	}
+	sort.Slice(boots, func(i, j int) bool { return boots[i].Index > boots[j].Index })
+	return boots, nil
+}
+
+// ListBoots returns the boots recorded in the journal, newest first
+func ListBoots(ctx context.Context) ([]Boot, error) {
+	out, err := runCommand(ctx, timeout, journalctlCommand, "--list-boots", "--output=json", "--no-pager")
+	if err != nil {
+		return nil, err
+	}
+	return parseBoots([]byte(out))
+}
+
+// parseDiskUsage parses `journalctl --disk-usage`, e.g. "Archived and
+// active journals take up 1.2G in the file system.", into bytes
+func parseDiskUsage(out string) (int64, bool) {
+	_, rest, ok := strings.Cut(out, "take up ")
+	if !ok {
+		return 0, false
+	}
+	value, _, _ := strings.Cut(rest, " ")
+	if value == "" {
+		return 0, false
+	}
+	multiplier := int64(1)
+	switch value[len(value)-1] {
+	case 'B':
+		value = value[:len(value)-1]
+	case 'K':
+		multiplier, value = 1<<10, value[:len(value)-1]
+	case 'M':
+		multiplier, value = 1<<20, value[:len(value)-1]
+	case 'G':
+		multiplier, value = 1<<30, value[:len(value)-1]
+	case 'T':
+		multiplier, value = 1<<40, value[:len(value)-1]
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(number * float64(multiplier)), true
+}
+
+// JournalDiskUsage returns the disk space taken by the journal files the
+// user may read; for a user outside systemd-journal, only their own
+func JournalDiskUsage(ctx context.Context) (int64, error) {
+	out, err := runCommand(ctx, timeout, journalctlCommand, "--disk-usage")
+	if err != nil {
+		return 0, err
+	}
+	size, ok := parseDiskUsage(out)
+	if !ok {
+		return 0, &Error{Message: fmt.Sprintf("unexpected journalctl --disk-usage output: %q", strings.TrimSpace(out))}
+	}
+	return size, nil
+}
+
+// VacuumJournal deletes the oldest archived journal files until the
+// journal fits in JournalVacuumSize. The files belong to root, so this
+// runs through the system helper via pkexec, under its own polkit action
+// (org.frostyard.ChairLift.journal.vacuum).
+func VacuumJournal(ctx context.Context) error {
+	args := vacuumCommand()
+	if dryRun {
+		log.Printf("[DRY-RUN] Would execute: %s %s", pkexecCommand, strings.Join(args, " "))
+		return nil
+	}
+	_, err := runCommand(ctx, ControlTimeout, pkexecCommand, args...)
+	audit.Record(pkexecCommand, args, err)
+	return err
+}
+
+// vacuumCommand returns pkexec's arguments for VacuumJournal
+func vacuumCommand() []string {
+	return []string{systemhelper.Path, systemhelper.JournalVacuum}
+}
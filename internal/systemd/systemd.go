@@ -0,0 +1,314 @@
+// Package systemd lists and controls services through systemctl, for the
+// system manager or the user's own, and reads their recent journal lines
+// through journalctl. Changing a system service is authorized by systemd's
+// own polkit action (org.freedesktop.systemd1.manage-units), which the
+// session's authentication agent prompts for; user services need none.
+// Vacuuming the journal has no polkit action of its own, so it goes
+// through chairlift-system-helper via pkexec.
+package systemd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/audit"
+)
+
+const systemctlCommand = "systemctl"
+
+// ControlTimeout bounds a start, stop or restart; systemd's default stop
+// timeout alone is 90 seconds
+const ControlTimeout = 2 * time.Minute
+
+var (
+	dryRun  = false
+	timeout = 30 * time.Second
+)
+
+// SetDryRun enables/disables dry-run mode
+func SetDryRun(mode bool) {
+	dryRun = mode
+	log.Printf("Systemd dry-run mode: %v", mode)
+}
+
+// IsDryRun returns whether dry-run mode is enabled
+func IsDryRun() bool {
+	return dryRun
+}
+
+// Error represents a failed systemctl or journalctl command
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// IsInstalled reports whether systemctl is on $PATH
+func IsInstalled() bool {
+	_, err := exec.LookPath(systemctlCommand)
+	return err == nil
+}
+
+// Unit is one service
+type Unit struct {
+	Name        string
+	Description string
+	LoadState   string // loaded, masked, ...
+	ActiveState string // active, inactive, failed, activating, ...
+	SubState    string // running, exited, dead, ...
+	// FileState is the unit file's enablement: enabled, disabled, static,
+	// masked, ... Empty for a unit with no unit file (e.g. generated).
+	FileState string
+}
+
+// Active reports whether the service is running or starting
+func (u Unit) Active() bool {
+	switch u.ActiveState {
+	case "active", "activating", "reloading":
+		return true
+	}
+	return false
+}
+
+// Failed reports whether the service is in the failed state
+func (u Unit) Failed() bool {
+	return u.ActiveState == "failed"
+}
+
+// CanEnable reports whether the service can be enabled or disabled; static,
+// masked and generated units cannot
+func (u Unit) CanEnable() bool {
+	return u.FileState == "enabled" || u.FileState == "disabled"
+}
+
+// State describes the unit for a subtitle, e.g. "active (running)"
+func (u Unit) State() string {
+	if u.SubState == "" || u.SubState == u.ActiveState {
+		return u.ActiveState
+	}
+	return fmt.Sprintf("%s (%s)", u.ActiveState, u.SubState)
+}
+
+// scopeArgs prefixes args with --user for the user's service manager
+func scopeArgs(user bool, args ...string) []string {
+	if user {
+		return append([]string{"--user"}, args...)
+	}
+	return args
+}
+
+// listedUnit is one entry of `systemctl list-units --output=json`
+type listedUnit struct {
+	Unit        string `json:"unit"`
+	Load        string `json:"load"`
+	Active      string `json:"active"`
+	Sub         string `json:"sub"`
+	Description string `json:"description"`
+}
+
+// listedUnitFile is one entry of `systemctl list-unit-files --output=json`
+type listedUnitFile struct {
+	UnitFile string `json:"unit_file"`
+	State    string `json:"state"`
+}
+
+// parseUnits parses list-units JSON, skipping units whose file is missing
+func parseUnits(data []byte) ([]Unit, error) {
+	var listed []listedUnit
+	if err := json.Unmarshal(data, &listed); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("failed to parse systemctl list-units output: %v", err)}
+	}
+	units := make([]Unit, 0, len(listed))
+	for _, l := range listed {
+		if l.Load == "not-found" {
+			continue // referenced by another unit but not installed
+		}
+		units = append(units, Unit{
+			Name:        l.Unit,
+			Description: l.Description,
+			LoadState:   l.Load,
+			ActiveState: l.Active,
+			SubState:    l.Sub,
+		})
+	}
+	return units, nil
+}
+
+// parseUnitFiles parses list-unit-files JSON into unit file states by name
+func parseUnitFiles(data []byte) (map[string]string, error) {
+	var listed []listedUnitFile
+	if err := json.Unmarshal(data, &listed); err != nil {
+		return nil, &Error{Message: fmt.Sprintf("failed to parse systemctl list-unit-files output: %v", err)}
+	}
+	states := make(map[string]string, len(listed))
+	for _, l := range listed {
+		name := l.UnitFile
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:] // older systemd prints the full path
+		}
+		states[name] = l.State
+	}
+	return states, nil
+}
+
+// mergeServices sets each loaded unit's file state and adds the enabled or
+// disabled services that are not loaded, so a stopped service can still be
+// started. Templates are left out: they only run as named instances.
+func mergeServices(units []Unit, files map[string]string) []Unit {
+	seen := make(map[string]bool, len(units))
+	for i := range units {
+		units[i].FileState = files[units[i].Name]
+		seen[units[i].Name] = true
+	}
+	for name, state := range files {
+		if seen[name] || strings.Contains(name, "@.") {
+			continue
+		}
+		if state != "enabled" && state != "disabled" {
+			continue
+		}
+		units = append(units, Unit{
+			Name:        name,
+			LoadState:   "not-loaded",
+			ActiveState: "inactive",
+			SubState:    "dead",
+			FileState:   state,
+		})
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Name < units[j].Name })
+	return units
+}
+
+// ListServices returns the services of the system or the user's manager
+func ListServices(ctx context.Context, user bool) ([]Unit, error) {
+	out, err := runCommand(ctx, timeout, systemctlCommand,
+		scopeArgs(user, "list-units", "--type=service", "--all", "--output=json", "--no-pager")...)
+	if err != nil {
+		return nil, err
+	}
+	units, err := parseUnits([]byte(out))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = runCommand(ctx, timeout, systemctlCommand,
+		scopeArgs(user, "list-unit-files", "--type=service", "--output=json", "--no-pager")...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := parseUnitFiles([]byte(out))
+	if err != nil {
+		return nil, err
+	}
+	return mergeServices(units, files), nil
+}
+
+// GetService returns one service's current state
+func GetService(ctx context.Context, name string, user bool) (*Unit, error) {
+	out, err := runCommand(ctx, timeout, systemctlCommand, scopeArgs(user, "show", name, "--no-pager",
+		"--property=Id,Description,LoadState,ActiveState,SubState,UnitFileState")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseShow(out), nil
+}
+
+// parseShow parses `systemctl show --property=...` key=value lines
+func parseShow(out string) *Unit {
+	u := &Unit{}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			u.Name = value
+		case "Description":
+			u.Description = value
+		case "LoadState":
+			u.LoadState = value
+		case "ActiveState":
+			u.ActiveState = value
+		case "SubState":
+			u.SubState = value
+		case "UnitFileState":
+			u.FileState = value
+		}
+	}
+	return u
+}
+
+// Start starts a service
+func Start(ctx context.Context, name string, user bool) error {
+	return control(ctx, user, "start", name)
+}
+
+// Stop stops a service
+func Stop(ctx context.Context, name string, user bool) error {
+	return control(ctx, user, "stop", name)
+}
+
+// Restart restarts a service, starting it if it is stopped
+func Restart(ctx context.Context, name string, user bool) error {
+	return control(ctx, user, "restart", name)
+}
+
+// Enable makes a service start at boot (or login, for user services)
+// without starting it now
+func Enable(ctx context.Context, name string, user bool) error {
+	return control(ctx, user, "enable", name)
+}
+
+// Disable stops a service from starting at boot without stopping it now
+func Disable(ctx context.Context, name string, user bool) error {
+	return control(ctx, user, "disable", name)
+}
+
+// control runs a state-changing systemctl verb on one unit, or only logs
+// it under dry-run
+func control(ctx context.Context, user bool, verb, name string) error {
+	args := scopeArgs(user, verb, name)
+	if dryRun {
+		log.Printf("[DRY-RUN] Would execute: %s %s", systemctlCommand, strings.Join(args, " "))
+		return nil
+	}
+	_, err := runCommand(ctx, ControlTimeout, systemctlCommand, args...)
+	audit.Record(systemctlCommand, args, err)
+	return err
+}
+
+// runCommand runs a command with limit as its timeout, returning stdout
+func runCommand(parent context.Context, limit time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, limit)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// A cancelled caller gets its own error back, not a failure message
+		if parent.Err() != nil {
+			return "", parent.Err()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", &Error{Message: fmt.Sprintf("Command '%s %s' timed out", name, strings.Join(args, " "))}
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", &Error{Message: fmt.Sprintf("%s failed: %s", name, strings.TrimSpace(stderr.String()))}
+		}
+		return "", &Error{Message: err.Error()}
+	}
+	return stdout.String(), nil
+}
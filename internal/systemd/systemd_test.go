@@ -0,0 +1,188 @@
+package systemd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseUnits(t *testing.T) {
+	data := `[
+{"unit":"sshd.service","load":"loaded","active":"active","sub":"running","description":"OpenSSH Daemon"},
+{"unit":"cups.service","load":"loaded","active":"failed","sub":"failed","description":"CUPS Scheduler"},
+{"unit":"gone.service","load":"not-found","active":"inactive","sub":"dead","description":"gone.service"}
+]`
+	units, err := parseUnits([]byte(data))
+	if err != nil {
+		t.Fatalf("parseUnits: %v", err)
+	}
+	want := []Unit{
+		{Name: "sshd.service", Description: "OpenSSH Daemon", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+		{Name: "cups.service", Description: "CUPS Scheduler", LoadState: "loaded", ActiveState: "failed", SubState: "failed"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("parseUnits() = %+v, want %+v", units, want)
+	}
+	if _, err := parseUnits([]byte("not json")); err == nil {
+		t.Error("parseUnits(not json) = nil error, want one")
+	}
+}
+
+func TestMergeServices(t *testing.T) {
+	units := []Unit{
+		{Name: "sshd.service", ActiveState: "active", SubState: "running"},
+		{Name: "systemd-journald.service", ActiveState: "active", SubState: "running"},
+	}
+	files, err := parseUnitFiles([]byte(`[
+{"unit_file":"sshd.service","state":"enabled"},
+{"unit_file":"/usr/lib/systemd/system/systemd-journald.service","state":"static"},
+{"unit_file":"bluetooth.service","state":"disabled"},
+{"unit_file":"getty@.service","state":"enabled"},
+{"unit_file":"masked.service","state":"masked"}
+]`))
+	if err != nil {
+		t.Fatalf("parseUnitFiles: %v", err)
+	}
+
+	got := mergeServices(units, files)
+	var names []string
+	for _, u := range got {
+		names = append(names, u.Name+"="+u.FileState)
+	}
+	want := []string{"bluetooth.service=disabled", "sshd.service=enabled", "systemd-journald.service=static"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("mergeServices() = %q, want %q", names, want)
+	}
+	if got[0].Active() || !got[1].Active() {
+		t.Errorf("Active(): bluetooth %v, sshd %v, want false, true", got[0].Active(), got[1].Active())
+	}
+	if !got[0].CanEnable() || got[2].CanEnable() {
+		t.Errorf("CanEnable(): bluetooth %v, journald %v, want true, false", got[0].CanEnable(), got[2].CanEnable())
+	}
+}
+
+func TestParseShow(t *testing.T) {
+	out := "Id=sshd.service\nDescription=OpenSSH Daemon\nLoadState=loaded\nActiveState=active\nSubState=running\nUnitFileState=enabled\n"
+	want := &Unit{Name: "sshd.service", Description: "OpenSSH Daemon", LoadState: "loaded",
+		ActiveState: "active", SubState: "running", FileState: "enabled"}
+	if got := parseShow(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseShow() = %+v, want %+v", got, want)
+	}
+	if got := want.State(); got != "active (running)" {
+		t.Errorf("State() = %q, want active (running)", got)
+	}
+}
+
+func TestScopeArgs(t *testing.T) {
+	if got := scopeArgs(false, "start", "a.service"); !reflect.DeepEqual(got, []string{"start", "a.service"}) {
+		t.Errorf("scopeArgs(system) = %q", got)
+	}
+	if got := scopeArgs(true, "start", "a.service"); !reflect.DeepEqual(got, []string{"--user", "start", "a.service"}) {
+		t.Errorf("scopeArgs(user) = %q", got)
+	}
+}
+
+func TestParseJournal(t *testing.T) {
+	out := `{"__REALTIME_TIMESTAMP":"1792220400000000","PRIORITY":"3","SYSLOG_IDENTIFIER":"bootc","_SYSTEMD_UNIT":"bootc-fetch-apply-updates.service","MESSAGE":"error: pulling image: 503"}
+{"__REALTIME_TIMESTAMP":"1792220401000000","_COMM":"sshd","MESSAGE":[104,105,10]}
+-- No entries --
+`
+	got, err := parseJournal(out)
+	if err != nil {
+		t.Fatalf("parseJournal: %v", err)
+	}
+	want := []JournalEntry{
+		{Time: time.UnixMicro(1792220400000000), Priority: PriorityError, Identifier: "bootc",
+			Unit: "bootc-fetch-apply-updates.service", Message: "error: pulling image: 503"},
+		{Time: time.UnixMicro(1792220401000000), Priority: PriorityInfo, Identifier: "sshd", Message: "hi"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseJournal() = %+v, want %+v", got, want)
+	}
+	if _, err := parseJournal("not json"); err == nil {
+		t.Error("parseJournal(not json) = nil error, want one")
+	}
+}
+
+func TestJournalQueryArgs(t *testing.T) {
+	if got, want := (JournalQuery{}).args(), []string{"--no-pager", "--quiet"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("zero query args = %q, want %q", got, want)
+	}
+	q := JournalQuery{User: true, Unit: "a.service", Priority: PriorityWarning, Boot: "-1", Grep: "fail", Lines: 200}
+	want := []string{"--user", "--no-pager", "--quiet", "--unit", "a.service", "--priority", "4",
+		"--boot", "-1", "--grep", "fail", "--case-sensitive=false", "--lines", "200"}
+	if got := q.args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %q, want %q", got, want)
+	}
+}
+
+func TestParseBoots(t *testing.T) {
+	data := `[{"index":-1,"boot_id":"aaa","first_entry":1792130000000000,"last_entry":1792150000000000},
+{"index":0,"boot_id":"bbb","first_entry":1792200000000000,"last_entry":1792220400000000}]`
+	boots, err := parseBoots([]byte(data))
+	if err != nil {
+		t.Fatalf("parseBoots: %v", err)
+	}
+	if len(boots) != 2 || boots[0].ID != "bbb" || boots[1].Index != -1 {
+		t.Errorf("parseBoots() = %+v, want current boot first", boots)
+	}
+	if !boots[0].First.Equal(time.UnixMicro(1792200000000000)) {
+		t.Errorf("parseBoots() first entry = %v", boots[0].First)
+	}
+}
+
+func TestParseDiskUsage(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int64
+	}{
+		{"Archived and active journals take up 1.5G in the file system.\n", 3 << 29},
+		{"Journals take up 24.0M on disk.\n", 24 << 20},
+		{"Archived and active journals take up 512B in the file system.\n", 512},
+	}
+	for _, tt := range tests {
+		if got, ok := parseDiskUsage(tt.out); !ok || got != tt.want {
+			t.Errorf("parseDiskUsage(%q) = %d, %v, want %d", tt.out, got, ok, tt.want)
+		}
+	}
+	if _, ok := parseDiskUsage("No journal files were found."); ok {
+		t.Error("parseDiskUsage(no files) ok, want not ok")
+	}
+}
+
+func TestDryRunRunsNothing(t *testing.T) {
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	for name, fn := range map[string]func(context.Context, string, bool) error{
+		"Start":   Start,
+		"Stop":    Stop,
+		"Restart": Restart,
+		"Enable":  Enable,
+		"Disable": Disable,
+	} {
+		if err := fn(context.Background(), "chairlift-test.service", false); err != nil {
+			t.Errorf("dry-run %s = %v, want nil", name, err)
+		}
+	}
+	if err := VacuumJournal(context.Background()); err != nil {
+		t.Errorf("dry-run VacuumJournal = %v, want nil", err)
+	}
+}
+
+func TestVacuumCommand(t *testing.T) {
+	// pkexec runs the fixed system helper, never journalctl itself
+	want := []string{"/usr/bin/chairlift-system-helper", "journal-vacuum"}
+	if got := vacuumCommand(); !reflect.DeepEqual(got, want) {
+		t.Errorf("vacuumCommand() = %v, want %v", got, want)
+	}
+}
+
+func TestRunCommandReportsFailure(t *testing.T) {
+	_, err := runCommand(context.Background(), timeout, "sh", "-c", "echo 'Access denied' >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "Access denied") {
+		t.Errorf("runCommand(failing) = %v, want error with stderr", err)
+	}
+}
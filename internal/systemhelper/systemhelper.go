@@ -0,0 +1,128 @@
+// Package systemhelper decides what cmd/chairlift-system-helper runs: the
+// privileged helper binary invoked via pkexec for root operations that
+// have no helper of their own. Each subcommand maps to a fixed program at
+// a fixed absolute path with fixed arguments, and each has its own polkit
+// action in data/org.frostyard.ChairLift.*.policy, selected by pkexec from
+// the helper's path and its first argument. Nothing the caller passes is
+// ever run as a program.
+//
+// It is deliberately free of any puregotk/GTK import, so the decision can
+// be unit-tested on a headless host and inside the gates' ./internal/...
+// test scope. See docs/agents/skills/gate-test-scope-is-internal-only.md.
+// cmd/chairlift-system-helper/main.go only runs the steps returned here.
+package systemhelper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Path is the fixed, absolute installed path of the helper. It must match
+// the org.freedesktop.policykit.exec.path annotation on every action that
+// names the helper: pkexec compares it textually, and a $PATH-resolved
+// name would fall back to the generic run-program action.
+const Path = "/usr/bin/chairlift-system-helper"
+
+// Fixed absolute paths of the programs the helper runs
+const (
+	bootcPath      = "/usr/bin/bootc"
+	podmanPath     = "/usr/bin/podman"
+	flatpakPath    = "/usr/bin/flatpak"
+	journalctlPath = "/usr/bin/journalctl"
+)
+
+// Subcommands, each the first argument pkexec matches an action on
+const (
+	BootcRollback = "bootc-rollback"
+	BootcSwitch   = "bootc-switch"
+	FlatpakRepair = "flatpak-repair"
+	JournalVacuum = "journal-vacuum"
+)
+
+// JournalVacuumSize is what journal-vacuum trims the journal down to. It
+// is fixed here rather than passed in, so the action cannot be used to
+// empty the journal.
+const JournalVacuumSize = "500M"
+
+// Transports bootc-switch accepts, naming how the new image is pulled
+const (
+	TransportRegistry          = "registry"
+	TransportContainersStorage = "containers-storage"
+)
+
+// imageRefPattern is what bootc-switch accepts as an image reference:
+// registry, repository, tag and digest characters only, starting with a
+// letter or digit so it is never read as a flag
+var imageRefPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/:@+-]*$`)
+
+// maxImageRefLen bounds an image reference, well above any real one
+const maxImageRefLen = 512
+
+// ValidImageRef reports whether image is a reference bootc-switch accepts
+func ValidImageRef(image string) bool {
+	return len(image) <= maxImageRefLen && imageRefPattern.MatchString(image)
+}
+
+// Step is one program the helper runs, by absolute path
+type Step struct {
+	Path string
+	Args []string
+}
+
+// ErrUsage is returned for an unknown subcommand or wrong arguments
+var ErrUsage = errors.New("usage: chairlift-system-helper <command> [args...]")
+
+// Plan returns the steps for the helper's arguments (os.Args[1:]), run in
+// order until one fails
+func Plan(args []string) ([]Step, error) {
+	if len(args) == 0 {
+		return nil, ErrUsage
+	}
+	switch args[0] {
+	case BootcRollback:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: chairlift-system-helper %s", BootcRollback)
+		}
+		return []Step{{Path: bootcPath, Args: []string{"rollback"}}}, nil
+	case BootcSwitch:
+		return planSwitch(args[1:])
+	case FlatpakRepair:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: chairlift-system-helper %s", FlatpakRepair)
+		}
+		return []Step{{Path: flatpakPath, Args: []string{"repair", "--system"}}}, nil
+	case JournalVacuum:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: chairlift-system-helper %s", JournalVacuum)
+		}
+		return []Step{{Path: journalctlPath, Args: []string{"--vacuum-size=" + JournalVacuumSize}}}, nil
+	default:
+		return nil, fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+// planSwitch returns the steps of bootc-switch <transport> <image>. A host
+// that boots from containers-storage, like snow, cannot use bootc's own
+// registry pull, so the image is pulled with podman first and then
+// switched to from local storage: the same two steps as the stage script.
+func planSwitch(args []string) ([]Step, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("usage: chairlift-system-helper %s <transport> <image>", BootcSwitch)
+	}
+	transport, image := args[0], args[1]
+	if !ValidImageRef(image) {
+		return nil, fmt.Errorf("invalid image reference: %q", image)
+	}
+	switch transport {
+	case TransportRegistry:
+		return []Step{{Path: bootcPath, Args: []string{"switch", image}}}, nil
+	case TransportContainersStorage:
+		return []Step{
+			{Path: podmanPath, Args: []string{"pull", image}},
+			{Path: bootcPath, Args: []string{"switch", "--transport", TransportContainersStorage, image}},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown transport: %q", transport)
+	}
+}
@@ -0,0 +1,91 @@
+package systemhelper
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []Step
+	}{
+		{
+			name: "rollback",
+			args: []string{BootcRollback},
+			want: []Step{{Path: "/usr/bin/bootc", Args: []string{"rollback"}}},
+		},
+		{
+			name: "switch from a registry",
+			args: []string{BootcSwitch, TransportRegistry, "quay.io/fedora/fedora-bootc:42"},
+			want: []Step{{Path: "/usr/bin/bootc", Args: []string{"switch", "quay.io/fedora/fedora-bootc:42"}}},
+		},
+		{
+			name: "switch through containers-storage",
+			args: []string{BootcSwitch, TransportContainersStorage, "ghcr.io/frostyard/snow:testing"},
+			want: []Step{
+				{Path: "/usr/bin/podman", Args: []string{"pull", "ghcr.io/frostyard/snow:testing"}},
+				{Path: "/usr/bin/bootc", Args: []string{"switch", "--transport", "containers-storage", "ghcr.io/frostyard/snow:testing"}},
+			},
+		},
+		{
+			name: "system flatpak repair",
+			args: []string{FlatpakRepair},
+			want: []Step{{Path: "/usr/bin/flatpak", Args: []string{"repair", "--system"}}},
+		},
+		{
+			name: "journal vacuum",
+			args: []string{JournalVacuum},
+			want: []Step{{Path: "/usr/bin/journalctl", Args: []string{"--vacuum-size=500M"}}},
+		},
+		{
+			name: "switch by digest",
+			args: []string{BootcSwitch, TransportRegistry, "ghcr.io/frostyard/snow@sha256:0123abcd"},
+			want: []Step{{Path: "/usr/bin/bootc", Args: []string{"switch", "ghcr.io/frostyard/snow@sha256:0123abcd"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Plan(tt.args)
+			if err != nil {
+				t.Fatalf("Plan(%q) error = %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+			for _, step := range got {
+				if !filepath.IsAbs(step.Path) {
+					t.Errorf("Plan(%q) runs %q, want an absolute path", tt.args, step.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanRejects(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"bootc"},
+		{"/bin/sh", "-c", "id"},
+		{BootcRollback, "--extra"},
+		{FlatpakRepair, "--user"},
+		{JournalVacuum, "--vacuum-size=1K"},
+		{BootcSwitch},
+		{BootcSwitch, TransportRegistry},
+		{BootcSwitch, "oci", "ghcr.io/frostyard/snow:testing"},
+		{BootcSwitch, TransportRegistry, "--help"},
+		{BootcSwitch, TransportRegistry, "-x"},
+		{BootcSwitch, TransportRegistry, "ghcr.io/frostyard/snow testing"},
+		{BootcSwitch, TransportRegistry, "ghcr.io/x;rm -rf /"},
+		{BootcSwitch, TransportRegistry, "$(id)"},
+		{BootcSwitch, TransportRegistry, ""},
+		{BootcSwitch, TransportRegistry, "ghcr.io/frostyard/snow:testing", "extra"},
+	}
+	for _, args := range tests {
+		if steps, err := Plan(args); err == nil {
+			t.Errorf("Plan(%q) = %+v, want an error", args, steps)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package updateall runs every update source — the bootc system image,
+// Flatpak installations and Homebrew — one after another as a single
+// tracked operation, reporting progress per step.
+//
+// It is free of any puregotk/GTK import so its sequencing can be
+// unit-tested on a headless host; the Updates page supplies a Reporter that
+// forwards progress to the main thread.
+package updateall
+
+import (
+	"context"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/bootc"
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+)
+
+// Timeout bounds a whole Update Everything run. Staging an image and
+// upgrading Homebrew can each take many minutes on a slow connection.
+const Timeout = 2 * time.Hour
+
+// Context returns a context with the Timeout, cancelled early if parent is
+func Context(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, Timeout)
+}
+
+// Step is one update source. Run reports output lines through progress
+// and returns when the source is up to date or has failed.
+type Step struct {
+	Name string
+	Run  func(ctx context.Context, progress func(line string)) error
+}
+
+// Reporter receives progress as Run works through the steps. Calls come
+// from Run's goroutine, in order.
+type Reporter interface {
+	StepStarted(index int, name string)
+	StepProgress(index int, line string)
+	StepFinished(index int, name string, err error)
+}
+
+// Result is the outcome of one step. Skipped is set for steps that never
+// started because ctx was done.
+type Result struct {
+	Name    string
+	Err     error
+	Skipped bool
+}
+
+// Run runs steps one at a time in order. A failing step does not stop the
+// ones after it, since the sources are independent; once ctx is done the
+// remaining steps are skipped.
+func Run(ctx context.Context, steps []Step, reporter Reporter) []Result {
+	results := make([]Result, len(steps))
+	for i, step := range steps {
+		results[i].Name = step.Name
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			results[i].Skipped = true
+			reporter.StepFinished(i, step.Name, err)
+			continue
+		}
+
+		reporter.StepStarted(i, step.Name)
+		err := step.Run(ctx, func(line string) {
+			reporter.StepProgress(i, line)
+		})
+		results[i].Err = err
+		reporter.StepFinished(i, step.Name, err)
+	}
+	return results
+}
+
+// Failed returns the names of steps that failed or were skipped
+func Failed(results []Result) []string {
+	var names []string
+	for _, r := range results {
+		if r.Err != nil {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// Sources says which update sources are available on this system
+type Sources struct {
+	Bootc    bool
+	Flatpak  bool
+	Homebrew bool
+}
+
+// Steps returns the steps for the available sources, system image first
+// so an interrupted run still leaves the most important update staged.
+func Steps(sources Sources) []Step {
+	var steps []Step
+	if sources.Bootc {
+		steps = append(steps, Step{Name: "System image", Run: stageBootc})
+	}
+	if sources.Flatpak {
+		steps = append(steps,
+			Step{Name: "Flatpak (user)", Run: updateFlatpak(true)},
+			Step{Name: "Flatpak (system)", Run: updateFlatpak(false)},
+		)
+	}
+	if sources.Homebrew {
+		steps = append(steps, Step{Name: "Homebrew", Run: upgradeHomebrew})
+	}
+	return steps
+}
+
+func stageBootc(ctx context.Context, progress func(string)) error {
+	progressCh := make(chan bootc.ProgressEvent)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- bootc.StageUpdate(ctx, progressCh)
+	}()
+	for event := range progressCh {
+		progress(event.Message)
+	}
+	return <-errCh
+}
+
+// updateFlatpak updates one installation. flatpak.UpdateAll allows as
+// long as Timeout, so the run's own deadline is what bounds it.
+func updateFlatpak(user bool) func(context.Context, func(string)) error {
+	return func(ctx context.Context, progress func(string)) error {
+		progress("Updating applications and runtimes...")
+		return flatpak.UpdateAll(ctx, user)
+	}
+}
+
+func upgradeHomebrew(ctx context.Context, progress func(string)) error {
+	progress("Updating Homebrew...")
+	if err := homebrew.Update(ctx); err != nil {
+		return err
+	}
+
+	eventCh := make(chan homebrew.BundleEvent)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- homebrew.UpgradeAllStreaming(ctx, eventCh)
+	}()
+	for event := range eventCh {
+		progress(event.Line)
+	}
+	return <-errCh
+}
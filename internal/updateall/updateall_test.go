@@ -0,0 +1,143 @@
+package updateall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+)
+
+// recorder is a Reporter that logs every call as a string.
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) StepStarted(index int, name string) {
+	r.calls = append(r.calls, fmt.Sprintf("start %d %s", index, name))
+}
+
+func (r *recorder) StepProgress(index int, line string) {
+	r.calls = append(r.calls, fmt.Sprintf("progress %d %s", index, line))
+}
+
+func (r *recorder) StepFinished(index int, name string, err error) {
+	r.calls = append(r.calls, fmt.Sprintf("finish %d %s %v", index, name, err))
+}
+
+func step(name string, err error) Step {
+	return Step{
+		Name: name,
+		Run: func(_ context.Context, progress func(string)) error {
+			progress(name + " working")
+			return err
+		},
+	}
+}
+
+// TestRunContinuesAfterFailure checks that steps run in order and that a
+// failing step does not stop the ones after it.
+func TestRunContinuesAfterFailure(t *testing.T) {
+	boom := errors.New("boom")
+	r := &recorder{}
+
+	results := Run(context.Background(), []Step{step("a", nil), step("b", boom), step("c", nil)}, r)
+
+	wantCalls := []string{
+		"start 0 a", "progress 0 a working", "finish 0 a <nil>",
+		"start 1 b", "progress 1 b working", "finish 1 b boom",
+		"start 2 c", "progress 2 c working", "finish 2 c <nil>",
+	}
+	if !reflect.DeepEqual(r.calls, wantCalls) {
+		t.Errorf("reporter calls =\n%v\nwant\n%v", r.calls, wantCalls)
+	}
+
+	wantResults := []Result{{Name: "a"}, {Name: "b", Err: boom}, {Name: "c"}}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("results = %+v, want %+v", results, wantResults)
+	}
+	if got := Failed(results); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Failed() = %v, want [b]", got)
+	}
+}
+
+// TestRunSkipsAfterCancel checks that once ctx is done the remaining steps
+// are reported finished without starting.
+func TestRunSkipsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &recorder{}
+
+	cancelling := Step{
+		Name: "a",
+		Run: func(context.Context, func(string)) error {
+			cancel()
+			return nil
+		},
+	}
+	results := Run(ctx, []Step{cancelling, step("b", nil)}, r)
+
+	wantCalls := []string{"start 0 a", "finish 0 a <nil>", "finish 1 b context canceled"}
+	if !reflect.DeepEqual(r.calls, wantCalls) {
+		t.Errorf("reporter calls =\n%v\nwant\n%v", r.calls, wantCalls)
+	}
+	if !results[1].Skipped || !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("results[1] = %+v, want skipped with context.Canceled", results[1])
+	}
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		sources Sources
+		want    []string
+	}{
+		{Sources{}, nil},
+		{Sources{Bootc: true, Flatpak: true, Homebrew: true}, []string{"System image", "Flatpak (user)", "Flatpak (system)", "Homebrew"}},
+		{Sources{Homebrew: true}, []string{"Homebrew"}},
+	}
+
+	for _, tt := range tests {
+		var names []string
+		for _, s := range Steps(tt.sources) {
+			names = append(names, s.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("Steps(%+v) = %v, want %v", tt.sources, names, tt.want)
+		}
+	}
+}
+
+// TestFlatpakStepUsesRunDeadline checks that a Flatpak step is bounded by
+// the run's context rather than the wrapper's short default: flatpak's own
+// limit for a whole installation is no shorter than Timeout, and a fake
+// flatpak that outlasts the run's deadline is stopped by it.
+func TestFlatpakStepUsesRunDeadline(t *testing.T) {
+	if flatpak.UpdateAllTimeout < Timeout {
+		t.Errorf("flatpak.UpdateAllTimeout = %v, want at least Timeout (%v)", flatpak.UpdateAllTimeout, Timeout)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "flatpak"), []byte("#!/bin/sh\nsleep 0.3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var lines []string
+	progress := func(line string) { lines = append(lines, line) }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := updateFlatpak(true)(ctx, progress); err != nil {
+		t.Errorf("Flatpak step = %v, want nil", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := updateFlatpak(false)(ctx, progress); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flatpak step past the run's deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
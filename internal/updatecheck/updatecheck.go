@@ -0,0 +1,102 @@
+// Package updatecheck re-runs the update checks in the background and
+// decides when their results are worth a desktop notification. It has no
+// GTK import, so the scheduling and the notification wording are tested
+// headlessly; the views supply the check itself.
+package updatecheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is how often updates are checked when the config does
+// not say
+const DefaultInterval = 6 * time.Hour
+
+// MinInterval keeps a misconfigured interval from hammering the remotes
+const MinInterval = 15 * time.Minute
+
+// ParseInterval parses a config interval such as "6h" or "90m". Empty
+// means DefaultInterval; anything shorter than MinInterval is raised to it.
+func ParseInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultInterval, nil
+	}
+	interval, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid update check interval %q: %w", s, err)
+	}
+	return max(interval, MinInterval), nil
+}
+
+// Counts is how many updates each source has
+type Counts struct {
+	System   int
+	Flatpak  int
+	Homebrew int
+	Features int
+}
+
+// Total returns the number of updates across all sources
+func (c Counts) Total() int {
+	return c.System + c.Flatpak + c.Homebrew + c.Features
+}
+
+// Notification returns the body of the notification to post after a check
+// moved the counts from before to after, and false when no source gained
+// updates. A source that shrank (something was upgraded) is not news.
+func Notification(before, after Counts) (string, bool) {
+	var parts []string
+	if after.System > before.System {
+		parts = append(parts, "a system update")
+	}
+	if n := after.Flatpak - before.Flatpak; n > 0 {
+		parts = append(parts, plural(n, "Flatpak update"))
+	}
+	if n := after.Homebrew - before.Homebrew; n > 0 {
+		parts = append(parts, plural(n, "Homebrew update"))
+	}
+	if n := after.Features - before.Features; n > 0 {
+		parts = append(parts, plural(n, "feature update"))
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+
+	var list string
+	switch len(parts) {
+	case 1:
+		list = parts[0]
+	case 2:
+		list = parts[0] + " and " + parts[1]
+	default:
+		list = strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+	}
+	return fmt.Sprintf("New: %s", list), true
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Run calls check every interval until ctx is done. It blocks, so callers
+// start it in a goroutine. The first check runs after one interval: the
+// pages already check once while they load.
+func Run(ctx context.Context, interval time.Duration, check func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check(ctx)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package updatecheck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: DefaultInterval},
+		{in: "6h", want: 6 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "1m", want: MinInterval},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterval(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseInterval(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseInterval(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNotification(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after Counts
+		want          string
+		wantOK        bool
+	}{
+		{name: "nothing changed", before: Counts{Flatpak: 2}, after: Counts{Flatpak: 2}},
+		{name: "only shrank", before: Counts{Homebrew: 5}, after: Counts{Homebrew: 1}},
+		{name: "one flatpak", after: Counts{Flatpak: 1}, want: "New: 1 Flatpak update", wantOK: true},
+		{
+			name:   "flatpak grew while homebrew shrank",
+			before: Counts{Flatpak: 1, Homebrew: 4},
+			after:  Counts{Flatpak: 3, Homebrew: 0},
+			want:   "New: 2 Flatpak updates",
+			wantOK: true,
+		},
+		{
+			name:   "every source",
+			after:  Counts{System: 1, Flatpak: 2, Homebrew: 1},
+			want:   "New: a system update, 2 Flatpak updates and 1 Homebrew update",
+			wantOK: true,
+		},
+		{
+			name:   "features",
+			before: Counts{Features: 1},
+			after:  Counts{Flatpak: 1, Features: 2},
+			want:   "New: 1 Flatpak update and 1 feature update",
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Notification(tt.before, tt.after)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Notification(%+v, %+v) = %q, %v, want %q, %v", tt.before, tt.after, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRunChecksEachIntervalUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	checks := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, 10*time.Millisecond, func(context.Context) { checks <- struct{}{} })
+		close(done)
+	}()
+
+	for range 2 {
+		select {
+		case <-checks:
+		case <-time.After(5 * time.Second):
+			t.Fatal("check did not run")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/frostyard/chairlift/internal/audit"
+
 	updexapi "github.com/frostyard/updex/updex"
 )
 
@@ -143,6 +145,13 @@ func DisableFeature(ctx context.Context, name string) error {
 	return err
 }
 
+// RemoveFeature disables a feature and deletes its downloaded extensions.
+// Extensions that are merged stay in use until the next boot.
+func RemoveFeature(ctx context.Context, name string) error {
+	_, _, err := runHelper(ctx, pkexecCommand, "remove-feature", name)
+	return err
+}
+
 // UpdateFeatures downloads enabled features
 func UpdateFeatures(ctx context.Context) error {
 	_, _, err := runHelper(ctx, pkexecCommand, "update")
@@ -173,6 +182,7 @@ func runHelper(ctx context.Context, pkexecPath string, args ...string) (string,
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	audit.Record("updex", args, err)
 
 	if stderr.Len() > 0 {
 		log.Printf("updex helper stderr: %s", stderr.String())
@@ -77,6 +77,9 @@ func TestEnableDisableUpdateFeaturesDryRunNeverInvokePkexec(t *testing.T) {
 	if err := DisableFeature(ctx, "demo"); err != nil {
 		t.Errorf("DisableFeature dry-run: %v", err)
 	}
+	if err := RemoveFeature(ctx, "demo"); err != nil {
+		t.Errorf("RemoveFeature dry-run: %v", err)
+	}
 	if err := UpdateFeatures(ctx); err != nil {
 		t.Errorf("UpdateFeatures dry-run: %v", err)
 	}
@@ -40,6 +40,14 @@ func DisableOptions(dryRun bool) updex.DisableFeatureOptions {
 	return updex.DisableFeatureOptions{DryRun: dryRun}
 }
 
+// RemoveOptions builds the updex.DisableFeatureOptions for the
+// remove-feature subcommand: the feature is disabled and its downloaded
+// extensions are deleted at once (Now), even while merged (Force, so the
+// removal completes on the next boot). DryRun is set to exactly dryRun.
+func RemoveOptions(dryRun bool) updex.DisableFeatureOptions {
+	return updex.DisableFeatureOptions{Now: true, Force: true, DryRun: dryRun}
+}
+
 // UpdateOptions builds the updex.UpdateFeaturesOptions for the update
 // subcommand, with DryRun set to exactly dryRun. Previously main.go passed
 // a zero-value updex.UpdateFeaturesOptions{} here, silently dropping the
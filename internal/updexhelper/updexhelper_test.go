@@ -68,6 +68,20 @@ func TestDisableOptions(t *testing.T) {
 	}
 }
 
+// TestRemoveOptions asserts DryRun is set to exactly the bool passed and
+// that removal always deletes files now, merged or not.
+func TestRemoveOptions(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		got := RemoveOptions(dryRun)
+		if got.DryRun != dryRun {
+			t.Errorf("RemoveOptions(%v).DryRun = %v, want %v", dryRun, got.DryRun, dryRun)
+		}
+		if !got.Now || !got.Force {
+			t.Errorf("RemoveOptions(%v) = %+v, want Now and Force set", dryRun, got)
+		}
+	}
+}
+
 // TestUpdateOptions asserts DryRun is set to exactly the bool passed, for
 // both true and false. This is the direct fix for
 // cmd/chairlift-updex-helper/main.go's update case previously constructing
@@ -1,10 +1,12 @@
 // Package actionmsg builds the toast text (and, where the action itself is
 // gated by dry-run, the execution decision) for maintenance-page,
 // applications-page, updates-page, and features-page actions: Homebrew
-// Brewfile dumps, Homebrew/Flatpak cleanup, Homebrew package
+// Brewfile dumps, Homebrew/Flatpak cleanup, Flatpak repair, Homebrew package
 // installs/upgrades/self-updates, Flatpak application uninstalls/updates,
+// Flatpak remote additions/removals,
 // Homebrew tap trust, bootc system update staging, configured custom
-// maintenance scripts, and system feature toggles/updates.
+// maintenance scripts, system feature toggles/updates/removals, systemd
+// service actions, journal vacuuming, and container and image actions.
 //
 // It is deliberately free of any puregotk/GTK import, following the
 // internal/views/trustmsg pattern, so its logic can be unit-tested on a
@@ -13,11 +15,15 @@
 // test function runs — so logic that must be tested cannot live in the view
 // packages. See docs/agents/skills/gtk-headless-tests.md.
 //
-// Functions whose result only selects display text (BundleDump, Cleanup,
-// Install, Uninstall, Upgrade, Update, SelfUpdate, BootcStage, FeatureUpdate)
+// Functions whose result only selects display text (BundleDump, Cleanup, FlatpakRepair,
+// Install, Uninstall, RemoteAdd, RemoteRemove, Upgrade, Update, SelfUpdate,
+// BootcStage, BootcRollback, BootcSwitch, Restart, RestartScheduled,
+// FeatureUpdate, FeatureRemove, ServiceAction, JournalVacuum, ContainerAction,
+// ImagePrune)
 // return a plain string: the state-changing/no-op decision for those actions
 // is already made and already tested inside their wrapper package
-// (internal/homebrew, internal/flatpak, internal/bootc, internal/updex).
+// (internal/homebrew, internal/flatpak, internal/bootc, internal/power,
+// internal/updex, internal/systemd, internal/containers).
 // Functions whose result gates a further decision that has no wrapper
 // package of its own to make it (MaintenanceScript, for configured custom
 // scripts) return a decision struct instead of a plain string, precisely so
@@ -31,6 +37,9 @@
 // feature's switch confirms its new visual state — which has no
 // wrapper-package equivalent to decide it, so TapTrustDecision.MutateUI and
 // FeatureToggleDecision.Confirm are what actionmsg_test.go asserts on.
+// BundleDumpSaved follows the same shape for the Brewfile dump toast's
+// "Open" button, which must not be offered for a file that was never
+// written.
 package actionmsg
 
 import "fmt"
@@ -47,6 +56,40 @@ func BundleDump(dryRun bool, path string) string {
 	return fmt.Sprintf("Brewfile saved to %s", path)
 }
 
+// BundleDumpDecision is the result of deciding whether the Brewfile dump
+// toast should offer to open the saved file, and what toast to show.
+type BundleDumpDecision struct {
+	// OfferOpen is true when the Brewfile was actually written and the
+	// toast should carry an "Open" button. It is exactly !dryRun — under
+	// dry-run nothing is written, so an "Open" button would show a stale
+	// Brewfile from an earlier run, or fail on a missing one.
+	OfferOpen bool
+	// Toast is the completion message, the same text BundleDump returns.
+	Toast string
+}
+
+// BundleDumpSaved decides whether the Brewfile dump completion toast
+// (onBrewBundleDumpClicked in internal/views/maintenance_page.go) offers an
+// "Open" button for path. OfferOpen is exactly !dryRun; the caller must not
+// independently recompute that condition.
+func BundleDumpSaved(dryRun bool, path string) BundleDumpDecision {
+	return BundleDumpDecision{
+		OfferOpen: !dryRun,
+		Toast:     BundleDump(dryRun, path),
+	}
+}
+
+// BundleInstall returns the toast text for a successful Brewfile install.
+// homebrew.BundleInstallStreaming skips `brew bundle install` under dry-run
+// (bundle is one of homebrew's stateChangingCommands), so no counts exist
+// then and this function only says what would have happened.
+func BundleInstall(dryRun bool, name string, installed, using int) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: packages from %s would be installed — no changes made", name)
+	}
+	return fmt.Sprintf("%s: %d installed, %d already present", name, installed, using)
+}
+
 // Cleanup returns the toast text for a Homebrew or Flatpak cleanup action.
 // The wrapper package (internal/homebrew or internal/flatpak) already skips
 // the state-changing cleanup command under dry-run and returns a mock
@@ -60,6 +103,17 @@ func Cleanup(dryRun bool, tool string, output string) string {
 	return fmt.Sprintf("%s cleanup completed", tool)
 }
 
+// FlatpakRepair returns the toast text for a successful repair of the user
+// or system Flatpak installation (installation is "user" or "system").
+// flatpak.Repair skips the command under dry-run, so this function only
+// selects which string to show.
+func FlatpakRepair(dryRun bool, installation string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: the %s Flatpak installation would be repaired — no changes made", installation)
+	}
+	return fmt.Sprintf("The %s Flatpak installation was verified and repaired", installation)
+}
+
 // Install returns the toast text for a Homebrew package install. The
 // wrapper package (internal/homebrew) already skips the state-changing
 // `brew install` command under dry-run — install is one of homebrew's
@@ -85,12 +139,103 @@ func Uninstall(dryRun bool, appID string) string {
 	return fmt.Sprintf("%s uninstalled", appID)
 }
 
+// RemoteAdd returns the toast text for adding a Flatpak remote. The wrapper
+// package (internal/flatpak) already skips the state-changing
+// `flatpak remote-add` command under dry-run, so this function only selects
+// which string to show.
+func RemoteAdd(dryRun bool, name string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: remote %s would be added — no changes made", name)
+	}
+	return fmt.Sprintf("Remote %s added", name)
+}
+
+// RemoteRemove returns the toast text for deleting a Flatpak remote. The
+// wrapper package (internal/flatpak) already skips the state-changing
+// `flatpak remote-delete` command under dry-run, so this function only
+// selects which string to show.
+func RemoteRemove(dryRun bool, name string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: remote %s would be removed — no changes made", name)
+	}
+	return fmt.Sprintf("Remote %s removed", name)
+}
+
+// Downgrade returns the toast text for deploying an earlier commit of a
+// Flatpak application. The wrapper package (internal/flatpak) already skips
+// the state-changing `flatpak update --commit` command under dry-run, so
+// this function only selects which string to show.
+func Downgrade(dryRun bool, appID string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be switched to the selected version — no changes made", appID)
+	}
+	return fmt.Sprintf("%s switched to the selected version", appID)
+}
+
+// Hold returns the toast text for masking (hold true) or unmasking a Flatpak
+// application. The wrapper package (internal/flatpak) already skips the
+// state-changing `flatpak mask` command under dry-run, so this function only
+// selects which string to show.
+func Hold(dryRun bool, hold bool, appID string) string {
+	switch {
+	case dryRun && hold:
+		return fmt.Sprintf("[DRY-RUN] Preview: updates to %s would be held — no changes made", appID)
+	case dryRun:
+		return fmt.Sprintf("[DRY-RUN] Preview: the hold on %s would be released — no changes made", appID)
+	case hold:
+		return fmt.Sprintf("Updates to %s are held", appID)
+	default:
+		return fmt.Sprintf("Hold on %s released", appID)
+	}
+}
+
+// Pin returns the toast text for pinning (pin true) or unpinning a Homebrew
+// formula. The wrapper package (internal/homebrew) already skips the
+// state-changing `brew pin`/`brew unpin` commands under dry-run, so this
+// function only selects which string to show.
+func Pin(dryRun bool, pin bool, name string) string {
+	switch {
+	case dryRun && pin:
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be pinned — no changes made", name)
+	case dryRun:
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be unpinned — no changes made", name)
+	case pin:
+		return fmt.Sprintf("%s pinned; it will be skipped by upgrades", name)
+	default:
+		return fmt.Sprintf("%s unpinned", name)
+	}
+}
+
+// UpdateEverything returns the toast text for a successful Update
+// Everything run. Every wrapper package updateall drives (bootc, flatpak,
+// homebrew) already skips its state-changing commands under dry-run, and
+// the dry-run flag is set for all of them together at startup, so this
+// function only selects which string to show.
+func UpdateEverything(dryRun bool) string {
+	if dryRun {
+		return "[DRY-RUN] Preview: every source would be updated — no changes made"
+	}
+	return "Everything is up to date"
+}
+
+// ManifestImport returns the toast text for a successful manifest import.
+// The import runs through the flatpak, homebrew and updex wrappers, which
+// skip their state-changing commands under dry-run, so this function only
+// selects which string to show.
+func ManifestImport(dryRun bool) string {
+	if dryRun {
+		return "[DRY-RUN] Preview: the manifest would be applied — no changes made"
+	}
+	return "Manifest applied"
+}
+
 // Upgrade returns the toast text for a per-package Homebrew upgrade. The
 // wrapper package (internal/homebrew) already skips the state-changing
 // `brew upgrade` command under dry-run — upgrade is one of homebrew's
 // stateChangingCommands — so this function only selects which string to
 // show: a preview when dryRun is true, or a fixed completion message when
-// the upgrade actually ran.
+// the upgrade actually ran. The Developer Tools page uses it too; the
+// devtools wrapper likewise skips its upgrade command under dry-run.
 func Upgrade(dryRun bool, pkgName string) string {
 	if dryRun {
 		return fmt.Sprintf("[DRY-RUN] Preview: %s would be upgraded — no changes made", pkgName)
@@ -110,6 +255,34 @@ func Update(dryRun bool, appID string) string {
 	return fmt.Sprintf("%s updated", appID)
 }
 
+// BatchUpdate returns the toast text for a successful batch of Flatpak
+// updates run from the Updates page's selection mode. Like Update, the
+// wrapper already skips each `flatpak update` under dry-run.
+func BatchUpdate(dryRun bool, count int) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be updated — no changes made", plural(count, "application"))
+	}
+	return fmt.Sprintf("%s updated", plural(count, "application"))
+}
+
+// BatchUpgrade returns the toast text for a successful batch of Homebrew
+// upgrades run from the Updates page's selection mode, and for the
+// Developer Tools page's Upgrade All.
+func BatchUpgrade(dryRun bool, count int) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be upgraded — no changes made", plural(count, "package"))
+	}
+	return fmt.Sprintf("%s upgraded", plural(count, "package"))
+}
+
+// plural formats count with noun, adding an "s" unless count is one
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
 // SelfUpdate returns the toast text for a package manager self-update (e.g.
 // Homebrew's own `brew update`). The wrapper package already skips the
 // state-changing update command under dry-run, so this function only selects
@@ -147,6 +320,51 @@ func BootcStage(dryRun bool, staged bool) string {
 	return "System is up to date"
 }
 
+// BootcRollback returns the toast text for a successful System page
+// rollback. bootc.Rollback skips pkexec under dry-run, so this function
+// only selects which string to show; version names the deployment that
+// the next boot starts, and may be empty when the image carries no
+// version label.
+func BootcRollback(dryRun bool, version string) string {
+	if dryRun {
+		return "[DRY-RUN] Preview: the previous deployment would be queued for the next boot — no changes made"
+	}
+	if version == "" {
+		return "Rollback queued. Restart to boot the previous version."
+	}
+	return fmt.Sprintf("Rollback queued. Restart to boot %s.", version)
+}
+
+// BootcSwitch returns the toast text for a successful System page image
+// switch to image. bootc.Switch skips pkexec under dry-run, so this
+// function only selects which string to show.
+func BootcSwitch(dryRun bool, image string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: the system would switch to %s — no changes made", image)
+	}
+	return fmt.Sprintf("Switched to %s. Restart to apply.", image)
+}
+
+// Restart returns the toast text for a Restart Now request. Under dry-run
+// power.Reboot runs nothing, so the toast must say the machine stays up;
+// otherwise the session is about to end and the toast is rarely seen.
+func Restart(dryRun bool) string {
+	if dryRun {
+		return "[DRY-RUN] Preview: the computer would restart now — no changes made"
+	}
+	return "Restarting..."
+}
+
+// RestartScheduled returns the toast text for a restart scheduled at at
+// (already formatted for display). power.ScheduleReboot skips the command
+// under dry-run, so this function only selects which string to show.
+func RestartScheduled(dryRun bool, at string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: a restart would be scheduled for %s — no changes made", at)
+	}
+	return fmt.Sprintf("Restart scheduled for %s", at)
+}
+
 // TapTrustDecision is the result of deciding whether trusting a Homebrew tap
 // should mutate the Untrusted Homebrew Taps UI (remove the tap's row, hide
 // the group when empty, refresh outdated packages), and what toast to show
@@ -285,3 +503,82 @@ func FeatureUpdate(dryRun bool) string {
 	}
 	return "Features updated. Changes apply after reboot."
 }
+
+// FeatureRemove returns the toast text for a feature's Remove button.
+// updex.RemoveFeature skips pkexec under dry-run, so this function only
+// selects which string to show.
+func FeatureRemove(dryRun bool, name string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be removed — no changes made", name)
+	}
+	return fmt.Sprintf("%s removed. Reboot to complete.", name)
+}
+
+// serviceActionDone maps a systemctl verb to the past participle the toast
+// uses for it
+var serviceActionDone = map[string]string{
+	"start":   "started",
+	"stop":    "stopped",
+	"restart": "restarted",
+	"enable":  "enabled",
+	"disable": "disabled",
+}
+
+// ServiceAction returns the toast text for a start, stop, restart, enable
+// or disable on the Services page. systemd.Start and friends skip
+// systemctl under dry-run, so this function only selects which string to
+// show.
+func ServiceAction(dryRun bool, verb, unit string) string {
+	done, ok := serviceActionDone[verb]
+	if !ok {
+		done = verb
+	}
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be %s — no changes made", unit, done)
+	}
+	return fmt.Sprintf("%s %s", unit, done)
+}
+
+// JournalVacuum returns the toast text for the Storage page's journal
+// Clean Up button. systemd.VacuumJournal skips the system helper under dry-run, so
+// this function only selects which string to show.
+func JournalVacuum(dryRun bool, size string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: the journal would be trimmed to %s — no changes made", size)
+	}
+	return fmt.Sprintf("Journal trimmed to %s", size)
+}
+
+// containerActionDone maps a container or image action to the past
+// participle the toast uses for it
+var containerActionDone = map[string]string{
+	"start":  "started",
+	"stop":   "stopped",
+	"remove": "removed",
+}
+
+// ContainerAction returns the toast text for a start, stop or remove of a
+// container, or a remove of an image, on the Containers page.
+// containers.Engine's methods skip the API call under dry-run, so this
+// function only selects which string to show.
+func ContainerAction(dryRun bool, verb, name string) string {
+	done, ok := containerActionDone[verb]
+	if !ok {
+		done = verb
+	}
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: %s would be %s — no changes made", name, done)
+	}
+	return fmt.Sprintf("%s %s", name, done)
+}
+
+// ImagePrune returns the toast text for the Containers page's Remove
+// Unused Images button, with the space freed already formatted.
+// containers.Engine.PruneImages skips the API call under dry-run, so this
+// function only selects which string to show.
+func ImagePrune(dryRun bool, engine, freed string) string {
+	if dryRun {
+		return fmt.Sprintf("[DRY-RUN] Preview: unused %s images would be removed — no changes made", engine)
+	}
+	return fmt.Sprintf("Unused %s images removed, %s freed", engine, freed)
+}
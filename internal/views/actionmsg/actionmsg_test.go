@@ -1,6 +1,7 @@
 package actionmsg
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -44,6 +45,22 @@ func TestBundleDump(t *testing.T) {
 	}
 }
 
+// TestBundleDumpSaved covers both dry-run states for the Brewfile dump
+// toast's "Open" button: OfferOpen must be exactly !dryRun, and Toast must
+// match BundleDump for the same inputs.
+func TestBundleDumpSaved(t *testing.T) {
+	const path = "/home/user/Brewfile"
+	for _, dryRun := range []bool{false, true} {
+		got := BundleDumpSaved(dryRun, path)
+		if got.OfferOpen != !dryRun {
+			t.Errorf("BundleDumpSaved(%v, %q).OfferOpen = %v, want %v", dryRun, path, got.OfferOpen, !dryRun)
+		}
+		if want := BundleDump(dryRun, path); got.Toast != want {
+			t.Errorf("BundleDumpSaved(%v, %q).Toast = %q, want %q", dryRun, path, got.Toast, want)
+		}
+	}
+}
+
 // TestCleanup covers both dry-run states for the Homebrew/Flatpak cleanup
 // toast text. This is the extraction of onBrewCleanupClicked's and
 // onFlatpakCleanupClicked's already-correct message selection into a tested,
@@ -176,6 +193,183 @@ func TestUninstall(t *testing.T) {
 	}
 }
 
+// TestRemoteToasts covers both dry-run states for the Flatpak remote
+// add and remove toast texts.
+func TestRemoteToasts(t *testing.T) {
+	funcs := []struct {
+		name     string
+		fn       func(bool, string) string
+		liveWant string
+	}{
+		{"RemoteAdd", RemoteAdd, "Remote flathub added"},
+		{"RemoteRemove", RemoteRemove, "Remote flathub removed"},
+	}
+
+	for _, f := range funcs {
+		t.Run(f.name, func(t *testing.T) {
+			if got := f.fn(false, "flathub"); got != f.liveWant {
+				t.Errorf("%s(false, %q) = %q, want %q", f.name, "flathub", got, f.liveWant)
+			}
+			got := f.fn(true, "flathub")
+			for _, want := range []string{"[DRY-RUN]", "flathub", "no changes made"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("%s(true, %q) = %q, want it to contain %q", f.name, "flathub", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDowngrade covers both dry-run states for the Flatpak version switch
+// toast text.
+func TestDowngrade(t *testing.T) {
+	if got, want := Downgrade(false, "org.gnome.Loupe"), "org.gnome.Loupe switched to the selected version"; got != want {
+		t.Errorf("Downgrade(false) = %q, want %q", got, want)
+	}
+	got := Downgrade(true, "org.gnome.Loupe")
+	for _, want := range []string{"[DRY-RUN]", "org.gnome.Loupe", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Downgrade(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// TestHold covers every dry-run and hold/release combination for the
+// Flatpak mask toast text.
+func TestHold(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+		hold   bool
+		want   []string
+	}{
+		{"live hold", false, true, []string{"Updates to org.gnome.Loupe are held"}},
+		{"live release", false, false, []string{"Hold on org.gnome.Loupe released"}},
+		{"dry-run hold", true, true, []string{"[DRY-RUN]", "would be held", "no changes made"}},
+		{"dry-run release", true, false, []string{"[DRY-RUN]", "would be released", "no changes made"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Hold(tt.dryRun, tt.hold, "org.gnome.Loupe")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Hold(%v, %v) = %q, want it to contain %q", tt.dryRun, tt.hold, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestPin covers every dry-run and pin/unpin combination for the Homebrew
+// pin toggle toast text.
+func TestPin(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+		pin    bool
+		want   []string
+	}{
+		{"live pin", false, true, []string{"wget pinned"}},
+		{"live unpin", false, false, []string{"wget unpinned"}},
+		{"dry-run pin", true, true, []string{"[DRY-RUN]", "would be pinned", "no changes made"}},
+		{"dry-run unpin", true, false, []string{"[DRY-RUN]", "would be unpinned", "no changes made"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Pin(tt.dryRun, tt.pin, "wget")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Pin(%v, %v) = %q, want it to contain %q", tt.dryRun, tt.pin, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestBundleInstall covers both dry-run states for the Brewfile install
+// toast text.
+func TestBundleInstall(t *testing.T) {
+	if got, want := BundleInstall(false, "dev", 3, 2), "dev: 3 installed, 2 already present"; got != want {
+		t.Errorf("BundleInstall(false) = %q, want %q", got, want)
+	}
+	got := BundleInstall(true, "dev", 0, 0)
+	for _, want := range []string{"[DRY-RUN]", "dev", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BundleInstall(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// TestUpdateEverything covers both dry-run states for the Update
+// Everything toast text.
+func TestUpdateEverything(t *testing.T) {
+	if got, want := UpdateEverything(false), "Everything is up to date"; got != want {
+		t.Errorf("UpdateEverything(false) = %q, want %q", got, want)
+	}
+	got := UpdateEverything(true)
+	for _, want := range []string{"[DRY-RUN]", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("UpdateEverything(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestFlatpakRepair(t *testing.T) {
+	for _, installation := range []string{"user", "system"} {
+		got := FlatpakRepair(false, installation)
+		if want := fmt.Sprintf("The %s Flatpak installation was verified and repaired", installation); got != want {
+			t.Errorf("FlatpakRepair(false, %q) = %q, want %q", installation, got, want)
+		}
+		got = FlatpakRepair(true, installation)
+		for _, want := range []string{"[DRY-RUN]", installation, "no changes made"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("FlatpakRepair(true, %q) = %q, want it to contain %q", installation, got, want)
+			}
+		}
+	}
+}
+
+func TestManifestImport(t *testing.T) {
+	if got, want := ManifestImport(false), "Manifest applied"; got != want {
+		t.Errorf("ManifestImport(false) = %q, want %q", got, want)
+	}
+	got := ManifestImport(true)
+	for _, want := range []string{"[DRY-RUN]", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ManifestImport(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// TestBatch covers both dry-run states and the singular/plural wording of
+// the selection-mode batch toasts.
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{BatchUpdate(false, 1), "1 application updated"},
+		{BatchUpdate(false, 7), "7 applications updated"},
+		{BatchUpgrade(false, 1), "1 package upgraded"},
+		{BatchUpgrade(false, 3), "3 packages upgraded"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	for _, got := range []string{BatchUpdate(true, 2), BatchUpgrade(true, 2)} {
+		for _, want := range []string{"[DRY-RUN]", "2 ", "no changes made"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("%q does not contain %q", got, want)
+			}
+		}
+	}
+}
+
 // TestUpgrade covers both dry-run states for the per-package Homebrew
 // upgrade toast text.
 func TestUpgrade(t *testing.T) {
@@ -590,3 +784,137 @@ func TestFeatureUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureRemove(t *testing.T) {
+	if got, want := FeatureRemove(false, "docker"), "docker removed. Reboot to complete."; got != want {
+		t.Errorf("FeatureRemove(false) = %q, want %q", got, want)
+	}
+	got := FeatureRemove(true, "docker")
+	for _, want := range []string{"[DRY-RUN]", "docker", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FeatureRemove(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestBootcRollback(t *testing.T) {
+	if got, want := BootcRollback(false, "42.20260101"), "Rollback queued. Restart to boot 42.20260101."; got != want {
+		t.Errorf("BootcRollback(false, version) = %q, want %q", got, want)
+	}
+	if got, want := BootcRollback(false, ""), "Rollback queued. Restart to boot the previous version."; got != want {
+		t.Errorf("BootcRollback(false, \"\") = %q, want %q", got, want)
+	}
+	got := BootcRollback(true, "42.20260101")
+	for _, want := range []string{"[DRY-RUN]", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BootcRollback(true, version) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestBootcSwitch(t *testing.T) {
+	const image = "ghcr.io/frostyard/snow:testing"
+	if got, want := BootcSwitch(false, image), "Switched to "+image+". Restart to apply."; got != want {
+		t.Errorf("BootcSwitch(false, image) = %q, want %q", got, want)
+	}
+	got := BootcSwitch(true, image)
+	for _, want := range []string{"[DRY-RUN]", image, "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BootcSwitch(true, image) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestRestart(t *testing.T) {
+	if got, want := Restart(false), "Restarting..."; got != want {
+		t.Errorf("Restart(false) = %q, want %q", got, want)
+	}
+	if got := Restart(true); !strings.Contains(got, "[DRY-RUN]") || !strings.Contains(got, "no changes made") {
+		t.Errorf("Restart(true) = %q, want a dry-run preview", got)
+	}
+}
+
+func TestRestartScheduled(t *testing.T) {
+	if got, want := RestartScheduled(false, "03:00"), "Restart scheduled for 03:00"; got != want {
+		t.Errorf("RestartScheduled(false) = %q, want %q", got, want)
+	}
+	got := RestartScheduled(true, "03:00")
+	for _, want := range []string{"[DRY-RUN]", "03:00", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RestartScheduled(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestServiceAction(t *testing.T) {
+	tests := []struct {
+		verb string
+		want string
+	}{
+		{"start", "sshd.service started"},
+		{"stop", "sshd.service stopped"},
+		{"restart", "sshd.service restarted"},
+		{"enable", "sshd.service enabled"},
+		{"disable", "sshd.service disabled"},
+	}
+	for _, tt := range tests {
+		if got := ServiceAction(false, tt.verb, "sshd.service"); got != tt.want {
+			t.Errorf("ServiceAction(false, %q) = %q, want %q", tt.verb, got, tt.want)
+		}
+		got := ServiceAction(true, tt.verb, "sshd.service")
+		for _, want := range []string{"[DRY-RUN]", "sshd.service", "no changes made"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("ServiceAction(true, %q) = %q, want it to contain %q", tt.verb, got, want)
+			}
+		}
+	}
+}
+
+func TestJournalVacuum(t *testing.T) {
+	if got, want := JournalVacuum(false, "500M"), "Journal trimmed to 500M"; got != want {
+		t.Errorf("JournalVacuum(false) = %q, want %q", got, want)
+	}
+	got := JournalVacuum(true, "500M")
+	for _, want := range []string{"[DRY-RUN]", "500M", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JournalVacuum(true) = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestContainerAction(t *testing.T) {
+	tests := []struct {
+		verb string
+		want string
+	}{
+		{"start", "web started"},
+		{"stop", "web stopped"},
+		{"remove", "web removed"},
+	}
+	for _, tt := range tests {
+		if got := ContainerAction(false, tt.verb, "web"); got != tt.want {
+			t.Errorf("ContainerAction(false, %q) = %q, want %q", tt.verb, got, tt.want)
+		}
+		got := ContainerAction(true, tt.verb, "web")
+		for _, want := range []string{"[DRY-RUN]", "web", "no changes made"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("ContainerAction(true, %q) = %q, want it to contain %q", tt.verb, got, want)
+			}
+		}
+	}
+}
+
+func TestImagePrune(t *testing.T) {
+	if got, want := ImagePrune(false, "Podman", "1.2 GB"), "Unused Podman images removed, 1.2 GB freed"; got != want {
+		t.Errorf("ImagePrune(false) = %q, want %q", got, want)
+	}
+	got := ImagePrune(true, "Podman", "0 B")
+	for _, want := range []string{"[DRY-RUN]", "Podman", "no changes made"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ImagePrune(true) = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "0 B") {
+		t.Errorf("ImagePrune(true) = %q, want no freed size", got)
+	}
+}
@@ -1,18 +1,23 @@
 package views
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 
 	"github.com/frostyard/chairlift/internal/flatpak"
 	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/pkgsearch"
 	"github.com/frostyard/chairlift/internal/views/actionmsg"
 
 	sgtk "github.com/frostyard/snowkit/gtk"
 
 	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gdk"
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 )
 
@@ -62,6 +67,7 @@ func (uh *UserHome) buildApplicationsPage() {
 		uh.flatpakUserExpander.SetTitle("User Applications")
 		uh.flatpakUserExpander.SetSubtitle("Loading...")
 		group.Add(&uh.flatpakUserExpander.Widget)
+		uh.addRefreshButton(group, uh.loadFlatpakApplications, uh.lists.flatpakUser)
 
 		page.Add(group)
 	}
@@ -76,6 +82,7 @@ func (uh *UserHome) buildApplicationsPage() {
 		uh.flatpakSystemExpander.SetTitle("System Applications")
 		uh.flatpakSystemExpander.SetSubtitle("Loading...")
 		group.Add(&uh.flatpakSystemExpander.Widget)
+		uh.addRefreshButton(group, uh.loadFlatpakApplications, uh.lists.flatpakSystem)
 
 		page.Add(group)
 	}
@@ -86,11 +93,34 @@ func (uh *UserHome) buildApplicationsPage() {
 		go uh.loadFlatpakApplications()
 	}
 
+	// Flatpak Remotes group - hidden if Flatpak is not installed
+	if uh.config.IsGroupEnabled("applications_page", "flatpak_remotes_group") {
+		group := adw.NewPreferencesGroup()
+		group.SetTitle("Flatpak Remotes")
+		group.SetDescription("Repositories Flatpak applications are installed from")
+		uh.flatpakRemotesGroup = group
+
+		addBtn := gtk.NewButtonFromIconName("list-add-symbolic")
+		addBtn.SetValign(gtk.AlignCenterValue)
+		addBtn.AddCssClass("flat")
+		addBtn.SetTooltipText("Add Remote")
+		addClickedCb := func(btn gtk.Button) {
+			uh.showAddRemoteDialog()
+		}
+		addBtn.ConnectClicked(&addClickedCb)
+		group.SetHeaderSuffix(&addBtn.Widget)
+
+		page.Add(group)
+
+		go uh.loadFlatpakRemotes()
+	}
+
 	// Homebrew group
 	if uh.config.IsGroupEnabled("applications_page", "brew_group") {
 		group := adw.NewPreferencesGroup()
 		group.SetTitle("Homebrew")
 		group.SetDescription("Manage Homebrew packages installed on your system")
+		uh.addRefreshButton(group, uh.loadHomebrewPackages, uh.lists.formulae, uh.lists.casks)
 
 		// Bundle dump row
 		dumpRow := adw.NewActionRow()
@@ -114,6 +144,16 @@ func (uh *UserHome) buildApplicationsPage() {
 		uh.formulaeExpander.SetSubtitle("Loading...")
 		group.Add(&uh.formulaeExpander.Widget)
 
+		uh.pinnedFilterBtn = gtk.NewToggleButtonWithLabel("Pinned")
+		uh.pinnedFilterBtn.SetValign(gtk.AlignCenterValue)
+		uh.pinnedFilterBtn.SetTooltipText("Show only pinned formulae")
+		uh.pinnedFilterBtn.AddCssClass("flat")
+		pinnedFilterCb := func(_ gtk.ToggleButton) {
+			uh.applyPinnedFilter()
+		}
+		uh.pinnedFilterBtn.ConnectToggled(&pinnedFilterCb)
+		uh.formulaeExpander.AddSuffix(&uh.pinnedFilterBtn.Widget)
+
 		// Casks expander
 		uh.casksExpander = adw.NewExpanderRow()
 		uh.casksExpander.SetTitle("Casks")
@@ -126,11 +166,25 @@ func (uh *UserHome) buildApplicationsPage() {
 		go uh.loadHomebrewPackages()
 	}
 
-	// Homebrew Search group
+	// Package Search group - one search box across Homebrew and Flatpak,
+	// each source enabled by its own group key
 	if uh.config.IsGroupEnabled("applications_page", "brew_search_group") {
+		uh.searchSources = append(uh.searchSources, pkgsearch.SourceFormula, pkgsearch.SourceCask)
+	}
+	if uh.config.IsGroupEnabled("applications_page", "flatpak_search_group") {
+		uh.searchSources = append(uh.searchSources, pkgsearch.SourceFlatpak)
+	}
+	if len(uh.searchSources) > 0 {
 		group := adw.NewPreferencesGroup()
-		group.SetTitle("Search Homebrew")
-		group.SetDescription("Search for and install Homebrew formulae")
+		group.SetTitle("Search Packages")
+		switch {
+		case !slices.Contains(uh.searchSources, pkgsearch.SourceFlatpak):
+			group.SetDescription("Search Homebrew, and install with one click")
+		case !slices.Contains(uh.searchSources, pkgsearch.SourceFormula):
+			group.SetDescription("Search Flatpak remotes, and install with one click")
+		default:
+			group.SetDescription("Search Homebrew and Flatpak remotes, and install with one click")
+		}
 
 		// Search entry row
 		searchRow := adw.NewActionRow()
@@ -140,25 +194,73 @@ func (uh *UserHome) buildApplicationsPage() {
 		uh.searchEntry.SetHexpand(true)
 
 		searchActivateCb := func(entry gtk.SearchEntry) {
-			uh.onHomebrewSearch()
+			uh.onPackageSearch()
 		}
 		uh.searchEntry.ConnectActivate(&searchActivateCb)
 
 		searchRow.AddSuffix(&uh.searchEntry.Widget)
 		group.Add(&searchRow.Widget)
 
-		// Search results expander
+		// One results expander per source
 		uh.searchResultsExpander = adw.NewExpanderRow()
-		uh.searchResultsExpander.SetTitle("Search Results")
-		uh.searchResultsExpander.SetSubtitle("No search performed")
-		uh.searchResultsExpander.SetEnableExpansion(false)
-		group.Add(&uh.searchResultsExpander.Widget)
+		uh.searchResultsExpander.SetTitle("Homebrew Formulae")
+		uh.caskResultsExpander = adw.NewExpanderRow()
+		uh.caskResultsExpander.SetTitle("Homebrew Casks")
+		uh.flatpakResultsExpander = adw.NewExpanderRow()
+		uh.flatpakResultsExpander.SetTitle("Flatpak Applications")
+
+		uh.searchResultsExpander.SetVisible(slices.Contains(uh.searchSources, pkgsearch.SourceFormula))
+		uh.caskResultsExpander.SetVisible(slices.Contains(uh.searchSources, pkgsearch.SourceCask))
+		uh.flatpakResultsExpander.SetVisible(slices.Contains(uh.searchSources, pkgsearch.SourceFlatpak))
+
+		for _, expander := range []*adw.ExpanderRow{uh.searchResultsExpander, uh.caskResultsExpander, uh.flatpakResultsExpander} {
+			expander.SetSubtitle("No search performed")
+			expander.SetEnableExpansion(false)
+			group.Add(&expander.Widget)
+		}
 
 		page.Add(group)
 	}
+
+	// Homebrew Bundles group - hidden if Homebrew is not installed
+	if uh.config.IsGroupEnabled("applications_page", "brew_bundles_group") {
+		group := adw.NewPreferencesGroup()
+		group.SetTitle("Homebrew Bundles")
+		group.SetDescription("Install a curated set of packages from a Brewfile")
+		uh.brewBundlesGroup = group
+
+		uh.bundleChooseRow = adw.NewActionRow()
+		uh.bundleChooseRow.SetTitle("Install from Brewfile")
+		uh.bundleChooseRow.SetSubtitle("Choose a Brewfile to install its packages")
+
+		chooseBtn := gtk.NewButtonWithLabel("Choose...")
+		chooseBtn.SetValign(gtk.AlignCenterValue)
+		chooseClickedCb := func(_ gtk.Button) {
+			uh.chooseBrewfile()
+		}
+		chooseBtn.ConnectClicked(&chooseClickedCb)
+		uh.bundleInstallBtns = append(uh.bundleInstallBtns, chooseBtn)
+		uh.bundleChooseRow.AddSuffix(&chooseBtn.Widget)
+		group.Add(&uh.bundleChooseRow.Widget)
+
+		// Progress for the running (or last) install; hidden until one starts
+		uh.bundleProgressExpander = adw.NewExpanderRow()
+		uh.bundleProgressExpander.SetVisible(false)
+		group.Add(&uh.bundleProgressExpander.Widget)
+
+		page.Add(group)
+
+		var dirs []string
+		if groupCfg := uh.config.GetGroupConfig("applications_page", "brew_bundles_group"); groupCfg != nil {
+			dirs = groupCfg.BundlesPaths
+		}
+		go uh.loadBrewBundles(dirs)
+	}
 }
 
-// loadHomebrewPackages loads installed Homebrew packages asynchronously
+// loadHomebrewPackages loads installed Homebrew packages asynchronously,
+// replacing any rows from a previous load (it is re-run after every pin
+// change so the pin toggles show brew's actual state).
 func (uh *UserHome) loadHomebrewPackages() {
 	if !homebrew.IsInstalledCached() {
 		sgtk.RunOnMainThread(func() {
@@ -169,43 +271,158 @@ func (uh *UserHome) loadHomebrewPackages() {
 	}
 
 	// Load formulae
-	formulae, err := homebrew.ListInstalledFormulae()
-	if err != nil {
-		sgtk.RunOnMainThread(func() {
-			uh.formulaeExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
-		})
-	} else {
-		sgtk.RunOnMainThread(func() {
-			uh.formulaeExpander.SetSubtitle(fmt.Sprintf("%d installed", len(formulae)))
-			for _, pkg := range formulae {
-				row := adw.NewActionRow()
-				row.SetTitle(pkg.Name)
-				row.SetSubtitle(pkg.Version)
-				uh.formulaeExpander.AddRow(&row.Widget)
-			}
-		})
-	}
+	uh.lists.formulae.Load(uh.ctx, func(formulae []homebrew.Package, formulaeErr error) {
+		uh.showFormulae(formulae, formulaeErr)
+	})
 
 	// Load casks
-	casks, err := homebrew.ListInstalledCasks()
-	if err != nil {
-		sgtk.RunOnMainThread(func() {
-			uh.casksExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
-		})
+	uh.lists.casks.Load(uh.ctx, func(casks []homebrew.Package, casksErr error) {
+		uh.showCasks(casks, casksErr)
+	})
+}
+
+// showFormulae replaces the Formulae expander's rows with formulae
+func (uh *UserHome) showFormulae(formulae []homebrew.Package, formulaeErr error) {
+	sgtk.RunOnMainThread(func() {
+		for _, row := range uh.formulaRows {
+			uh.formulaeExpander.Remove(&row.Widget)
+		}
+		uh.formulaRows = nil
+		uh.pinnedFormulae = make(map[string]bool)
+
+		if formulaeErr != nil {
+			uh.formulaeExpander.SetSubtitle(fmt.Sprintf("Error: %v", formulaeErr))
+			return
+		}
+
+		pinned := 0
+		var total int64
+		for _, pkg := range formulae {
+			total += pkg.Size
+			if pkg.Pinned {
+				pinned++
+				uh.pinnedFormulae[pkg.Name] = true
+			}
+			row := uh.newFormulaRow(pkg)
+			uh.formulaeExpander.AddRow(&row.Widget)
+			uh.formulaRows = append(uh.formulaRows, row)
+		}
+
+		subtitle := fmt.Sprintf("%d installed", len(formulae))
+		if pinned > 0 {
+			subtitle = fmt.Sprintf("%d installed, %d pinned", len(formulae), pinned)
+		}
+		uh.formulaeExpander.SetSubtitle(withTotal(subtitle, total))
+		uh.applyPinnedFilter()
+	})
+}
+
+// showCasks replaces the Casks expander's rows with casks
+func (uh *UserHome) showCasks(casks []homebrew.Package, casksErr error) {
+	sgtk.RunOnMainThread(func() {
+		for _, row := range uh.caskRows {
+			uh.casksExpander.Remove(&row.Widget)
+		}
+		uh.caskRows = nil
+
+		if casksErr != nil {
+			uh.casksExpander.SetSubtitle(fmt.Sprintf("Error: %v", casksErr))
+			return
+		}
+
+		var total int64
+		for _, pkg := range casks {
+			total += pkg.Size
+			row := adw.NewActionRow()
+			row.SetTitle(pkg.Name)
+			row.SetSubtitle(withSize(pkg.Version, pkg.Size))
+			name := pkg.Name
+			depsBtn := newDependenciesButton(func() {
+				uh.showBrewDependencies(name, true)
+			})
+			row.AddSuffix(&depsBtn.Widget)
+			uh.casksExpander.AddRow(&row.Widget)
+			uh.caskRows = append(uh.caskRows, row)
+		}
+		uh.casksExpander.SetSubtitle(withTotal(fmt.Sprintf("%d installed", len(casks)), total))
+	})
+}
+
+// newFormulaRow builds an installed-formula row with a pin toggle. Pinned
+// formulae are held back by `brew upgrade` until unpinned.
+func (uh *UserHome) newFormulaRow(pkg homebrew.Package) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetTitle(pkg.Name)
+	row.SetSubtitle(withSize(pkg.Version, pkg.Size))
+
+	pinBtn := gtk.NewToggleButton()
+	pinBtn.SetIconName("view-pin-symbolic")
+	pinBtn.SetValign(gtk.AlignCenterValue)
+	pinBtn.AddCssClass("flat")
+	pinBtn.SetActive(pkg.Pinned)
+	if pkg.Pinned {
+		pinBtn.SetTooltipText("Unpin (allow upgrades)")
 	} else {
-		sgtk.RunOnMainThread(func() {
-			uh.casksExpander.SetSubtitle(fmt.Sprintf("%d installed", len(casks)))
-			for _, pkg := range casks {
-				row := adw.NewActionRow()
-				row.SetTitle(pkg.Name)
-				row.SetSubtitle(pkg.Version)
-				uh.casksExpander.AddRow(&row.Widget)
+		pinBtn.SetTooltipText("Pin (hold back from upgrades)")
+	}
+
+	name := pkg.Name
+	// reverting suppresses the toggled handler while a failed change is
+	// undone, so the revert does not start another brew call.
+	reverting := false
+	toggledCb := func(btn gtk.ToggleButton) {
+		if reverting {
+			return
+		}
+		pin := btn.GetActive()
+		btn.SetSensitive(false)
+		go func() {
+			var err error
+			if pin {
+				err = homebrew.Pin(uh.ctx, name)
+			} else {
+				err = homebrew.Unpin(uh.ctx, name)
 			}
-		})
+			sgtk.RunOnMainThread(func() {
+				btn.SetSensitive(true)
+				if err != nil {
+					reverting = true
+					btn.SetActive(!pin)
+					reverting = false
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to change pin: %v", err))
+					return
+				}
+				uh.toastAdder.ShowToast(actionmsg.Pin(homebrew.IsDryRun(), pin, name))
+				// Re-list so the toggle reflects brew's state; under dry-run
+				// nothing was pinned and the toggle snaps back.
+				go uh.loadHomebrewPackages()
+				go uh.loadOutdatedPackages()
+			})
+		}()
 	}
+	pinBtn.ConnectToggled(&toggledCb)
+
+	depsBtn := newDependenciesButton(func() {
+		uh.showBrewDependencies(name, false)
+	})
+
+	row.AddSuffix(&depsBtn.Widget)
+	row.AddSuffix(&pinBtn.Widget)
+	return row
 }
 
-// loadFlatpakApplications loads installed Flatpak applications asynchronously
+// applyPinnedFilter hides unpinned formula rows while the Pinned filter is
+// active.
+func (uh *UserHome) applyPinnedFilter() {
+	onlyPinned := uh.pinnedFilterBtn != nil && uh.pinnedFilterBtn.GetActive()
+	for _, row := range uh.formulaRows {
+		row.SetVisible(!onlyPinned || uh.pinnedFormulae[row.GetTitle()])
+	}
+}
+
+// loadFlatpakApplications loads installed Flatpak applications
+// asynchronously, replacing any rows from a previous load (it is re-run
+// after every uninstall).
 func (uh *UserHome) loadFlatpakApplications() {
 	if !flatpak.IsInstalledCached() {
 		sgtk.RunOnMainThread(func() {
@@ -221,171 +438,491 @@ func (uh *UserHome) loadFlatpakApplications() {
 
 	// Load user applications
 	if uh.flatpakUserExpander != nil {
-		userApps, err := flatpak.ListUserApplications()
-		if err != nil {
+		uh.lists.flatpakUser.Load(uh.ctx, func(userApps []flatpak.Application, err error) {
 			sgtk.RunOnMainThread(func() {
-				uh.flatpakUserExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
-			})
-		} else {
-			sgtk.RunOnMainThread(func() {
-				uh.flatpakUserExpander.SetSubtitle(fmt.Sprintf("%d installed", len(userApps)))
-				for _, app := range userApps {
-					row := adw.NewActionRow()
-					row.SetTitle(app.Name)
-					subtitle := app.ApplicationID
-					if app.Version != "" {
-						subtitle = fmt.Sprintf("%s (%s)", app.ApplicationID, app.Version)
-					}
-					row.SetSubtitle(subtitle)
-
-					// Add uninstall button
-					uninstallBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
-					uninstallBtn.SetValign(gtk.AlignCenterValue)
-					uninstallBtn.AddCssClass("destructive-action")
-					uninstallBtn.SetTooltipText("Uninstall")
-
-					appID := app.ApplicationID
-					clickedCb := func(btn gtk.Button) {
-						btn.SetSensitive(false)
-						go func() {
-							if err := flatpak.Uninstall(appID, true); err != nil {
-								sgtk.RunOnMainThread(func() {
-									btn.SetSensitive(true)
-									uh.toastAdder.ShowErrorToast(fmt.Sprintf("Uninstall failed: %v", err))
-								})
-								return
-							}
-							sgtk.RunOnMainThread(func() {
-								uh.toastAdder.ShowToast(actionmsg.Uninstall(flatpak.IsDryRun(), appID))
-								// Refresh the list
-								go uh.loadFlatpakApplications()
-							})
-						}()
-					}
-					uninstallBtn.ConnectClicked(&clickedCb)
-
-					row.AddSuffix(&uninstallBtn.Widget)
-					uh.flatpakUserExpander.AddRow(&row.Widget)
-				}
+				uh.flatpakUserAppRows = uh.fillFlatpakExpander(uh.flatpakUserExpander, uh.flatpakUserAppRows, userApps, err, true)
 			})
-		}
+		})
 	}
 
 	// Load system applications
 	if uh.flatpakSystemExpander != nil {
-		systemApps, err := flatpak.ListSystemApplications()
-		if err != nil {
+		uh.lists.flatpakSystem.Load(uh.ctx, func(systemApps []flatpak.Application, err error) {
 			sgtk.RunOnMainThread(func() {
-				uh.flatpakSystemExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
+				uh.flatpakSystemAppRows = uh.fillFlatpakExpander(uh.flatpakSystemExpander, uh.flatpakSystemAppRows, systemApps, err, false)
 			})
-		} else {
-			sgtk.RunOnMainThread(func() {
-				uh.flatpakSystemExpander.SetSubtitle(fmt.Sprintf("%d installed", len(systemApps)))
-				for _, app := range systemApps {
-					row := adw.NewActionRow()
-					row.SetTitle(app.Name)
-					subtitle := app.ApplicationID
-					if app.Version != "" {
-						subtitle = fmt.Sprintf("%s (%s)", app.ApplicationID, app.Version)
-					}
-					row.SetSubtitle(subtitle)
-
-					// Add uninstall button (requires elevated privileges for system apps)
-					uninstallBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
-					uninstallBtn.SetValign(gtk.AlignCenterValue)
-					uninstallBtn.AddCssClass("destructive-action")
-					uninstallBtn.SetTooltipText("Uninstall (requires admin)")
-
-					appID := app.ApplicationID
-					clickedCb := func(btn gtk.Button) {
-						btn.SetSensitive(false)
-						go func() {
-							if err := flatpak.Uninstall(appID, false); err != nil {
-								sgtk.RunOnMainThread(func() {
-									btn.SetSensitive(true)
-									uh.toastAdder.ShowErrorToast(fmt.Sprintf("Uninstall failed: %v", err))
-								})
-								return
-							}
-							sgtk.RunOnMainThread(func() {
-								uh.toastAdder.ShowToast(actionmsg.Uninstall(flatpak.IsDryRun(), appID))
-								// Refresh the list
-								go uh.loadFlatpakApplications()
-							})
-						}()
-					}
-					uninstallBtn.ConnectClicked(&clickedCb)
+		})
+	}
+}
 
-					row.AddSuffix(&uninstallBtn.Widget)
-					uh.flatpakSystemExpander.AddRow(&row.Widget)
-				}
+// fillFlatpakExpander replaces oldRows in expander with one row per app and
+// returns the new rows. Must run on the main thread.
+func (uh *UserHome) fillFlatpakExpander(expander *adw.ExpanderRow, oldRows []*adw.ActionRow, apps []flatpak.Application, err error, user bool) []*adw.ActionRow {
+	for _, row := range oldRows {
+		expander.Remove(&row.Widget)
+	}
+
+	if err != nil {
+		expander.SetSubtitle(fmt.Sprintf("Error: %v", err))
+		return nil
+	}
+
+	var total int64
+	for _, app := range apps {
+		total += app.Size
+	}
+	expander.SetSubtitle(withTotal(fmt.Sprintf("%d installed", len(apps)), total))
+
+	rows := make([]*adw.ActionRow, 0, len(apps))
+	for _, app := range apps {
+		row := uh.newFlatpakAppRow(app, user)
+		expander.AddRow(&row.Widget)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// newFlatpakAppRow builds the row for an installed Flatpak application: its
+// exported icon, display name, summary, ID and version, and an uninstall
+// button. Must run on the main thread.
+func (uh *UserHome) newFlatpakAppRow(app flatpak.Application, user bool) *adw.ActionRow {
+	row := adw.NewActionRow()
+	// Names and summaries come from AppStream and may contain & or <
+	row.SetUseMarkup(false)
+	row.SetTitle(app.Name)
+
+	// Flatpak exports each app's icon into the icon theme under its app ID.
+	iconName := "application-x-executable-symbolic"
+	if theme := gtk.IconThemeGetForDisplay(gdk.DisplayGetDefault()); theme != nil && theme.HasIcon(app.ApplicationID) {
+		iconName = app.ApplicationID
+	}
+	icon := gtk.NewImageFromIconName(iconName)
+	icon.SetPixelSize(32)
+	row.AddPrefix(&icon.Widget)
+
+	subtitle := app.ApplicationID
+	if app.Version != "" {
+		subtitle = fmt.Sprintf("%s (%s)", app.ApplicationID, app.Version)
+	}
+	subtitle = withSize(subtitle, app.Size)
+	if app.Description != "" {
+		subtitle = app.Description + "\n" + subtitle
+	}
+	row.SetSubtitle(subtitle)
+	row.SetSubtitleLines(2)
+
+	uninstallBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
+	uninstallBtn.SetValign(gtk.AlignCenterValue)
+	uninstallBtn.AddCssClass("destructive-action")
+
+	body := "It will be removed for your user account. Its data in ~/.var/app is kept."
+	if user {
+		uninstallBtn.SetTooltipText("Uninstall")
+	} else {
+		// System apps require elevated privileges to uninstall
+		uninstallBtn.SetTooltipText("Uninstall (requires admin)")
+		body = "It will be removed for all users on this system. Its data in each user's ~/.var/app is kept."
+	}
+
+	appID := app.ApplicationID
+	appName := app.Name
+	clickedCb := func(btn gtk.Button) {
+		uh.confirmDestructive(&uh.applicationsPrefsPage.Widget,
+			fmt.Sprintf("Uninstall %s?", appName),
+			body,
+			"Uninstall",
+			func() {
+				btn.SetSensitive(false)
+				go func() {
+					if err := flatpak.Uninstall(uh.ctx, appID, user); err != nil {
+						sgtk.RunOnMainThread(func() {
+							btn.SetSensitive(true)
+							uh.toastAdder.ShowErrorToast(fmt.Sprintf("Uninstall failed: %v", err))
+						})
+						return
+					}
+					sgtk.RunOnMainThread(func() {
+						uh.toastAdder.ShowToast(actionmsg.Uninstall(flatpak.IsDryRun(), appID))
+						// Refresh the list
+						go uh.loadFlatpakApplications()
+					})
+				}()
 			})
-		}
 	}
+	uninstallBtn.ConnectClicked(&clickedCb)
+
+	versionsBtn := gtk.NewButtonFromIconName("document-open-recent-symbolic")
+	versionsBtn.SetValign(gtk.AlignCenterValue)
+	versionsBtn.SetTooltipText("Versions")
+	versionsBtn.AddCssClass("flat")
+	versionsCb := func(_ gtk.Button) {
+		uh.showFlatpakVersions(app, user)
+	}
+	versionsBtn.ConnectClicked(&versionsCb)
+
+	depsBtn := newDependenciesButton(func() {
+		uh.showFlatpakDependencies(app, user)
+	})
+
+	row.AddSuffix(&depsBtn.Widget)
+	row.AddSuffix(&versionsBtn.Widget)
+	row.AddSuffix(&uninstallBtn.Widget)
+	return row
 }
 
-// onHomebrewSearch handles the Homebrew search action
-func (uh *UserHome) onHomebrewSearch() {
-	query := uh.searchEntry.GetText()
-	if query == "" {
+// loadFlatpakRemotes lists user and system remotes into the Flatpak Remotes
+// group, replacing any rows from a previous load.
+func (uh *UserHome) loadFlatpakRemotes() {
+	if !flatpak.IsInstalledCached() {
+		sgtk.RunOnMainThread(func() {
+			uh.flatpakRemotesGroup.SetVisible(false)
+		})
 		return
 	}
 
-	uh.searchResultsExpander.SetSubtitle("Searching...")
-	uh.searchResultsExpander.SetEnableExpansion(false)
+	userRemotes, userErr := flatpak.ListRemotes(uh.ctx, true)
+	systemRemotes, systemErr := flatpak.ListRemotes(uh.ctx, false)
 
-	go func() {
-		results, err := homebrew.Search(query)
-		if err != nil {
-			sgtk.RunOnMainThread(func() {
-				uh.searchResultsExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
-			})
-			return
+	sgtk.RunOnMainThread(func() {
+		for _, row := range uh.flatpakRemoteRows {
+			uh.flatpakRemotesGroup.Remove(&row.Widget)
 		}
+		uh.flatpakRemoteRows = nil
 
-		sgtk.RunOnMainThread(func() {
-			// Clear previous search results
-			for _, row := range uh.searchResultRows {
-				uh.searchResultsExpander.Remove(&row.Widget)
-			}
-			uh.searchResultRows = nil
+		addRow := func(row *adw.ActionRow) {
+			uh.flatpakRemotesGroup.Add(&row.Widget)
+			uh.flatpakRemoteRows = append(uh.flatpakRemoteRows, row)
+		}
 
-			uh.searchResultsExpander.SetSubtitle(fmt.Sprintf("%d results", len(results)))
-			uh.searchResultsExpander.SetEnableExpansion(len(results) > 0)
+		for _, result := range []struct {
+			label   string
+			remotes []flatpak.Remote
+			err     error
+		}{
+			{"User", userRemotes, userErr},
+			{"System", systemRemotes, systemErr},
+		} {
+			if result.err != nil {
+				row := adw.NewActionRow()
+				row.SetTitle(fmt.Sprintf("%s remotes", result.label))
+				row.SetSubtitle(fmt.Sprintf("Error: %v", result.err))
+				addRow(row)
+				continue
+			}
 
-			// Add result rows
-			for _, result := range results {
+			for _, remote := range result.remotes {
 				row := adw.NewActionRow()
-				row.SetTitle(result.Name)
+				title := remote.Title
+				if title == "" {
+					title = remote.Name
+				}
+				row.SetTitle(title)
 
-				installBtn := gtk.NewButtonWithLabel("Install")
-				installBtn.SetValign(gtk.AlignCenterValue)
-				installBtn.AddCssClass("suggested-action")
+				subtitle := fmt.Sprintf("%s · %s", remote.Name, result.label)
+				if remote.Disabled {
+					subtitle += " · disabled"
+				}
+				if remote.URL != "" {
+					subtitle += "\n" + remote.URL
+				}
+				row.SetSubtitle(subtitle)
 
-				pkgName := result.Name
+				removeBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
+				removeBtn.SetValign(gtk.AlignCenterValue)
+				removeBtn.AddCssClass("flat")
+				removeBtn.SetTooltipText("Remove Remote")
+
+				name := remote.Name
+				user := remote.Installation == "user"
 				clickedCb := func(btn gtk.Button) {
-					go func() {
-						if err := homebrew.Install(pkgName, false); err != nil {
-							sgtk.RunOnMainThread(func() {
-								uh.toastAdder.ShowErrorToast(fmt.Sprintf("Install failed: %v", err))
-							})
-							return
-						}
-						sgtk.RunOnMainThread(func() {
-							uh.toastAdder.ShowToast(actionmsg.Install(homebrew.IsDryRun(), pkgName))
+					uh.confirmDestructive(&uh.applicationsPrefsPage.Widget,
+						fmt.Sprintf("Remove %s?", name),
+						"Applications can no longer be installed or updated from this remote. Flatpak refuses while applications from it are still installed.",
+						"Remove",
+						func() {
+							btn.SetSensitive(false)
+							go uh.removeFlatpakRemote(name, user, &btn)
 						})
-					}()
 				}
-				installBtn.ConnectClicked(&clickedCb)
+				removeBtn.ConnectClicked(&clickedCb)
 
-				row.AddSuffix(&installBtn.Widget)
-				uh.searchResultsExpander.AddRow(&row.Widget)
-				uh.searchResultRows = append(uh.searchResultRows, row)
+				row.AddSuffix(&removeBtn.Widget)
+				addRow(row)
 			}
+		}
+
+		if len(uh.flatpakRemoteRows) == 0 {
+			row := adw.NewActionRow()
+			row.SetTitle("No remotes configured")
+			row.SetSubtitle("Add Flathub or another repository to install applications")
+			addRow(row)
+		}
+	})
+}
+
+// removeFlatpakRemote deletes a remote and reloads the Remotes group
+func (uh *UserHome) removeFlatpakRemote(name string, user bool, button *gtk.Button) {
+	err := flatpak.RemoveRemote(uh.ctx, name, user)
+
+	sgtk.RunOnMainThread(func() {
+		if err != nil {
+			button.SetSensitive(true)
+			uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to remove %s: %v", name, err))
+			return
+		}
+		uh.toastAdder.ShowToast(actionmsg.RemoteRemove(flatpak.IsDryRun(), name))
+		go uh.loadFlatpakRemotes()
+	})
+}
+
+// showAddRemoteDialog asks for a remote name and location and adds it
+func (uh *UserHome) showAddRemoteDialog() {
+	dialog := adw.NewAlertDialog(
+		"Add Flatpak Remote",
+		"Enter a name and the repository URL or .flatpakrepo file, e.g. https://dl.flathub.org/repo/flathub.flatpakrepo.",
+	)
+
+	fields := adw.NewPreferencesGroup()
+
+	nameRow := adw.NewEntryRow()
+	nameRow.SetTitle("Name")
+	fields.Add(&nameRow.Widget)
+
+	locationRow := adw.NewEntryRow()
+	locationRow.SetTitle("Location")
+	locationRow.SetInputPurpose(gtk.InputPurposeUrlValue)
+	fields.Add(&locationRow.Widget)
+
+	systemRow := adw.NewSwitchRow()
+	systemRow.SetTitle("System-wide")
+	systemRow.SetSubtitle("Available to all users; requires administrator")
+	fields.Add(&systemRow.Widget)
+
+	dialog.SetExtraChild(&fields.Widget)
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("add", "Add")
+	dialog.SetResponseAppearance("add", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("add")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response != "add" {
+			return
+		}
+
+		name := strings.TrimSpace(nameRow.GetText())
+		location := strings.TrimSpace(locationRow.GetText())
+		user := !systemRow.GetActive()
+
+		if err := flatpak.ValidateRemote(name, location); err != nil {
+			uh.toastAdder.ShowErrorToast(err.Error())
+			return
+		}
+
+		go func() {
+			err := flatpak.AddRemote(uh.ctx, name, location, user)
+			sgtk.RunOnMainThread(func() {
+				if err != nil {
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to add %s: %v", name, err))
+					return
+				}
+				uh.toastAdder.ShowToast(actionmsg.RemoteAdd(flatpak.IsDryRun(), name))
+				go uh.loadFlatpakRemotes()
+			})
+		}()
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(&uh.applicationsPrefsPage.Widget)
+}
+
+// onPackageSearch handles the package search action. Every enabled and
+// available source is searched concurrently by pkgsearch; each lists its
+// results in its own expander, and expanders for sources disabled or
+// missing on this system are hidden.
+func (uh *UserHome) onPackageSearch() {
+	query := uh.searchEntry.GetText()
+	if query == "" {
+		return
+	}
+
+	for _, expander := range []*adw.ExpanderRow{uh.searchResultsExpander, uh.caskResultsExpander, uh.flatpakResultsExpander} {
+		expander.SetSubtitle("Searching...")
+		expander.SetEnableExpansion(false)
+	}
+
+	// A new query supersedes the one still running; its results are dropped
+	if uh.searchCancel != nil {
+		uh.searchCancel()
+	}
+	ctx, cancel := context.WithCancel(uh.ctx)
+	uh.searchCancel = cancel
+
+	go func() {
+		results := pkgsearch.Search(ctx, query, uh.searchSources)
+
+		sgtk.RunOnMainThread(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			cancel()
+			uh.searchCancel = nil
+			uh.searchResultRows = uh.fillSearchExpander(uh.searchResultsExpander, uh.searchResultRows, results, pkgsearch.SourceFormula)
+			uh.caskResultRows = uh.fillSearchExpander(uh.caskResultsExpander, uh.caskResultRows, results, pkgsearch.SourceCask)
+			uh.flatpakResultRows = uh.fillSearchExpander(uh.flatpakResultsExpander, uh.flatpakResultRows, results, pkgsearch.SourceFlatpak)
 		})
 	}()
 }
 
+// fillSearchExpander replaces oldRows in expander with one row per result
+// from source and returns the new rows. Must run on the main thread.
+func (uh *UserHome) fillSearchExpander(expander *adw.ExpanderRow, oldRows []*adw.ActionRow, results pkgsearch.Results, source pkgsearch.Source) []*adw.ActionRow {
+	for _, row := range oldRows {
+		expander.Remove(&row.Widget)
+	}
+
+	items, searched := results.Items[source]
+	err, failed := results.Errors[source]
+	expander.SetVisible(searched || failed)
+
+	if failed {
+		expander.SetSubtitle(errorText(err))
+		return nil
+	}
+
+	expander.SetSubtitle(fmt.Sprintf("%d results", len(items)))
+	expander.SetEnableExpansion(len(items) > 0)
+
+	rows := make([]*adw.ActionRow, 0, len(items))
+	for _, result := range items {
+		row := uh.newSearchResultRow(result)
+		expander.AddRow(&row.Widget)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// newSearchResultRow builds a search result row with a homepage button
+// (when known) and an Install button, or an "Installed" label. Flatpak
+// applications install from the remote they were found in, into that
+// remote's installation.
+func (uh *UserHome) newSearchResultRow(result pkgsearch.Result) *adw.ActionRow {
+	row := adw.NewActionRow()
+	// Descriptions come from brew info and AppStream and may contain & or <
+	row.SetUseMarkup(false)
+	row.SetTitle(result.Name)
+
+	subtitle := result.Description
+	if result.Version != "" {
+		if subtitle != "" {
+			subtitle += " · "
+		}
+		subtitle += result.Version
+	}
+	if result.Source == pkgsearch.SourceFlatpak {
+		where := result.ID
+		if result.Remote != "" {
+			installation := "system"
+			if result.User {
+				installation = "user"
+			}
+			where = fmt.Sprintf("%s · %s (%s)", result.ID, result.Remote, installation)
+		}
+		subtitle = strings.TrimPrefix(subtitle+"\n"+where, "\n")
+		row.SetSubtitleLines(2)
+	}
+	row.SetSubtitle(subtitle)
+
+	if result.Homepage != "" {
+		homepage := result.Homepage
+		homeBtn := gtk.NewButtonFromIconName("web-browser-symbolic")
+		homeBtn.SetValign(gtk.AlignCenterValue)
+		homeBtn.SetTooltipText("Open Homepage")
+		homeBtn.AddCssClass("flat")
+		homeClickedCb := func(_ gtk.Button) {
+			uh.openURL(homepage)
+		}
+		homeBtn.ConnectClicked(&homeClickedCb)
+		row.AddSuffix(&homeBtn.Widget)
+	}
+
+	if result.Installed {
+		label := gtk.NewLabel("Installed")
+		label.AddCssClass("dim-label")
+		row.AddSuffix(&label.Widget)
+		return row
+	}
+
+	installBtn := gtk.NewButtonWithLabel("Install")
+	installBtn.SetValign(gtk.AlignCenterValue)
+	installBtn.AddCssClass("suggested-action")
+
+	id := result.ID
+	source := result.Source
+	remote, user := result.Remote, result.User
+	install := func() {
+		go func() {
+			var err error
+			var dryRun bool
+			switch source {
+			case pkgsearch.SourceFlatpak:
+				err = flatpak.InstallFrom(uh.ctx, remote, id, user)
+				dryRun = flatpak.IsDryRun()
+			default:
+				err = homebrew.Install(uh.ctx, id, source == pkgsearch.SourceCask)
+				dryRun = homebrew.IsDryRun()
+			}
+			if err != nil {
+				sgtk.RunOnMainThread(func() {
+					installBtn.SetSensitive(true)
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Install failed: %v", err))
+				})
+				return
+			}
+			sgtk.RunOnMainThread(func() {
+				uh.toastAdder.ShowToast(actionmsg.Install(dryRun, id))
+				if dryRun {
+					installBtn.SetSensitive(true)
+				} else {
+					row.Remove(&installBtn.Widget)
+					label := gtk.NewLabel("Installed")
+					label.AddCssClass("dim-label")
+					row.AddSuffix(&label.Widget)
+				}
+				if source == pkgsearch.SourceFlatpak {
+					go uh.loadFlatpakApplications()
+				}
+			})
+		}()
+	}
+	// Insensitive until the install finishes, so a second click
+	// cannot start another one
+	cancel := func() { installBtn.SetSensitive(true) }
+	clickedCb := func(_ gtk.Button) {
+		installBtn.SetSensitive(false)
+		if source == pkgsearch.SourceFlatpak {
+			install()
+			return
+		}
+
+		// brew install --dry-run lists the dependencies it would add
+		go func() {
+			plan, err := homebrew.PreviewInstall(uh.ctx, id, source == pkgsearch.SourceCask)
+			sgtk.RunOnMainThread(func() {
+				if err != nil {
+					installBtn.SetSensitive(true)
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not preview install: %v", err))
+					return
+				}
+				uh.confirmPlan(&uh.applicationsPrefsPage.Widget, fmt.Sprintf("Install %s?", id), "Install", plan, homebrew.IsDryRun(), install, cancel)
+			})
+		}()
+	}
+	installBtn.ConnectClicked(&clickedCb)
+
+	row.AddSuffix(&installBtn.Widget)
+	return row
+}
+
 // launchApp launches a desktop application by its application ID
 func (uh *UserHome) launchApp(appID string) {
 	log.Printf("Launching app: %s", appID)
@@ -0,0 +1,118 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/audit"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// auditLogLimit is how many of the newest entries the viewer shows
+const auditLogLimit = 200
+
+// buildAuditLogGroup adds the Activity Log row to the Maintenance page
+func (uh *UserHome) buildAuditLogGroup(page *adw.PreferencesPage) {
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("Activity Log")
+	group.SetDescription("Every install, removal and upgrade ChairLift has run on this machine")
+
+	row := adw.NewActionRow()
+	row.SetTitle("Package Changes")
+	if path := audit.Path(); path != "" {
+		row.SetSubtitle(path)
+	} else {
+		row.SetSubtitle("Recording is off")
+	}
+	icon := gtk.NewImageFromIconName("document-open-recent-symbolic")
+	row.AddPrefix(&icon.Widget)
+
+	viewBtn := gtk.NewButtonWithLabel("View")
+	viewBtn.SetValign(gtk.AlignCenterValue)
+	viewBtn.SetSensitive(audit.Path() != "")
+	clickedCb := func(_ gtk.Button) {
+		uh.showAuditLog()
+	}
+	viewBtn.ConnectClicked(&clickedCb)
+	row.AddSuffix(&viewBtn.Widget)
+	group.Add(&row.Widget)
+
+	page.Add(group)
+}
+
+// showAuditLog opens a dialog listing the newest audit log entries
+func (uh *UserHome) showAuditLog() {
+	path := audit.Path()
+
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle("Activity Log")
+	dialog.SetContentWidth(560)
+	dialog.SetContentHeight(620)
+
+	page := adw.NewPreferencesPage()
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("Package Changes")
+
+	openBtn := gtk.NewButtonFromIconName("document-open-symbolic")
+	openBtn.SetValign(gtk.AlignCenterValue)
+	openBtn.SetTooltipText("Open Log File")
+	openBtn.AddCssClass("flat")
+	openClickedCb := func(_ gtk.Button) {
+		uh.openURL(path)
+	}
+	openBtn.ConnectClicked(&openClickedCb)
+	group.SetHeaderSuffix(&openBtn.Widget)
+
+	loadingRow := adw.NewActionRow()
+	loadingRow.SetTitle("Loading...")
+	group.Add(&loadingRow.Widget)
+
+	page.Add(group)
+	dialog.Add(page)
+
+	go func() {
+		entries, err := audit.Read(path, auditLogLimit)
+
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				loadingRow.SetTitle("Failed to read the log")
+				loadingRow.SetSubtitle(err.Error())
+				return
+			}
+			if len(entries) == 0 {
+				loadingRow.SetTitle("Nothing recorded yet")
+				loadingRow.SetSubtitle("Package changes made through ChairLift appear here")
+				return
+			}
+			group.Remove(&loadingRow.Widget)
+
+			if len(entries) == auditLogLimit {
+				group.SetDescription(fmt.Sprintf("The newest %d changes", auditLogLimit))
+			}
+			for _, entry := range entries {
+				row := adw.NewActionRow()
+				row.SetTitle(fmt.Sprintf("%s %s", entry.Tool, entry.Command))
+				row.SetTitleLines(2)
+				subtitle := fmt.Sprintf("%s · %s", entry.Time.Local().Format("2006-01-02 15:04"), entry.User)
+				if entry.Result == audit.ResultFailed {
+					subtitle = fmt.Sprintf("%s · failed: %s", subtitle, entry.Error)
+					icon := gtk.NewImageFromIconName("dialog-error-symbolic")
+					icon.AddCssClass("error")
+					row.AddPrefix(&icon.Widget)
+				} else {
+					icon := gtk.NewImageFromIconName("object-select-symbolic")
+					icon.AddCssClass("success")
+					row.AddPrefix(&icon.Widget)
+				}
+				row.SetSubtitle(subtitle)
+				row.SetSubtitleLines(3)
+				group.Add(&row.Widget)
+			}
+		})
+	}()
+
+	dialog.Present(&uh.maintenancePrefsPage.Widget)
+}
@@ -0,0 +1,205 @@
+package views
+
+import (
+	"fmt"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// batchSelection adds a selection mode to an expander of package rows: a
+// Select toggle on the expander, a check button on each selectable row,
+// and an action row under the expander that runs one batch over the
+// checked rows. Rows are identified by the order they were added, so the
+// caller keeps a parallel slice of the items behind them.
+type batchSelection struct {
+	verb     string
+	expander *adw.ExpanderRow
+	toggle   *gtk.ToggleButton
+	bar      *adw.ActionRow
+	allBtn   *gtk.Button
+	runBtn   *gtk.Button
+	checks   []*gtk.CheckButton
+	actions  []*gtk.Widget // per-row buttons, hidden while selecting
+	busy     bool
+	onRun    func(selected []int)
+}
+
+// newBatchSelection builds the Select toggle onto expander and appends the
+// action row to group, right after the expander. verb labels the action
+// ("Update 7 selected").
+func newBatchSelection(group *adw.PreferencesGroup, expander *adw.ExpanderRow, verb string, onRun func(selected []int)) *batchSelection {
+	s := &batchSelection{verb: verb, expander: expander, onRun: onRun}
+
+	s.toggle = gtk.NewToggleButton()
+	s.toggle.SetIconName("selection-mode-symbolic")
+	s.toggle.SetValign(gtk.AlignCenterValue)
+	s.toggle.SetTooltipText("Select packages")
+	s.toggle.AddCssClass("flat")
+	s.toggle.SetVisible(false)
+	toggledCb := func(btn gtk.ToggleButton) {
+		s.setSelecting(btn.GetActive())
+	}
+	s.toggle.ConnectToggled(&toggledCb)
+	expander.AddSuffix(&s.toggle.Widget)
+
+	s.bar = adw.NewActionRow()
+	s.bar.SetVisible(false)
+
+	s.allBtn = gtk.NewButtonWithLabel("Select All")
+	s.allBtn.SetValign(gtk.AlignCenterValue)
+	s.allBtn.AddCssClass("flat")
+	allCb := func(_ gtk.Button) {
+		for _, check := range s.checks {
+			check.SetActive(true)
+		}
+	}
+	s.allBtn.ConnectClicked(&allCb)
+	s.bar.AddSuffix(&s.allBtn.Widget)
+
+	s.runBtn = gtk.NewButtonWithLabel(verb)
+	s.runBtn.SetValign(gtk.AlignCenterValue)
+	s.runBtn.AddCssClass("suggested-action")
+	runCb := func(_ gtk.Button) {
+		s.run()
+	}
+	s.runBtn.ConnectClicked(&runCb)
+	s.bar.AddSuffix(&s.runBtn.Widget)
+
+	group.Add(&s.bar.Widget)
+	s.updateCount()
+	return s
+}
+
+// reset forgets the current rows; call it after clearing the expander and
+// before adding the rebuilt rows
+func (s *batchSelection) reset() {
+	s.checks = nil
+	s.actions = nil
+	s.updateCount()
+}
+
+// addRow makes row selectable. action is the row's own button, hidden
+// while selecting so a single-row action cannot race the batch.
+func (s *batchSelection) addRow(row *adw.ActionRow, action *gtk.Widget) {
+	selecting := s.toggle.GetActive()
+
+	check := gtk.NewCheckButton()
+	check.SetValign(gtk.AlignCenterValue)
+	check.SetVisible(selecting)
+	check.SetSensitive(!s.busy)
+	toggledCb := func(_ gtk.CheckButton) {
+		s.updateCount()
+	}
+	check.ConnectToggled(&toggledCb)
+	row.AddPrefix(&check.Widget)
+	s.checks = append(s.checks, check)
+
+	if action != nil {
+		action.SetVisible(!selecting)
+		s.actions = append(s.actions, action)
+	}
+	s.toggle.SetVisible(true)
+}
+
+// finishRows hides the Select toggle, and leaves selection mode, when the
+// rebuilt list has nothing to select
+func (s *batchSelection) finishRows() {
+	if len(s.checks) > 0 {
+		return
+	}
+	s.toggle.SetActive(false)
+	s.toggle.SetVisible(false)
+}
+
+func (s *batchSelection) setSelecting(on bool) {
+	for _, check := range s.checks {
+		check.SetVisible(on)
+		if !on {
+			check.SetActive(false)
+		}
+	}
+	for _, action := range s.actions {
+		action.SetVisible(!on)
+	}
+	s.bar.SetVisible(on)
+	if on {
+		s.expander.SetExpanded(true)
+	}
+	s.updateCount()
+}
+
+// selected returns the indices, in addRow order, of the checked rows
+func (s *batchSelection) selected() []int {
+	var indices []int
+	for i, check := range s.checks {
+		if check.GetActive() {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (s *batchSelection) updateCount() {
+	if s.busy {
+		return
+	}
+	n := len(s.selected())
+	if n == 0 {
+		s.bar.SetTitle("No packages selected")
+		s.runBtn.SetLabel(s.verb)
+	} else {
+		s.bar.SetTitle(fmt.Sprintf("%d of %d selected", n, len(s.checks)))
+		s.runBtn.SetLabel(fmt.Sprintf("%s %d selected", s.verb, n))
+	}
+	s.bar.SetSubtitle("")
+	s.runBtn.SetSensitive(n > 0)
+	s.allBtn.SetSensitive(n < len(s.checks))
+}
+
+func (s *batchSelection) run() {
+	indices := s.selected()
+	if len(indices) == 0 {
+		return
+	}
+
+	s.busy = true
+	s.toggle.SetSensitive(false)
+	s.allBtn.SetSensitive(false)
+	s.runBtn.SetSensitive(false)
+	s.runBtn.SetLabel("Preparing...")
+	for _, check := range s.checks {
+		check.SetSensitive(false)
+	}
+
+	s.onRun(indices)
+}
+
+// progress shows how many of a running batch's items have finished
+func (s *batchSelection) progress(done, total int) {
+	s.runBtn.SetLabel("Working...")
+	s.bar.SetSubtitle(fmt.Sprintf("%d of %d done", done, total))
+}
+
+// resume re-enables the selection after a batch was not started, such as
+// when its preview was cancelled, keeping the rows checked
+func (s *batchSelection) resume() {
+	s.busy = false
+	s.toggle.SetSensitive(true)
+	for _, check := range s.checks {
+		check.SetSensitive(true)
+	}
+	s.updateCount()
+}
+
+// finish leaves selection mode once a batch is over; the caller reloads
+// the list, which rebuilds the rows
+func (s *batchSelection) finish() {
+	s.busy = false
+	s.toggle.SetSensitive(true)
+	for _, check := range s.checks {
+		check.SetSensitive(true)
+	}
+	s.toggle.SetActive(false)
+	s.updateCount()
+}
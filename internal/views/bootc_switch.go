@@ -0,0 +1,130 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/frostyard/chairlift/internal/bootc"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// addSwitchImageRow adds a Change... button to row that switches the
+// system to another image reference, starting from ref
+func (uh *UserHome) addSwitchImageRow(row *adw.ActionRow, ref *bootc.ImageReference) {
+	button := gtk.NewButtonWithLabel("Change...")
+	button.SetValign(gtk.AlignCenterValue)
+	clickedCb := func(_ gtk.Button) {
+		uh.showSwitchImageDialog(row, button, ref)
+	}
+	button.ConnectClicked(&clickedCb)
+	row.AddSuffix(&button.Widget)
+}
+
+// showSwitchImageDialog asks for the image to switch to and explains what
+// switching does before anything runs
+func (uh *UserHome) showSwitchImageDialog(row *adw.ActionRow, button *gtk.Button, ref *bootc.ImageReference) {
+	dialog := adw.NewAlertDialog(
+		"Change System Image?",
+		"The new image is downloaded and staged, and replaces the running system at the next restart. "+
+			"Later updates come from the new image. Your applications and files are kept, and the current system stays available as the rollback. "+
+			"Switching to an image from a different distribution or an untested channel can leave the system unable to boot into it.",
+	)
+
+	fields := adw.NewPreferencesGroup()
+	imageRow := adw.NewEntryRow()
+	imageRow.SetTitle("Image")
+	imageRow.SetText(ref.Image)
+	fields.Add(&imageRow.Widget)
+
+	dialog.SetExtraChild(&fields.Widget)
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("switch", "Switch")
+	dialog.SetResponseAppearance("switch", adw.ResponseDestructiveValue)
+	dialog.SetDefaultResponse("cancel")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response != "switch" {
+			return
+		}
+
+		image := strings.TrimSpace(imageRow.GetText())
+		if err := bootc.ValidateImageRef(image); err != nil {
+			uh.toastAdder.ShowErrorToast(err.Error())
+			return
+		}
+		if image == ref.Image {
+			uh.toastAdder.ShowToast("The system already uses this image")
+			return
+		}
+		uh.switchImage(row, button, image, ref.Transport)
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(&uh.systemPrefsPage.Widget)
+}
+
+// switchImage runs bootc switch, reporting progress in row's subtitle
+func (uh *UserHome) switchImage(row *adw.ActionRow, button *gtk.Button, image, transport string) {
+	previous := row.GetSubtitle()
+	button.SetSensitive(false)
+	button.SetLabel("Switching...")
+	row.SetSubtitle(fmt.Sprintf("Switching to %s...", image))
+
+	// Pulling a whole image; logging out half-way means starting over
+	inhibitCookie := uh.toastAdder.Inhibit("Switching the system image")
+
+	go func() {
+		ctx, cancel := bootc.DefaultContext()
+		defer cancel()
+
+		progressCh := make(chan bootc.ProgressEvent)
+
+		var switchErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switchErr = bootc.Switch(ctx, image, transport, progressCh)
+		}()
+
+		for evt := range progressCh {
+			if evt.Type != bootc.EventMessage {
+				continue
+			}
+			msg := evt.Message
+			sgtk.RunOnMainThread(func() {
+				row.SetSubtitle(msg)
+			})
+		}
+		wg.Wait()
+
+		sgtk.RunOnMainThread(func() {
+			uh.toastAdder.Uninhibit(inhibitCookie)
+			button.SetSensitive(true)
+			button.SetLabel("Change...")
+
+			if switchErr != nil {
+				row.SetSubtitle(previous)
+				uh.toastAdder.ShowErrorToast(bootcFailureText("Switch failed", switchErr))
+				return
+			}
+			if bootc.IsDryRun() {
+				row.SetSubtitle(previous)
+			} else {
+				row.SetSubtitle(fmt.Sprintf("%s — restart to apply", image))
+				// The switch staged a deployment; show it on the Updates page
+				if uh.bootcUpdatesGroup != nil {
+					go uh.loadBootcUpdateStatus(uh.bootcUpdatesGroup)
+				}
+				uh.offerRestart(&uh.systemPrefsPage.Widget, "New System Image Staged")
+			}
+			uh.toastAdder.ShowToast(actionmsg.BootcSwitch(bootc.IsDryRun(), image))
+		})
+	}()
+}
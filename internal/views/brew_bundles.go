@@ -0,0 +1,205 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// bundleTitle turns a Brewfile path such as /usr/share/snow/bundles/dev.Brewfile
+// into a row title ("dev").
+func bundleTitle(path string) string {
+	name := filepath.Base(path)
+	if trimmed := strings.TrimSuffix(name, ".Brewfile"); trimmed != "" {
+		return trimmed
+	}
+	return name
+}
+
+// loadBrewBundles lists the curated Brewfiles found in the configured
+// bundles_paths, each with an Install button. The group is hidden when
+// Homebrew is not installed.
+func (uh *UserHome) loadBrewBundles(dirs []string) {
+	if !homebrew.IsInstalledCached() {
+		sgtk.RunOnMainThread(func() {
+			uh.brewBundlesGroup.SetVisible(false)
+		})
+		return
+	}
+
+	bundles := homebrew.ListBundles(dirs)
+
+	sgtk.RunOnMainThread(func() {
+		if len(bundles) == 0 {
+			return
+		}
+
+		// PreferencesGroup only appends, so lift the chooser and progress
+		// rows off and put them back after the bundles.
+		uh.brewBundlesGroup.Remove(&uh.bundleChooseRow.Widget)
+		uh.brewBundlesGroup.Remove(&uh.bundleProgressExpander.Widget)
+
+		for _, path := range bundles {
+			row := adw.NewActionRow()
+			row.SetTitle(bundleTitle(path))
+			row.SetSubtitle(path)
+
+			installBtn := gtk.NewButtonWithLabel("Install")
+			installBtn.SetValign(gtk.AlignCenterValue)
+			bundlePath := path
+			clickedCb := func(_ gtk.Button) {
+				uh.installBrewfile(bundlePath)
+			}
+			installBtn.ConnectClicked(&clickedCb)
+			uh.bundleInstallBtns = append(uh.bundleInstallBtns, installBtn)
+
+			row.AddSuffix(&installBtn.Widget)
+			uh.brewBundlesGroup.Add(&row.Widget)
+		}
+
+		uh.brewBundlesGroup.Add(&uh.bundleChooseRow.Widget)
+		uh.brewBundlesGroup.Add(&uh.bundleProgressExpander.Widget)
+	})
+}
+
+// chooseBrewfile opens a file chooser and installs the picked Brewfile
+func (uh *UserHome) chooseBrewfile() {
+	dialog := gtk.NewFileDialog()
+	dialog.SetTitle("Install from Brewfile")
+	dialog.SetAcceptLabel("Install")
+
+	root := uh.applicationsPrefsPage.GetRoot()
+	if root == nil {
+		return
+	}
+	parent := gtk.WindowNewFromInternalPtr(root.Ptr)
+
+	// Cancelling the chooser returns an error from OpenFinish; there is
+	// nothing to report in that case.
+	openCb := gio.AsyncReadyCallback(func(_, result, _ uintptr) {
+		file, err := dialog.OpenFinish(&gio.AsyncResultBase{Ptr: result})
+		if err != nil || file == nil {
+			return
+		}
+		if path := file.GetPath(); path != "" {
+			uh.installBrewfile(path)
+		}
+	})
+	dialog.Open(parent, gio.NewCancellable(), &openCb, 0)
+}
+
+// installBrewfile runs brew bundle install for path, streaming its output
+// into the Details log and summarising per-package results when done. Only
+// one install runs at a time; every Install button is disabled meanwhile.
+func (uh *UserHome) installBrewfile(path string) {
+	expander := uh.bundleProgressExpander
+	title := bundleTitle(path)
+
+	for _, btn := range uh.bundleInstallBtns {
+		btn.SetSensitive(false)
+	}
+
+	for _, row := range uh.bundleProgressRows {
+		expander.Remove(row)
+	}
+	uh.bundleProgressRows = nil
+
+	expander.SetVisible(true)
+	expander.SetExpanded(true)
+	expander.SetTitle(fmt.Sprintf("Installing %s", title))
+	expander.SetSubtitle("Running...")
+
+	activityRow := adw.NewActionRow()
+	activityRow.SetTitle("Progress")
+	activityRow.SetSubtitle("Starting brew bundle...")
+	spinner := gtk.NewSpinner()
+	spinner.Start()
+	activityRow.AddSuffix(&spinner.Widget)
+	expander.AddRow(&activityRow.Widget)
+
+	logExpander := adw.NewExpanderRow()
+	logExpander.SetTitle("Details")
+	logExpander.SetSubtitle("View output")
+	expander.AddRow(&logExpander.Widget)
+
+	uh.bundleProgressRows = []*gtk.Widget{&activityRow.Widget, &logExpander.Widget}
+
+	go func() {
+		ctx, cancel := homebrew.BundleContext(uh.ctx)
+		defer cancel()
+
+		eventCh := make(chan homebrew.BundleEvent)
+
+		var installErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			installErr = homebrew.BundleInstallStreaming(ctx, path, eventCh)
+		}()
+
+		statuses := make(map[string]homebrew.BundleStatus)
+		for event := range eventCh {
+			evt := event
+			if evt.Package != "" {
+				statuses[evt.Package] = evt.Status
+			}
+			sgtk.RunOnMainThread(func() {
+				lineRow := adw.NewActionRow()
+				lineRow.SetTitle(evt.Line)
+				lineRow.SetSubtitle(time.Now().Format("15:04:05"))
+				if evt.Status == homebrew.BundleFailed {
+					errIcon := gtk.NewImageFromIconName("dialog-error-symbolic")
+					lineRow.AddPrefix(&errIcon.Widget)
+					logExpander.SetExpanded(true)
+				}
+				logExpander.AddRow(&lineRow.Widget)
+				activityRow.SetSubtitle(evt.Line)
+			})
+		}
+
+		wg.Wait()
+		installed, using, failed := homebrew.BundleSummary(statuses)
+
+		sgtk.RunOnMainThread(func() {
+			spinner.Stop()
+			for _, btn := range uh.bundleInstallBtns {
+				btn.SetSensitive(true)
+			}
+			expander.SetTitle(title)
+
+			summary := fmt.Sprintf("%d installed, %d already present", installed, using)
+			if len(failed) > 0 {
+				summary += fmt.Sprintf(", %d failed: %s", len(failed), strings.Join(failed, ", "))
+			}
+			activityRow.SetSubtitle(summary)
+
+			switch {
+			case len(failed) > 0:
+				expander.SetSubtitle(summary)
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Brewfile %s: %d failed: %s", title, len(failed), strings.Join(failed, ", ")))
+			case installErr != nil:
+				expander.SetSubtitle(fmt.Sprintf("Install failed: %v", installErr))
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Brewfile install failed: %v", installErr))
+			default:
+				expander.SetSubtitle(summary)
+				uh.toastAdder.ShowToast(actionmsg.BundleInstall(homebrew.IsDryRun(), title, installed, using))
+				// Newly installed formulae and casks belong in the lists.
+				if uh.formulaeExpander != nil {
+					go uh.loadHomebrewPackages()
+				}
+			}
+		})
+	}()
+}
@@ -0,0 +1,24 @@
+package views
+
+import (
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// addCopyButton adds a flat copy button to row that puts value on the
+// clipboard. value may differ from what the row displays, e.g. a full
+// digest behind a truncated subtitle.
+func (uh *UserHome) addCopyButton(row *adw.ActionRow, value string) {
+	button := gtk.NewButtonFromIconName("edit-copy-symbolic")
+	button.SetValign(gtk.AlignCenterValue)
+	button.AddCssClass("flat")
+	button.SetTooltipText("Copy")
+
+	clickedCb := func(btn gtk.Button) {
+		btn.GetClipboard().SetText(value)
+		uh.toastAdder.ShowToast("Copied to clipboard")
+	}
+	button.ConnectClicked(&clickedCb)
+
+	row.AddSuffix(&button.Widget)
+}
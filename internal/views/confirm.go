@@ -0,0 +1,27 @@
+package views
+
+import (
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// confirmDestructive asks the user to confirm an action that removes
+// something, calling onConfirm on the main thread only if they pick
+// actionLabel. Cancel is both the default and the close response, so
+// Enter and Escape never trigger the destructive path.
+func (uh *UserHome) confirmDestructive(parent *gtk.Widget, heading, body, actionLabel string, onConfirm func()) {
+	dialog := adw.NewAlertDialog(heading, body)
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("confirm", actionLabel)
+	dialog.SetResponseAppearance("confirm", adw.ResponseDestructiveValue)
+	dialog.SetDefaultResponse("cancel")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response == "confirm" {
+			onConfirm()
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(parent)
+}
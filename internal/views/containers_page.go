@@ -0,0 +1,353 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/containers"
+	"github.com/frostyard/chairlift/internal/preview"
+	"github.com/frostyard/chairlift/internal/systemd"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// containersGroup holds the widgets of one container engine's group on the
+// Containers page
+type containersGroup struct {
+	engine             *containers.Engine
+	group              *adw.PreferencesGroup
+	stoppedRow         *adw.ActionRow // Shown instead of the lists while the API socket is missing
+	containersExpander *adw.ExpanderRow
+	imagesExpander     *adw.ExpanderRow
+	pruneBtn           *gtk.Button
+	containerRows      []*adw.ActionRow // Store references for cleanup
+	imageRows          []*adw.ActionRow // Store references for cleanup
+}
+
+// containerEngines returns the engines that are installed or listening and
+// whose group is enabled. The page is only added to the sidebar when this
+// is non-empty.
+func (uh *UserHome) containerEngines() []*containers.Engine {
+	var engines []*containers.Engine
+	for _, e := range containers.Engines() {
+		if (e.Installed || e.Running()) && uh.config.IsGroupEnabled("containers_page", e.ID+"_group") {
+			engines = append(engines, e)
+		}
+	}
+	return engines
+}
+
+// buildContainersPage builds one group per engine, each listing its
+// containers and images
+func (uh *UserHome) buildContainersPage(engines []*containers.Engine) {
+	page := uh.containersPrefsPage
+	if page == nil {
+		return
+	}
+
+	for _, e := range engines {
+		cg := &containersGroup{engine: e}
+
+		cg.group = adw.NewPreferencesGroup()
+		cg.group.SetTitle(e.Title)
+		cg.group.SetDescription("Loading containers...")
+		uh.addRefreshButton(cg.group, func() { uh.loadContainers(cg) })
+
+		cg.stoppedRow = adw.NewActionRow()
+		cg.stoppedRow.SetTitle(fmt.Sprintf("%s Is Not Running", e.Title))
+		cg.stoppedRow.SetVisible(false)
+		if e.ID == "podman" && systemd.IsInstalled() {
+			cg.stoppedRow.SetSubtitle(fmt.Sprintf("Start %s to manage containers here", containers.PodmanSocketUnit))
+			startBtn := gtk.NewButtonWithLabel("Start")
+			startBtn.SetValign(gtk.AlignCenterValue)
+			startBtn.AddCssClass("suggested-action")
+			startCb := func(_ gtk.Button) {
+				uh.onStartPodmanSocket(cg, startBtn)
+			}
+			startBtn.ConnectClicked(&startCb)
+			cg.stoppedRow.AddSuffix(&startBtn.Widget)
+		} else {
+			cg.stoppedRow.SetSubtitle(fmt.Sprintf("Nothing is listening on %s", e.Socket))
+		}
+		cg.group.Add(&cg.stoppedRow.Widget)
+
+		cg.containersExpander = adw.NewExpanderRow()
+		cg.containersExpander.SetTitle("Containers")
+		cg.containersExpander.SetSubtitle("Loading...")
+		cg.group.Add(&cg.containersExpander.Widget)
+
+		cg.imagesExpander = adw.NewExpanderRow()
+		cg.imagesExpander.SetTitle("Images")
+		cg.imagesExpander.SetSubtitle("Loading...")
+
+		cg.pruneBtn = gtk.NewButtonWithLabel("Remove Unused")
+		cg.pruneBtn.SetValign(gtk.AlignCenterValue)
+		cg.pruneBtn.SetTooltipText("Remove every image no container uses")
+		cg.pruneBtn.SetSensitive(false)
+		pruneCb := func(_ gtk.Button) {
+			uh.confirmDestructive(&uh.containersPrefsPage.Widget,
+				"Remove Unused Images?",
+				fmt.Sprintf("Every %s image that no container uses, including tagged ones, will be deleted. They will be downloaded again the next time a container needs them.", e.Title),
+				"Remove",
+				func() { uh.onPruneImages(cg) })
+		}
+		cg.pruneBtn.ConnectClicked(&pruneCb)
+		cg.imagesExpander.AddSuffix(&cg.pruneBtn.Widget)
+		cg.group.Add(&cg.imagesExpander.Widget)
+
+		page.Add(cg.group)
+		uh.containersGroups = append(uh.containersGroups, cg)
+
+		go uh.loadContainers(cg)
+	}
+}
+
+// loadContainers lists an engine's containers and images, or shows that
+// its API socket is not there
+func (uh *UserHome) loadContainers(cg *containersGroup) {
+	if !cg.engine.Running() {
+		sgtk.RunOnMainThread(func() {
+			uh.clearContainerRows(cg)
+			cg.group.SetDescription("")
+			cg.stoppedRow.SetVisible(true)
+			cg.containersExpander.SetVisible(false)
+			cg.imagesExpander.SetVisible(false)
+		})
+		return
+	}
+
+	list, err := cg.engine.ListContainers(uh.ctx)
+	var images []containers.Image
+	if err == nil {
+		images, err = cg.engine.ListImages(uh.ctx)
+	}
+
+	sgtk.RunOnMainThread(func() {
+		uh.clearContainerRows(cg)
+		cg.stoppedRow.SetVisible(false)
+		cg.containersExpander.SetVisible(true)
+		cg.imagesExpander.SetVisible(true)
+
+		if err != nil {
+			cg.group.SetDescription(fmt.Sprintf("Error: %v", err))
+			cg.containersExpander.SetSubtitle("Failed to load")
+			cg.imagesExpander.SetSubtitle("Failed to load")
+			cg.pruneBtn.SetSensitive(false)
+			return
+		}
+
+		running := 0
+		for _, c := range list {
+			if c.Running() {
+				running++
+			}
+			row := uh.newContainerRow(cg, c)
+			cg.containersExpander.AddRow(&row.Widget)
+			cg.containerRows = append(cg.containerRows, row)
+		}
+		cg.containersExpander.SetSubtitle(fmt.Sprintf("%d running, %d stopped", running, len(list)-running))
+		cg.containersExpander.SetEnableExpansion(len(list) > 0)
+
+		var total int64
+		for _, i := range images {
+			total += i.Size
+			row := uh.newImageRow(cg, i)
+			cg.imagesExpander.AddRow(&row.Widget)
+			cg.imageRows = append(cg.imageRows, row)
+		}
+		// Images share layers, so the sum is an upper bound
+		cg.imagesExpander.SetSubtitle(fmt.Sprintf("%d images, up to %s", len(images), preview.FormatSize(total)))
+		cg.imagesExpander.SetEnableExpansion(len(images) > 0)
+		cg.pruneBtn.SetSensitive(len(images) > 0)
+
+		cg.group.SetDescription(fmt.Sprintf("Containers and images of %s", cg.engine.Socket))
+	})
+}
+
+// clearContainerRows removes the container and image rows of a group
+func (uh *UserHome) clearContainerRows(cg *containersGroup) {
+	for _, row := range cg.containerRows {
+		cg.containersExpander.Remove(&row.Widget)
+	}
+	cg.containerRows = nil
+	for _, row := range cg.imageRows {
+		cg.imagesExpander.Remove(&row.Widget)
+	}
+	cg.imageRows = nil
+}
+
+// newContainerRow returns a container's row: Stop while it runs, Start and
+// Remove while it does not
+func (uh *UserHome) newContainerRow(cg *containersGroup, c containers.Container) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetTitle(c.Name)
+	row.SetTitleSelectable(true)
+	subtitle := fmt.Sprintf("%s · %s", c.Image, c.Status)
+	if c.Size > 0 {
+		subtitle += fmt.Sprintf(" · %s written", preview.FormatSize(c.Size))
+	}
+	row.SetSubtitle(subtitle)
+
+	if c.Running() {
+		stopBtn := gtk.NewButtonWithLabel("Stop")
+		stopBtn.SetValign(gtk.AlignCenterValue)
+		stopCb := func(_ gtk.Button) {
+			uh.runContainerAction(cg, stopBtn, "Stopping...", "stop", c.Name, func() error {
+				return cg.engine.StopContainer(uh.ctx, c)
+			})
+		}
+		stopBtn.ConnectClicked(&stopCb)
+		row.AddSuffix(&stopBtn.Widget)
+		return row
+	}
+
+	startBtn := gtk.NewButtonWithLabel("Start")
+	startBtn.SetValign(gtk.AlignCenterValue)
+	startCb := func(_ gtk.Button) {
+		uh.runContainerAction(cg, startBtn, "Starting...", "start", c.Name, func() error {
+			return cg.engine.StartContainer(uh.ctx, c)
+		})
+	}
+	startBtn.ConnectClicked(&startCb)
+	row.AddSuffix(&startBtn.Widget)
+
+	removeBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
+	removeBtn.SetValign(gtk.AlignCenterValue)
+	removeBtn.AddCssClass("flat")
+	removeBtn.SetTooltipText("Remove")
+	removeCb := func(_ gtk.Button) {
+		uh.confirmDestructive(&uh.containersPrefsPage.Widget,
+			fmt.Sprintf("Remove %s?", c.Name),
+			"The container and its anonymous volumes will be deleted. Its image is kept.",
+			"Remove",
+			func() {
+				uh.runContainerAction(cg, removeBtn, "", "remove", c.Name, func() error {
+					return cg.engine.RemoveContainer(uh.ctx, c)
+				})
+			})
+	}
+	removeBtn.ConnectClicked(&removeCb)
+	row.AddSuffix(&removeBtn.Widget)
+	return row
+}
+
+// newImageRow returns an image's row, with a Remove button unless a
+// container is known to use the image
+func (uh *UserHome) newImageRow(cg *containersGroup, i containers.Image) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetTitle(i.Name())
+	row.SetTitleSelectable(true)
+	row.SetSubtitle(fmt.Sprintf("%s · created %s", preview.FormatSize(i.Size), i.Created.Local().Format("2006-01-02")))
+
+	removeBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
+	removeBtn.SetValign(gtk.AlignCenterValue)
+	removeBtn.AddCssClass("flat")
+	if i.Containers > 0 {
+		removeBtn.SetSensitive(false)
+		removeBtn.SetTooltipText("In use by a container")
+	} else {
+		removeBtn.SetTooltipText("Remove")
+	}
+	name := i.Name()
+	removeCb := func(_ gtk.Button) {
+		uh.confirmDestructive(&uh.containersPrefsPage.Widget,
+			fmt.Sprintf("Remove %s?", name),
+			"The image will be deleted. It will be downloaded again the next time a container needs it.",
+			"Remove",
+			func() {
+				uh.runContainerAction(cg, removeBtn, "", "remove", name, func() error {
+					return cg.engine.RemoveImage(uh.ctx, i)
+				})
+			})
+	}
+	removeBtn.ConnectClicked(&removeCb)
+	row.AddSuffix(&removeBtn.Widget)
+	return row
+}
+
+// runContainerAction runs action in the background with button disabled,
+// showing busyLabel on it when that is not empty, then toasts the result
+// and reloads the group
+func (uh *UserHome) runContainerAction(cg *containersGroup, button *gtk.Button, busyLabel, verb, name string, action func() error) {
+	label := button.GetLabel()
+	button.SetSensitive(false)
+	if busyLabel != "" {
+		button.SetLabel(busyLabel)
+	}
+
+	go func() {
+		err := action()
+
+		sgtk.RunOnMainThread(func() {
+			button.SetSensitive(true)
+			if busyLabel != "" {
+				button.SetLabel(label)
+			}
+
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not %s %s: %v", verb, name, err))
+				return
+			}
+			uh.toastAdder.ShowToast(actionmsg.ContainerAction(containers.IsDryRun(), verb, name))
+			if !containers.IsDryRun() {
+				go uh.loadContainers(cg)
+			}
+		})
+	}()
+}
+
+// onPruneImages removes every unused image of an engine, then reloads its
+// group
+func (uh *UserHome) onPruneImages(cg *containersGroup) {
+	cg.pruneBtn.SetSensitive(false)
+	cg.pruneBtn.SetLabel("Removing...")
+
+	go func() {
+		freed, err := cg.engine.PruneImages(uh.ctx)
+
+		sgtk.RunOnMainThread(func() {
+			cg.pruneBtn.SetSensitive(true)
+			cg.pruneBtn.SetLabel("Remove Unused")
+
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Removing unused images failed: %v", err))
+				return
+			}
+			uh.toastAdder.ShowToast(actionmsg.ImagePrune(containers.IsDryRun(), cg.engine.Title, preview.FormatSize(freed)))
+			if !containers.IsDryRun() {
+				go uh.loadContainers(cg)
+			}
+		})
+	}()
+}
+
+// onStartPodmanSocket enables and starts the user's Podman API socket, so
+// it also listens after the next login, then reloads the group
+func (uh *UserHome) onStartPodmanSocket(cg *containersGroup, button *gtk.Button) {
+	button.SetSensitive(false)
+	button.SetLabel("Starting...")
+
+	go func() {
+		err := systemd.Enable(uh.ctx, containers.PodmanSocketUnit, true)
+		if err == nil {
+			err = systemd.Start(uh.ctx, containers.PodmanSocketUnit, true)
+		}
+
+		sgtk.RunOnMainThread(func() {
+			button.SetSensitive(true)
+			button.SetLabel("Start")
+
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not start %s: %v", containers.PodmanSocketUnit, err))
+				return
+			}
+			uh.toastAdder.ShowToast(actionmsg.ServiceAction(systemd.IsDryRun(), "start", containers.PodmanSocketUnit))
+			if !systemd.IsDryRun() {
+				go uh.loadContainers(cg)
+			}
+		})
+	}()
+}
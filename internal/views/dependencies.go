@@ -0,0 +1,182 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// newDependenciesButton builds the flat row button that opens a
+// dependencies dialog
+func newDependenciesButton(onClicked func()) *gtk.Button {
+	btn := gtk.NewButtonFromIconName("view-list-bullet-symbolic")
+	btn.SetValign(gtk.AlignCenterValue)
+	btn.SetTooltipText("Dependencies")
+	btn.AddCssClass("flat")
+	clickedCb := func(_ gtk.Button) {
+		onClicked()
+	}
+	btn.ConnectClicked(&clickedCb)
+	return btn
+}
+
+// newDependenciesDialog builds an empty dependencies dialog with one
+// group per title, each showing a loading row until filled
+func newDependenciesDialog(title string, groupTitles ...string) (*adw.PreferencesDialog, []*adw.PreferencesGroup, []*adw.ActionRow) {
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle(title)
+	dialog.SetContentWidth(480)
+	dialog.SetContentHeight(560)
+
+	page := adw.NewPreferencesPage()
+	groups := make([]*adw.PreferencesGroup, len(groupTitles))
+	loadingRows := make([]*adw.ActionRow, len(groupTitles))
+	for i, groupTitle := range groupTitles {
+		groups[i] = adw.NewPreferencesGroup()
+		groups[i].SetTitle(groupTitle)
+		page.Add(groups[i])
+
+		loadingRows[i] = adw.NewActionRow()
+		loadingRows[i].SetTitle("Loading...")
+		groups[i].Add(&loadingRows[i].Widget)
+	}
+	dialog.Add(page)
+	return dialog, groups, loadingRows
+}
+
+// addDependencyRow adds a titled row to group
+func addDependencyRow(group *adw.PreferencesGroup, title, subtitle string) {
+	row := adw.NewActionRow()
+	row.SetTitle(title)
+	row.SetSubtitle(subtitle)
+	group.Add(&row.Widget)
+}
+
+// showBrewDependencies opens a dialog with the installed dependency tree
+// of a formula or cask and, for formulae, the installed packages that
+// depend on it
+func (uh *UserHome) showBrewDependencies(name string, isCask bool) {
+	groupTitles := []string{"Depends On"}
+	if !isCask {
+		groupTitles = append(groupTitles, "Used By")
+	}
+	dialog, groups, loadingRows := newDependenciesDialog(fmt.Sprintf("%s Dependencies", name), groupTitles...)
+	dependsGroup := groups[0]
+
+	go func() {
+		deps, err := homebrew.Deps(uh.ctx, name, isCask)
+
+		sgtk.RunOnMainThread(func() {
+			dependsGroup.Remove(&loadingRows[0].Widget)
+			if err != nil {
+				addDependencyRow(dependsGroup, "Failed to load dependencies", err.Error())
+				return
+			}
+			if len(deps) == 0 {
+				addDependencyRow(dependsGroup, "No dependencies", fmt.Sprintf("%s does not need any other installed package", name))
+				return
+			}
+
+			dependsGroup.SetDescription(fmt.Sprintf("%d installed packages, direct and indirect", len(deps)))
+			// parents[d] is the latest dependency seen at depth d, which
+			// is what a dependency one level deeper was pulled in by
+			var parents []string
+			for _, dep := range deps {
+				depth := min(dep.Depth, len(parents))
+				parents = append(parents[:depth], dep.Name)
+				subtitle := "Direct dependency"
+				if depth > 0 {
+					subtitle = fmt.Sprintf("Needed by %s", parents[depth-1])
+				}
+				addDependencyRow(dependsGroup, dep.Name, subtitle)
+			}
+		})
+	}()
+
+	if !isCask {
+		usedByGroup := groups[1]
+		go func() {
+			users, err := homebrew.Uses(uh.ctx, name)
+
+			sgtk.RunOnMainThread(func() {
+				usedByGroup.Remove(&loadingRows[1].Widget)
+				if err != nil {
+					addDependencyRow(usedByGroup, "Failed to load dependents", err.Error())
+					return
+				}
+				if len(users) == 0 {
+					addDependencyRow(usedByGroup, "Nothing depends on it", fmt.Sprintf("%s can be uninstalled without breaking another package", name))
+					return
+				}
+
+				usedByGroup.SetDescription(fmt.Sprintf("Homebrew will not uninstall %s while these are installed", name))
+				for _, dependent := range users {
+					addDependencyRow(usedByGroup, dependent, "")
+				}
+			})
+		}()
+	}
+
+	dialog.Present(&uh.applicationsPrefsPage.Widget)
+}
+
+// showFlatpakDependencies opens a dialog with an application's runtime and
+// installed extensions, and the other applications sharing that runtime
+func (uh *UserHome) showFlatpakDependencies(app flatpak.Application, user bool) {
+	dialog, groups, loadingRows := newDependenciesDialog(fmt.Sprintf("%s Dependencies", app.Name),
+		"Runtime", "Extensions", "Shares Runtime With")
+	runtimeGroup, extensionsGroup, sharedGroup := groups[0], groups[1], groups[2]
+	appID := app.ApplicationID
+
+	go func() {
+		deps, err := flatpak.Runtimes(uh.ctx, appID, user)
+		var shared []string
+		var sharedErr error
+		if err == nil && deps.Runtime != "" {
+			shared, sharedErr = flatpak.RuntimeUsers(uh.ctx, deps.Runtime)
+		}
+
+		sgtk.RunOnMainThread(func() {
+			for i, group := range groups {
+				group.Remove(&loadingRows[i].Widget)
+			}
+			if err != nil {
+				addDependencyRow(runtimeGroup, "Failed to load dependencies", err.Error())
+				return
+			}
+
+			addDependencyRow(runtimeGroup, deps.Runtime, "Installed with the application and removed only once no application uses it")
+
+			if len(deps.Extensions) == 0 {
+				addDependencyRow(extensionsGroup, "No extensions", "")
+			}
+			for _, ext := range deps.Extensions {
+				addDependencyRow(extensionsGroup, ext, "")
+			}
+
+			if sharedErr != nil {
+				addDependencyRow(sharedGroup, "Failed to load applications", sharedErr.Error())
+				return
+			}
+			others := 0
+			for _, other := range shared {
+				if other == appID {
+					continue
+				}
+				others++
+				addDependencyRow(sharedGroup, other, "")
+			}
+			if others == 0 {
+				addDependencyRow(sharedGroup, "No other applications", "Uninstalling this application leaves the runtime unused")
+			}
+		})
+	}()
+
+	dialog.Present(&uh.applicationsPrefsPage.Widget)
+}
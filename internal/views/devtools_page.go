@@ -0,0 +1,233 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/batch"
+	"github.com/frostyard/chairlift/internal/devtools"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// devtoolsGroup holds the widgets of one package manager's group on the
+// Developer Tools page
+type devtoolsGroup struct {
+	manager           *devtools.Manager
+	group             *adw.PreferencesGroup
+	installedExpander *adw.ExpanderRow
+	outdatedExpander  *adw.ExpanderRow
+	upgradeAllBtn     *gtk.Button
+	installedRows     []*adw.ActionRow // Store references for cleanup
+	outdatedRows      []*adw.ActionRow // Store references for cleanup
+	outdated          []devtools.Tool
+}
+
+// devtoolsManagers returns the installed package managers whose group is
+// enabled. The page is only added to the sidebar when this is non-empty.
+func (uh *UserHome) devtoolsManagers() []*devtools.Manager {
+	var managers []*devtools.Manager
+	for _, m := range devtools.Available() {
+		if uh.config.IsGroupEnabled("devtools_page", m.ID+"_group") {
+			managers = append(managers, m)
+		}
+	}
+	return managers
+}
+
+// buildDevtoolsPage builds one group per package manager, each listing its
+// installed tools and the ones with a newer version
+func (uh *UserHome) buildDevtoolsPage(managers []*devtools.Manager) {
+	page := uh.devtoolsPrefsPage
+	if page == nil {
+		return
+	}
+
+	for _, m := range managers {
+		dg := &devtoolsGroup{manager: m}
+
+		dg.group = adw.NewPreferencesGroup()
+		dg.group.SetTitle(m.Title)
+		dg.group.SetDescription("Loading tools...")
+		uh.addRefreshButton(dg.group, func() { uh.loadDevtools(dg) })
+
+		dg.outdatedExpander = adw.NewExpanderRow()
+		dg.outdatedExpander.SetTitle("Updates Available")
+		dg.outdatedExpander.SetSubtitle("Checking for updates...")
+		dg.outdatedExpander.SetEnableExpansion(false)
+
+		dg.upgradeAllBtn = gtk.NewButtonWithLabel("Upgrade All")
+		dg.upgradeAllBtn.SetValign(gtk.AlignCenterValue)
+		dg.upgradeAllBtn.AddCssClass("suggested-action")
+		dg.upgradeAllBtn.SetVisible(false)
+		upgradeAllCb := func(_ gtk.Button) {
+			uh.onUpgradeAllDevtools(dg)
+		}
+		dg.upgradeAllBtn.ConnectClicked(&upgradeAllCb)
+		dg.outdatedExpander.AddSuffix(&dg.upgradeAllBtn.Widget)
+		dg.group.Add(&dg.outdatedExpander.Widget)
+
+		dg.installedExpander = adw.NewExpanderRow()
+		dg.installedExpander.SetTitle("Installed Tools")
+		dg.installedExpander.SetSubtitle("Loading...")
+		dg.group.Add(&dg.installedExpander.Widget)
+
+		page.Add(dg.group)
+		uh.devtoolsGroups = append(uh.devtoolsGroups, dg)
+
+		go uh.loadDevtools(dg)
+	}
+}
+
+// loadDevtools lists a manager's installed tools, then checks which are
+// outdated. The outdated check asks the package registry about every tool,
+// so the installed list is shown first.
+func (uh *UserHome) loadDevtools(dg *devtoolsGroup) {
+	tools, err := dg.manager.List(uh.ctx)
+
+	sgtk.RunOnMainThread(func() {
+		for _, row := range dg.installedRows {
+			dg.installedExpander.Remove(&row.Widget)
+		}
+		dg.installedRows = nil
+
+		if err != nil {
+			dg.group.SetDescription(fmt.Sprintf("Error: %v", err))
+			dg.installedExpander.SetSubtitle("Failed to load")
+			return
+		}
+
+		dg.group.SetDescription(fmt.Sprintf("Tools installed with %s", dg.manager.Command))
+		dg.installedExpander.SetSubtitle(fmt.Sprintf("%d installed", len(tools)))
+		for _, tool := range tools {
+			row := adw.NewActionRow()
+			row.SetTitle(tool.Name)
+			row.SetSubtitle(tool.Version)
+			dg.installedExpander.AddRow(&row.Widget)
+			dg.installedRows = append(dg.installedRows, row)
+		}
+	})
+	if err != nil {
+		return
+	}
+
+	outdated, err := dg.manager.Outdated(uh.ctx)
+
+	sgtk.RunOnMainThread(func() {
+		uh.showOutdatedDevtools(dg, outdated, err)
+	})
+}
+
+// showOutdatedDevtools fills the Updates Available expander with one row
+// per outdated tool, each with its own Upgrade button
+func (uh *UserHome) showOutdatedDevtools(dg *devtoolsGroup, outdated []devtools.Tool, err error) {
+	for _, row := range dg.outdatedRows {
+		dg.outdatedExpander.Remove(&row.Widget)
+	}
+	dg.outdatedRows = nil
+	dg.outdated = outdated
+
+	if err != nil {
+		dg.outdatedExpander.SetSubtitle(errorText(err))
+		dg.outdatedExpander.SetEnableExpansion(false)
+		dg.upgradeAllBtn.SetVisible(false)
+		return
+	}
+	if len(outdated) == 0 {
+		dg.outdatedExpander.SetSubtitle("All tools are up to date")
+		dg.outdatedExpander.SetEnableExpansion(false)
+		dg.upgradeAllBtn.SetVisible(false)
+		return
+	}
+
+	dg.outdatedExpander.SetSubtitle(fmt.Sprintf("%d can be upgraded", len(outdated)))
+	dg.outdatedExpander.SetEnableExpansion(true)
+	dg.upgradeAllBtn.SetVisible(true)
+	dg.upgradeAllBtn.SetSensitive(true)
+
+	for _, tool := range outdated {
+		row := adw.NewActionRow()
+		row.SetTitle(tool.Name)
+		row.SetSubtitle(fmt.Sprintf("%s → %s", tool.Version, tool.NewVersion))
+
+		upgradeBtn := gtk.NewButtonWithLabel("Upgrade")
+		upgradeBtn.SetValign(gtk.AlignCenterValue)
+		toolName := tool.Name
+		clickedCb := func(btn gtk.Button) {
+			upgradeBtn.SetSensitive(false)
+			upgradeBtn.SetLabel("Upgrading...")
+			go func() {
+				err := dg.manager.Upgrade(uh.ctx, toolName)
+				sgtk.RunOnMainThread(func() {
+					if err != nil {
+						upgradeBtn.SetSensitive(true)
+						upgradeBtn.SetLabel("Upgrade")
+						uh.toastAdder.ShowErrorToast(fmt.Sprintf("Upgrade failed: %v", err))
+						return
+					}
+					uh.toastAdder.ShowToast(actionmsg.Upgrade(devtools.IsDryRun(), toolName))
+					if !devtools.IsDryRun() {
+						go uh.loadDevtools(dg)
+					}
+				})
+			}()
+		}
+		upgradeBtn.ConnectClicked(&clickedCb)
+		row.AddSuffix(&upgradeBtn.Widget)
+
+		dg.outdatedExpander.AddRow(&row.Widget)
+		dg.outdatedRows = append(dg.outdatedRows, row)
+	}
+}
+
+// onUpgradeAllDevtools upgrades every outdated tool of one manager, one
+// at a time, updating each tool's row as it finishes
+func (uh *UserHome) onUpgradeAllDevtools(dg *devtoolsGroup) {
+	tools := dg.outdated
+	if len(tools) == 0 {
+		return
+	}
+	rows := make(map[string]*adw.ActionRow, len(dg.outdatedRows))
+	for i, row := range dg.outdatedRows {
+		rows[tools[i].Name] = row
+	}
+
+	dg.upgradeAllBtn.SetSensitive(false)
+	dg.upgradeAllBtn.SetLabel("Upgrading...")
+	inhibitCookie := uh.toastAdder.Inhibit(fmt.Sprintf("Upgrading %s tools", dg.manager.Title))
+
+	go func() {
+		errs := dg.manager.UpgradeAll(uh.ctx, tools, func(tool devtools.Tool, err error) {
+			sgtk.RunOnMainThread(func() {
+				row := rows[tool.Name]
+				if row == nil {
+					return
+				}
+				if err != nil {
+					row.SetSubtitle(fmt.Sprintf("Failed: %v", err))
+					return
+				}
+				row.SetSubtitle(fmt.Sprintf("Upgraded to %s", tool.NewVersion))
+			})
+		})
+		failed := batch.Failed(errs)
+
+		sgtk.RunOnMainThread(func() {
+			uh.toastAdder.Uninhibit(inhibitCookie)
+			dg.upgradeAllBtn.SetLabel("Upgrade All")
+			dg.upgradeAllBtn.SetSensitive(true)
+
+			if failed > 0 {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("%d of %d tools failed to upgrade", failed, len(tools)))
+			} else {
+				uh.toastAdder.ShowToast(actionmsg.BatchUpgrade(devtools.IsDryRun(), len(tools)))
+			}
+			if !devtools.IsDryRun() {
+				go uh.loadDevtools(dg)
+			}
+		})
+	}()
+}
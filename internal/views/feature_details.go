@@ -0,0 +1,127 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/updex"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// featureStatus describes whether a feature is enabled, disabled or masked
+func featureStatus(feat updex.Feature) string {
+	switch {
+	case feat.Masked:
+		return "Masked by the system configuration"
+	case feat.Enabled:
+		return "Enabled"
+	default:
+		return "Disabled"
+	}
+}
+
+// addFeatureDetailsButton adds a button that opens the feature's details
+func (uh *UserHome) addFeatureDetailsButton(row *adw.ActionRow, feat updex.Feature) {
+	detailsBtn := gtk.NewButtonFromIconName("dialog-information-symbolic")
+	detailsBtn.SetValign(gtk.AlignCenterValue)
+	detailsBtn.SetTooltipText("Details")
+	detailsBtn.AddCssClass("flat")
+	clickedCb := func(_ gtk.Button) {
+		uh.showFeatureDetails(feat)
+	}
+	detailsBtn.ConnectClicked(&clickedCb)
+	row.AddSuffix(&detailsBtn.Widget)
+}
+
+// showFeatureDetails opens a dialog with a feature's description, status,
+// documentation and definition file, and the installed and newest version
+// of each of its extensions from the last update check
+func (uh *UserHome) showFeatureDetails(feat updex.Feature) {
+	title := feat.Description
+	if title == "" {
+		title = feat.Name
+	}
+
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle(title)
+	dialog.SetContentWidth(480)
+	dialog.SetContentHeight(520)
+
+	page := adw.NewPreferencesPage()
+
+	aboutGroup := adw.NewPreferencesGroup()
+	aboutGroup.SetTitle("About")
+	page.Add(aboutGroup)
+
+	nameRow := adw.NewActionRow()
+	nameRow.SetTitle("Name")
+	nameRow.SetSubtitle(feat.Name)
+	aboutGroup.Add(&nameRow.Widget)
+
+	statusRow := adw.NewActionRow()
+	statusRow.SetTitle("Status")
+	statusRow.SetSubtitle(featureStatus(feat))
+	aboutGroup.Add(&statusRow.Widget)
+
+	if feat.Documentation != "" {
+		docURL := feat.Documentation
+		docRow := adw.NewActionRow()
+		docRow.SetTitle("Documentation")
+		docRow.SetSubtitle(docURL)
+		openBtn := gtk.NewButtonFromIconName("web-browser-symbolic")
+		openBtn.SetValign(gtk.AlignCenterValue)
+		openBtn.SetTooltipText("Open Documentation")
+		openBtn.AddCssClass("flat")
+		openClickedCb := func(_ gtk.Button) {
+			uh.openURL(docURL)
+		}
+		openBtn.ConnectClicked(&openClickedCb)
+		docRow.AddSuffix(&openBtn.Widget)
+		aboutGroup.Add(&docRow.Widget)
+	}
+
+	if feat.Source != "" {
+		sourceRow := adw.NewActionRow()
+		sourceRow.SetTitle("Defined In")
+		sourceRow.SetSubtitle(feat.Source)
+		aboutGroup.Add(&sourceRow.Widget)
+	}
+
+	extGroup := adw.NewPreferencesGroup()
+	extGroup.SetTitle("Extensions")
+	page.Add(extGroup)
+
+	if len(feat.Transfers) == 0 {
+		emptyRow := adw.NewActionRow()
+		emptyRow.SetTitle("No extensions")
+		extGroup.Add(&emptyRow.Widget)
+	}
+
+	versions := make(map[string]updex.CheckResult)
+	for _, result := range uh.featureChecks[feat.Name] {
+		versions[result.Component] = result
+	}
+	if !feat.Enabled {
+		extGroup.SetDescription("Versions are checked for enabled features only")
+	}
+	for _, component := range feat.Transfers {
+		row := adw.NewActionRow()
+		row.SetTitle(component)
+		result, ok := versions[component]
+		switch {
+		case !ok:
+			row.SetSubtitle("Version unknown")
+		case result.CurrentVersion == "":
+			row.SetSubtitle(fmt.Sprintf("Not downloaded — v%s available", result.NewestVersion))
+		case result.UpdateAvailable:
+			row.SetSubtitle(fmt.Sprintf("v%s installed — v%s available", result.CurrentVersion, result.NewestVersion))
+		default:
+			row.SetSubtitle(fmt.Sprintf("v%s installed (newest)", result.CurrentVersion))
+		}
+		extGroup.Add(&row.Widget)
+	}
+
+	dialog.Add(page)
+	dialog.Present(&uh.featuresPrefsPage.Widget)
+}
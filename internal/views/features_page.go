@@ -100,6 +100,10 @@ func (uh *UserHome) loadFeatures() {
 		}
 
 		uh.featuresGroup.SetDescription(fmt.Sprintf("%d features available", len(features)))
+		// Reloads after a removal replace the previous rows
+		for _, row := range uh.featureRows {
+			uh.featuresGroup.Remove(&row.Widget)
+		}
 		uh.featureRows = make(map[string]*adw.ActionRow)
 
 		for _, feat := range features {
@@ -119,6 +123,10 @@ func (uh *UserHome) loadFeatures() {
 			}
 			toggle.ConnectStateSet(&stateSetCb)
 
+			uh.addFeatureDetailsButton(row, feat)
+			if feat.Enabled {
+				uh.addFeatureRemoveButton(row, feat.Name)
+			}
 			row.AddSuffix(&toggle.Widget)
 			row.SetActivatableWidget(&toggle.Widget)
 			uh.featuresGroup.Add(&row.Widget)
@@ -126,25 +134,37 @@ func (uh *UserHome) loadFeatures() {
 		}
 
 		// Check for updates after rendering the feature list
-		go uh.checkFeatureUpdates(len(features))
+		go uh.checkFeatureUpdates()
 	})
 }
 
-// checkFeatureUpdates checks enabled features for available updates
-func (uh *UserHome) checkFeatureUpdates(totalFeatures int) {
+// checkFeatureUpdates checks enabled features for available updates,
+// marking each row and counting them into the update badge
+func (uh *UserHome) checkFeatureUpdates() {
 	ctx, cancel := updex.DefaultContext()
 	defer cancel()
 
 	checks, err := updex.CheckFeatures(ctx)
+	if err != nil {
+		log.Printf("Feature update check failed: %v", err)
+		return
+	}
 
-	sgtk.RunOnMainThread(func() {
-		if err != nil {
-			log.Printf("Feature update check failed: %v", err)
-			return
+	updateCount := 0
+	for _, check := range checks {
+		if len(check.Results) > 0 && check.Results[0].UpdateAvailable {
+			updateCount++
 		}
+	}
+	uh.updateCountMu.Lock()
+	uh.featureUpdateCount = updateCount
+	uh.updateCountMu.Unlock()
+	uh.updateBadgeCount()
 
-		updateCount := 0
+	sgtk.RunOnMainThread(func() {
+		uh.featureChecks = make(map[string][]updex.CheckResult)
 		for _, check := range checks {
+			uh.featureChecks[check.Feature] = check.Results
 			row, ok := uh.featureRows[check.Feature]
 			if !ok || len(check.Results) == 0 {
 				continue
@@ -153,14 +173,18 @@ func (uh *UserHome) checkFeatureUpdates(totalFeatures int) {
 			result := check.Results[0]
 			if result.UpdateAvailable {
 				row.SetSubtitle(fmt.Sprintf("%s — v%s → v%s available", check.Feature, result.CurrentVersion, result.NewestVersion))
-				updateCount++
 			} else {
 				row.SetSubtitle(fmt.Sprintf("%s — v%s", check.Feature, result.CurrentVersion))
 			}
 		}
 
-		if uh.featuresGroup != nil && updateCount > 0 {
-			uh.featuresGroup.SetDescription(fmt.Sprintf("%d features available (%d updates)", totalFeatures, updateCount))
+		if uh.featuresGroup == nil || len(uh.featureRows) == 0 {
+			return
+		}
+		if updateCount > 0 {
+			uh.featuresGroup.SetDescription(fmt.Sprintf("%d features available (%d updates)", len(uh.featureRows), updateCount))
+		} else {
+			uh.featuresGroup.SetDescription(fmt.Sprintf("%d features available", len(uh.featureRows)))
 		}
 	})
 }
@@ -197,15 +221,65 @@ func (uh *UserHome) onFeatureToggled(name string, enabled bool, toggle *gtk.Swit
 			}
 
 			uh.toastAdder.ShowToast(decision.Toast)
+			if decision.Confirm {
+				// The Remove button and update check follow the new state
+				go uh.loadFeatures()
+			}
 		})
 	}()
 }
 
+// addFeatureRemoveButton adds a Remove button that deletes an enabled
+// feature's downloaded extensions after confirmation
+func (uh *UserHome) addFeatureRemoveButton(row *adw.ActionRow, name string) {
+	removeBtn := gtk.NewButtonFromIconName("user-trash-symbolic")
+	removeBtn.SetValign(gtk.AlignCenterValue)
+	removeBtn.SetTooltipText("Remove (requires admin)")
+	removeBtn.AddCssClass("destructive-action")
+
+	clickedCb := func(_ gtk.Button) {
+		uh.confirmDestructive(&uh.featuresPrefsPage.Widget,
+			fmt.Sprintf("Remove %s?", name),
+			"The feature is disabled and its downloaded extensions are deleted. Extensions in use stay active until the next reboot.",
+			"Remove",
+			func() {
+				removeBtn.SetSensitive(false)
+				go uh.removeFeature(name, removeBtn)
+			})
+	}
+	removeBtn.ConnectClicked(&clickedCb)
+	row.AddSuffix(&removeBtn.Widget)
+}
+
+// removeFeature runs updex.RemoveFeature and reloads the feature list
+func (uh *UserHome) removeFeature(name string, btn *gtk.Button) {
+	ctx, cancel := updex.DefaultContext()
+	defer cancel()
+
+	err := updex.RemoveFeature(ctx, name)
+
+	sgtk.RunOnMainThread(func() {
+		if err != nil {
+			btn.SetSensitive(true)
+			uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to remove %s: %v", name, err))
+			return
+		}
+		uh.toastAdder.ShowToast(actionmsg.FeatureRemove(updex.IsDryRun(), name))
+		if updex.IsDryRun() {
+			btn.SetSensitive(true)
+			return
+		}
+		go uh.loadFeatures()
+	})
+}
+
 // onUpdateFeaturesClicked handles the Update button click
 func (uh *UserHome) onUpdateFeaturesClicked(button *gtk.Button) {
 	button.SetSensitive(false)
 	button.SetLabel("Updating...")
 
+	inhibitCookie := uh.toastAdder.Inhibit("Updating system features")
+
 	go func() {
 		ctx, cancel := updex.DefaultContext()
 		defer cancel()
@@ -213,6 +287,7 @@ func (uh *UserHome) onUpdateFeaturesClicked(button *gtk.Button) {
 		err := updex.UpdateFeatures(ctx)
 
 		sgtk.RunOnMainThread(func() {
+			uh.toastAdder.Uninhibit(inhibitCookie)
 			button.SetSensitive(true)
 			button.SetLabel("Update")
 
@@ -222,6 +297,10 @@ func (uh *UserHome) onUpdateFeaturesClicked(button *gtk.Button) {
 			}
 
 			uh.toastAdder.ShowToast(actionmsg.FeatureUpdate(updex.IsDryRun()))
+			if !updex.IsDryRun() {
+				// updex has refreshed systemd-sysext; reload versions and rows
+				go uh.loadFeatures()
+			}
 		})
 	}()
 }
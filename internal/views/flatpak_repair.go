@@ -0,0 +1,149 @@
+package views
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// addFlatpakRepairRows adds the Repair Installation row and its hidden
+// progress expander to the Flatpak maintenance group
+func (uh *UserHome) addFlatpakRepairRows(group *adw.PreferencesGroup) {
+	row := adw.NewActionRow()
+	row.SetTitle("Repair Installation")
+	row.SetSubtitle("Verify installed files and re-download any that are corrupted")
+
+	icon := gtk.NewImageFromIconName("emblem-synchronizing-symbolic")
+	row.AddPrefix(&icon.Widget)
+
+	uh.flatpakRepairBtn = gtk.NewButtonWithLabel("Repair...")
+	uh.flatpakRepairBtn.SetValign(gtk.AlignCenterValue)
+	clickedCb := func(_ gtk.Button) {
+		uh.confirmFlatpakRepair()
+	}
+	uh.flatpakRepairBtn.ConnectClicked(&clickedCb)
+	row.AddSuffix(&uh.flatpakRepairBtn.Widget)
+	group.Add(&row.Widget)
+
+	// Hidden until a repair runs
+	uh.flatpakRepairExpander = adw.NewExpanderRow()
+	uh.flatpakRepairExpander.SetTitle("Repair Progress")
+	uh.flatpakRepairExpander.SetVisible(false)
+	group.Add(&uh.flatpakRepairExpander.Widget)
+}
+
+// confirmFlatpakRepair asks which installation to repair
+func (uh *UserHome) confirmFlatpakRepair() {
+	dialog := adw.NewAlertDialog("Repair Flatpak Installation?",
+		"Every installed object is checked. Corrupted files are removed and downloaded again, which can take a while. Repairing the system installation asks for administrator access.")
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("system", "Repair System")
+	dialog.AddResponse("user", "Repair User")
+	dialog.SetResponseAppearance("user", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("user")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		switch response {
+		case "user":
+			uh.repairFlatpak(true)
+		case "system":
+			uh.repairFlatpak(false)
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(&uh.maintenancePrefsPage.Widget)
+}
+
+// repairFlatpak runs flatpak repair, streaming its output into the
+// Details log of the progress expander
+func (uh *UserHome) repairFlatpak(user bool) {
+	installation := "system"
+	if user {
+		installation = "user"
+	}
+	button := uh.flatpakRepairBtn
+	expander := uh.flatpakRepairExpander
+
+	button.SetSensitive(false)
+	button.SetLabel("Repairing...")
+
+	for _, row := range uh.flatpakRepairRows {
+		expander.Remove(row)
+	}
+
+	expander.SetVisible(true)
+	expander.SetExpanded(true)
+	expander.SetTitle(fmt.Sprintf("Repairing the %s installation", installation))
+	expander.SetSubtitle("Running...")
+
+	activityRow := adw.NewActionRow()
+	activityRow.SetTitle("Progress")
+	activityRow.SetSubtitle("Starting flatpak repair...")
+	spinner := gtk.NewSpinner()
+	spinner.Start()
+	activityRow.AddSuffix(&spinner.Widget)
+	expander.AddRow(&activityRow.Widget)
+
+	logExpander := adw.NewExpanderRow()
+	logExpander.SetTitle("Details")
+	logExpander.SetSubtitle("View output")
+	expander.AddRow(&logExpander.Widget)
+
+	uh.flatpakRepairRows = []*gtk.Widget{&activityRow.Widget, &logExpander.Widget}
+
+	go func() {
+		lineCh := make(chan string)
+
+		var repairErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repairErr = flatpak.Repair(uh.ctx, user, lineCh)
+		}()
+
+		for line := range lineCh {
+			text := line
+			sgtk.RunOnMainThread(func() {
+				lineRow := adw.NewActionRow()
+				lineRow.SetTitle(text)
+				lineRow.SetSubtitle(time.Now().Format("15:04:05"))
+				logExpander.AddRow(&lineRow.Widget)
+				activityRow.SetSubtitle(text)
+			})
+		}
+		wg.Wait()
+
+		sgtk.RunOnMainThread(func() {
+			spinner.Stop()
+			button.SetSensitive(true)
+			button.SetLabel("Repair...")
+			expander.SetTitle(fmt.Sprintf("Repair of the %s installation", installation))
+
+			if repairErr != nil {
+				expander.SetSubtitle(fmt.Sprintf("Failed: %v", repairErr))
+				activityRow.SetSubtitle("Failed")
+				logExpander.SetExpanded(true)
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Flatpak repair failed: %v", repairErr))
+				return
+			}
+			finished := fmt.Sprintf("Finished at %s", time.Now().Format("15:04"))
+			expander.SetSubtitle(finished)
+			activityRow.SetSubtitle(finished)
+			uh.toastAdder.ShowToast(actionmsg.FlatpakRepair(flatpak.IsDryRun(), installation))
+			// Refs repair could not fix are removed
+			if uh.flatpakUserExpander != nil {
+				go uh.loadFlatpakApplications()
+			}
+		})
+	}()
+}
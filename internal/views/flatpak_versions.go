@@ -0,0 +1,189 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// shortCommit trims an OSTree commit checksum for display
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// showFlatpakVersions opens a dialog listing the commits of an installed
+// Flatpak application's ref on its origin remote. Any commit other than the
+// installed one can be deployed, and the application can be held (masked)
+// so a rolled-back version is not immediately updated again.
+func (uh *UserHome) showFlatpakVersions(app flatpak.Application, user bool) {
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle(fmt.Sprintf("%s Versions", app.Name))
+	dialog.SetContentWidth(480)
+	dialog.SetContentHeight(560)
+
+	page := adw.NewPreferencesPage()
+
+	holdGroup := adw.NewPreferencesGroup()
+	holdGroup.SetTitle("Hold Updates")
+	holdGroup.SetDescription("A held application is skipped by Flatpak updates until the hold is released")
+	page.Add(holdGroup)
+
+	holdRow := adw.NewActionRow()
+	holdRow.SetUseMarkup(false)
+	holdRow.SetTitle("Checking hold status...")
+	holdGroup.Add(&holdRow.Widget)
+
+	holdBtn := gtk.NewButtonWithLabel("Hold")
+	holdBtn.SetValign(gtk.AlignCenterValue)
+	holdBtn.SetSensitive(false)
+	holdRow.AddSuffix(&holdBtn.Widget)
+
+	historyGroup := adw.NewPreferencesGroup()
+	historyGroup.SetTitle("Available Versions")
+	historyGroup.SetDescription(fmt.Sprintf("History of %s on %s", app.Ref, app.Origin))
+	page.Add(historyGroup)
+
+	loadingRow := adw.NewActionRow()
+	loadingRow.SetTitle("Loading...")
+	historyGroup.Add(&loadingRow.Widget)
+
+	dialog.Add(page)
+
+	appID := app.ApplicationID
+	held := false
+
+	// refreshHold re-queries the mask list rather than trusting the last
+	// click, so dry-run (which skips `flatpak mask`) leaves the row as-is.
+	refreshHold := func() {
+		masked, err := flatpak.ListMasked(uh.ctx, user)
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				holdRow.SetTitle("Hold status unavailable")
+				holdRow.SetSubtitle(err.Error())
+				return
+			}
+			held = slices.Contains(masked, appID)
+			if held {
+				holdRow.SetTitle("Updates are held")
+				holdBtn.SetLabel("Release")
+			} else {
+				holdRow.SetTitle("Updates are not held")
+				holdBtn.SetLabel("Hold")
+			}
+			holdBtn.SetSensitive(true)
+		})
+	}
+
+	holdCb := func(btn gtk.Button) {
+		btn.SetSensitive(false)
+		hold := !held
+		go func() {
+			if err := flatpak.Mask(uh.ctx, appID, !hold, user); err != nil {
+				sgtk.RunOnMainThread(func() {
+					btn.SetSensitive(true)
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to change hold: %v", err))
+				})
+				return
+			}
+			sgtk.RunOnMainThread(func() {
+				uh.toastAdder.ShowToast(actionmsg.Hold(flatpak.IsDryRun(), hold, appID))
+			})
+			refreshHold()
+		}()
+	}
+	holdBtn.ConnectClicked(&holdCb)
+
+	go refreshHold()
+
+	go func() {
+		commits, err := flatpak.ListCommits(uh.ctx, app, user)
+		installed, installedErr := flatpak.InstalledCommit(uh.ctx, appID, user)
+		if installedErr != nil {
+			installed = ""
+		}
+
+		sgtk.RunOnMainThread(func() {
+			historyGroup.Remove(&loadingRow.Widget)
+
+			if err != nil {
+				row := adw.NewActionRow()
+				row.SetUseMarkup(false)
+				row.SetTitle("Failed to load versions")
+				row.SetSubtitle(err.Error())
+				historyGroup.Add(&row.Widget)
+				return
+			}
+
+			if len(commits) == 0 {
+				row := adw.NewActionRow()
+				row.SetTitle("No version history")
+				row.SetSubtitle("The remote did not report any earlier commits")
+				historyGroup.Add(&row.Widget)
+				return
+			}
+
+			for _, c := range commits {
+				row := adw.NewActionRow()
+				// Commit subjects are free text from the remote
+				row.SetUseMarkup(false)
+				title := c.Subject
+				if title == "" {
+					title = shortCommit(c.Commit)
+				}
+				row.SetTitle(title)
+				row.SetSubtitle(fmt.Sprintf("%s · %s", c.Date, shortCommit(c.Commit)))
+
+				if c.Commit == installed {
+					label := gtk.NewLabel("Installed")
+					label.AddCssClass("dim-label")
+					row.AddSuffix(&label.Widget)
+					historyGroup.Add(&row.Widget)
+					continue
+				}
+
+				installBtn := gtk.NewButtonWithLabel("Install")
+				installBtn.SetValign(gtk.AlignCenterValue)
+				if !user {
+					installBtn.SetTooltipText("Install this version (requires admin)")
+				}
+
+				commit := c.Commit
+				installCb := func(btn gtk.Button) {
+					btn.SetSensitive(false)
+					btn.SetLabel("Installing...")
+					go func() {
+						if err := flatpak.Downgrade(uh.ctx, app.Ref, commit, user); err != nil {
+							sgtk.RunOnMainThread(func() {
+								btn.SetSensitive(true)
+								btn.SetLabel("Install")
+								uh.toastAdder.ShowErrorToast(fmt.Sprintf("Version change failed: %v", err))
+							})
+							return
+						}
+						sgtk.RunOnMainThread(func() {
+							uh.toastAdder.ShowToast(actionmsg.Downgrade(flatpak.IsDryRun(), appID))
+							dialog.Close()
+							go uh.loadFlatpakApplications()
+						})
+					}()
+				}
+				installBtn.ConnectClicked(&installCb)
+
+				row.AddSuffix(&installBtn.Widget)
+				historyGroup.Add(&row.Widget)
+			}
+		})
+	}()
+
+	dialog.Present(&uh.applicationsPrefsPage.Widget)
+}
@@ -0,0 +1,416 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/frostyard/chairlift/internal/systemd"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/gobject"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+const (
+	// logsLimit is how many entries the Logs page shows
+	logsLimit = 200
+	// logsExportLimit is how many entries Export writes
+	logsExportLimit = 10000
+)
+
+// logPriorities are the Priority choices, least verbose first
+var logPriorities = []struct {
+	title    string
+	priority int
+}{
+	{"Errors", systemd.PriorityError},
+	{"Warnings and Errors", systemd.PriorityWarning},
+	{"Notices and Above", systemd.PriorityNotice},
+	{"Everything", 0},
+}
+
+// defaultLogPriority is the index in logPriorities selected at startup
+const defaultLogPriority = 1
+
+// logsView holds the Logs page's filter and entry widgets
+type logsView struct {
+	priorityRow *adw.ComboRow
+	bootRow     *adw.ComboRow
+	bootList    *gtk.StringList
+	unitRow     *adw.EntryRow
+	searchRow   *adw.EntryRow
+
+	// boots are the Boot choices after the current boot, in bootList order;
+	// the last bootList item is "All Boots"
+	boots        []systemd.Boot
+	priorityIdx  uint32
+	bootIdx      uint32
+	fillingBoots bool
+
+	entriesGroup *adw.PreferencesGroup
+	list         *gtk.ListBox
+	rows         []*adw.ActionRow // Newest first; store references for cleanup
+	liveBtn      *gtk.ToggleButton
+	exportBtn    *gtk.Button
+
+	// loadSeq drops results of a load that a newer one has replaced
+	loadSeq    int
+	liveCancel context.CancelFunc // Set while following new entries
+}
+
+// hasLogsPage reports whether the Logs page is shown: journalctl is
+// installed and the journal group is enabled
+func (uh *UserHome) hasLogsPage() bool {
+	return systemd.JournalInstalled() && uh.config.IsGroupEnabled("logs_page", "journal_group")
+}
+
+// buildLogsPage builds the filters group and the entries list
+func (uh *UserHome) buildLogsPage() {
+	page := uh.logsPrefsPage
+	if page == nil {
+		return
+	}
+
+	lv := &logsView{priorityIdx: defaultLogPriority}
+	uh.logs = lv
+
+	filtersGroup := adw.NewPreferencesGroup()
+	filtersGroup.SetTitle("Filters")
+	filtersGroup.SetDescription("Unit and Search apply when you press Enter")
+	page.Add(filtersGroup)
+
+	priorityTitles := make([]string, len(logPriorities))
+	for i, p := range logPriorities {
+		priorityTitles[i] = p.title
+	}
+	lv.priorityRow = adw.NewComboRow()
+	lv.priorityRow.SetTitle("Priority")
+	lv.priorityRow.SetModel(gtk.NewStringList(priorityTitles))
+	lv.priorityRow.SetSelected(defaultLogPriority)
+	priorityCb := func(_ gobject.Object, _ uintptr) {
+		if idx := lv.priorityRow.GetSelected(); idx != lv.priorityIdx {
+			lv.priorityIdx = idx
+			uh.reloadLogs()
+		}
+	}
+	lv.priorityRow.ConnectNotify(&priorityCb)
+	filtersGroup.Add(&lv.priorityRow.Widget)
+
+	lv.bootList = gtk.NewStringList([]string{"Current Boot", "All Boots"})
+	lv.bootRow = adw.NewComboRow()
+	lv.bootRow.SetTitle("Boot")
+	lv.bootRow.SetModel(lv.bootList)
+	bootCb := func(_ gobject.Object, _ uintptr) {
+		if lv.fillingBoots {
+			return
+		}
+		if idx := lv.bootRow.GetSelected(); idx != lv.bootIdx {
+			lv.bootIdx = idx
+			uh.reloadLogs()
+		}
+	}
+	lv.bootRow.ConnectNotify(&bootCb)
+	filtersGroup.Add(&lv.bootRow.Widget)
+
+	lv.unitRow = adw.NewEntryRow()
+	lv.unitRow.SetTitle("Unit, e.g. bootc-fetch-apply-updates.service")
+	lv.unitRow.SetShowApplyButton(true)
+	unitCb := func(_ adw.EntryRow) {
+		uh.reloadLogs()
+	}
+	lv.unitRow.ConnectApply(&unitCb)
+	filtersGroup.Add(&lv.unitRow.Widget)
+
+	lv.searchRow = adw.NewEntryRow()
+	lv.searchRow.SetTitle("Search Messages")
+	lv.searchRow.SetShowApplyButton(true)
+	searchCb := func(_ adw.EntryRow) {
+		uh.reloadLogs()
+	}
+	lv.searchRow.ConnectApply(&searchCb)
+	filtersGroup.Add(&lv.searchRow.Widget)
+
+	lv.entriesGroup = adw.NewPreferencesGroup()
+	lv.entriesGroup.SetTitle("Entries")
+	lv.entriesGroup.SetDescription("Loading...")
+
+	lv.liveBtn = gtk.NewToggleButton()
+	lv.liveBtn.SetIconName("media-playback-start-symbolic")
+	lv.liveBtn.SetValign(gtk.AlignCenterValue)
+	lv.liveBtn.AddCssClass("flat")
+	lv.liveBtn.SetTooltipText("Show New Entries as They Arrive")
+	liveCb := func(_ gtk.ToggleButton) {
+		uh.setLogsLive(lv.liveBtn.GetActive())
+	}
+	lv.liveBtn.ConnectToggled(&liveCb)
+
+	lv.exportBtn = gtk.NewButtonFromIconName("document-save-symbolic")
+	lv.exportBtn.SetValign(gtk.AlignCenterValue)
+	lv.exportBtn.AddCssClass("flat")
+	lv.exportBtn.SetTooltipText("Export Matching Entries")
+	exportCb := func(_ gtk.Button) {
+		uh.chooseLogsExportFile()
+	}
+	lv.exportBtn.ConnectClicked(&exportCb)
+
+	refreshBtn := gtk.NewButtonFromIconName("view-refresh-symbolic")
+	refreshBtn.SetValign(gtk.AlignCenterValue)
+	refreshBtn.AddCssClass("flat")
+	refreshBtn.SetTooltipText("Refresh")
+	refreshCb := func(_ gtk.Button) {
+		uh.reloadLogs()
+	}
+	refreshBtn.ConnectClicked(&refreshCb)
+
+	headerBox := gtk.NewBox(gtk.OrientationHorizontalValue, 6)
+	headerBox.Append(&lv.liveBtn.Widget)
+	headerBox.Append(&lv.exportBtn.Widget)
+	headerBox.Append(&refreshBtn.Widget)
+	lv.entriesGroup.SetHeaderSuffix(&headerBox.Widget)
+
+	lv.list = gtk.NewListBox()
+	lv.list.AddCssClass("boxed-list")
+	lv.list.SetSelectionMode(gtk.SelectionNoneValue)
+	lv.entriesGroup.Add(&lv.list.Widget)
+	page.Add(lv.entriesGroup)
+
+	go uh.loadBoots()
+	uh.reloadLogs()
+}
+
+// logsQuery returns the journal query for the current filters
+func (uh *UserHome) logsQuery() systemd.JournalQuery {
+	lv := uh.logs
+	q := systemd.JournalQuery{
+		Unit:  strings.TrimSpace(lv.unitRow.GetText()),
+		Grep:  strings.TrimSpace(lv.searchRow.GetText()),
+		Lines: logsLimit,
+	}
+	if int(lv.priorityIdx) < len(logPriorities) {
+		q.Priority = logPriorities[lv.priorityIdx].priority
+	}
+	switch {
+	case lv.bootIdx == 0:
+		q.Boot = "0"
+	case int(lv.bootIdx) <= len(lv.boots):
+		q.Boot = lv.boots[lv.bootIdx-1].ID
+	}
+	return q
+}
+
+// loadBoots fills the Boot choices with the boots the journal remembers
+func (uh *UserHome) loadBoots() {
+	boots, err := systemd.ListBoots(uh.ctx)
+	if err != nil {
+		return // Current Boot and All Boots still work
+	}
+
+	sgtk.RunOnMainThread(func() {
+		lv := uh.logs
+		var earlier []systemd.Boot
+		var titles []string
+		for _, b := range boots {
+			if b.Index == 0 {
+				continue
+			}
+			earlier = append(earlier, b)
+			titles = append(titles, fmt.Sprintf("Boot of %s", b.First.Local().Format("Mon 2006-01-02 15:04")))
+		}
+
+		// Replacing the items resets the selection; keep Current Boot
+		lv.fillingBoots = true
+		lv.bootList.Splice(1, lv.bootList.GetNItems()-2, titles)
+		lv.bootRow.SetSelected(0)
+		lv.fillingBoots = false
+		lv.boots = earlier
+		lv.bootIdx = 0
+	})
+}
+
+// reloadLogs reloads the entries for the current filters and restarts
+// following new entries if that is on
+func (uh *UserHome) reloadLogs() {
+	lv := uh.logs
+	lv.loadSeq++
+	seq := lv.loadSeq
+	q := uh.logsQuery()
+
+	// Only the current boot gets new entries
+	currentBoot := q.Boot == "0"
+	lv.liveBtn.SetSensitive(currentBoot)
+	if !currentBoot && lv.liveBtn.GetActive() {
+		lv.liveBtn.SetActive(false) // stops following through the toggled handler
+	}
+
+	lv.entriesGroup.SetDescription("Loading...")
+
+	go func() {
+		entries, err := systemd.Query(uh.ctx, q)
+
+		sgtk.RunOnMainThread(func() {
+			if seq != lv.loadSeq {
+				return // Filters changed while loading
+			}
+
+			lv.list.RemoveAll()
+			lv.rows = nil
+
+			if err != nil {
+				lv.entriesGroup.SetDescription(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			switch {
+			case len(entries) == 0:
+				lv.entriesGroup.SetDescription("No matching entries. Without the systemd-journal group, other users' and system entries may be hidden.")
+			case len(entries) == logsLimit:
+				lv.entriesGroup.SetDescription(fmt.Sprintf("The newest %d matching entries", logsLimit))
+			default:
+				lv.entriesGroup.SetDescription(fmt.Sprintf("%d matching entries", len(entries)))
+			}
+
+			// Newest first, like the audit log
+			for i := len(entries) - 1; i >= 0; i-- {
+				row := newJournalRow(entries[i])
+				lv.list.Append(&row.Widget)
+				lv.rows = append(lv.rows, row)
+			}
+
+			if lv.liveBtn.GetActive() {
+				uh.setLogsLive(true)
+			}
+		})
+	}()
+}
+
+// setLogsLive starts or stops following new entries for the current
+// filters. Each new entry goes to the top of the list, and the oldest row
+// is dropped past logsLimit.
+func (uh *UserHome) setLogsLive(live bool) {
+	lv := uh.logs
+	if lv.liveCancel != nil {
+		lv.liveCancel()
+		lv.liveCancel = nil
+	}
+	if !live {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(uh.ctx)
+	lv.liveCancel = cancel
+	q := uh.logsQuery()
+
+	go func() {
+		err := systemd.Follow(ctx, q, func(e systemd.JournalEntry) {
+			sgtk.RunOnMainThread(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				row := newJournalRow(e)
+				lv.list.Prepend(&row.Widget)
+				lv.rows = append([]*adw.ActionRow{row}, lv.rows...)
+				if len(lv.rows) > logsLimit {
+					oldest := lv.rows[len(lv.rows)-1]
+					lv.list.Remove(&oldest.Widget)
+					lv.rows = lv.rows[:len(lv.rows)-1]
+				}
+			})
+		})
+		if err != nil {
+			sgtk.RunOnMainThread(func() {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Stopped following the journal: %v", err))
+				lv.liveBtn.SetActive(false)
+			})
+		}
+	}()
+}
+
+// chooseLogsExportFile asks where to save the entries matching the current
+// filters, then writes them
+func (uh *UserHome) chooseLogsExportFile() {
+	dialog := gtk.NewFileDialog()
+	dialog.SetTitle("Export Log")
+	dialog.SetAcceptLabel("Export")
+	dialog.SetInitialName("chairlift-journal.log")
+
+	root := uh.logsPrefsPage.GetRoot()
+	if root == nil {
+		return
+	}
+	parent := gtk.WindowNewFromInternalPtr(root.Ptr)
+
+	// Cancelling the chooser returns an error from the finish call; there
+	// is nothing to report in that case.
+	saveCb := gio.AsyncReadyCallback(func(_, result, _ uintptr) {
+		file, err := dialog.SaveFinish(&gio.AsyncResultBase{Ptr: result})
+		if err != nil || file == nil {
+			return
+		}
+		if path := file.GetPath(); path != "" {
+			uh.exportLogs(path)
+		}
+	})
+	dialog.Save(parent, gio.NewCancellable(), &saveCb, 0)
+}
+
+// exportLogs writes up to logsExportLimit entries matching the current
+// filters to path. Export only reads the journal, so it runs under dry-run
+// too.
+func (uh *UserHome) exportLogs(path string) {
+	lv := uh.logs
+	q := uh.logsQuery()
+	q.Lines = logsExportLimit
+	lv.exportBtn.SetSensitive(false)
+
+	go func() {
+		err := systemd.Export(uh.ctx, q, path)
+
+		sgtk.RunOnMainThread(func() {
+			lv.exportBtn.SetSensitive(true)
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Log export failed: %v", err))
+				return
+			}
+			uh.toastAdder.ShowToastWithAction(fmt.Sprintf("Log saved to %s", path), "Open", func() {
+				uh.openURL(path)
+			})
+		})
+	}()
+}
+
+// newJournalRow returns a row for one journal entry, marked when it is a
+// warning or an error
+func newJournalRow(e systemd.JournalEntry) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(e.Message)
+	row.SetTitleLines(3)
+	row.SetSubtitle(journalSubtitle(e))
+
+	switch {
+	case e.Priority <= systemd.PriorityError:
+		icon := gtk.NewImageFromIconName("dialog-error-symbolic")
+		icon.AddCssClass("error")
+		row.AddPrefix(&icon.Widget)
+	case e.Priority == systemd.PriorityWarning:
+		icon := gtk.NewImageFromIconName("dialog-warning-symbolic")
+		icon.AddCssClass("warning")
+		row.AddPrefix(&icon.Widget)
+	}
+	return row
+}
+
+// journalSubtitle describes when and by what an entry was logged
+func journalSubtitle(e systemd.JournalEntry) string {
+	subtitle := e.Time.Local().Format("2006-01-02 15:04:05")
+	if e.Identifier != "" {
+		subtitle = fmt.Sprintf("%s · %s", subtitle, e.Identifier)
+	}
+	if e.Unit != "" {
+		subtitle = fmt.Sprintf("%s · %s", subtitle, e.Unit)
+	}
+	return subtitle
+}
@@ -83,7 +83,11 @@ func (uh *UserHome) buildMaintenancePage() {
 		button.AddCssClass("suggested-action")
 
 		clickedCb := func(btn gtk.Button) {
-			uh.onBrewCleanupClicked(button)
+			uh.confirmDestructive(&uh.maintenancePrefsPage.Widget,
+				"Clean Up Homebrew?",
+				"Old versions of installed packages and cached downloads will be deleted. You will not be able to switch back to those versions without downloading them again.",
+				"Clean Up",
+				func() { uh.onBrewCleanupClicked(button) })
 		}
 		button.ConnectClicked(&clickedCb)
 
@@ -108,7 +112,7 @@ func (uh *UserHome) buildMaintenancePage() {
 	// Flatpak Cleanup group
 	if uh.config.IsGroupEnabled("maintenance_page", "maintenance_flatpak_group") {
 		group := adw.NewPreferencesGroup()
-		group.SetTitle("Flatpak Cleanup")
+		group.SetTitle("Flatpak Maintenance")
 		group.SetDescription("Checking Flatpak availability...")
 		uh.maintenanceFlatpakGroup = group
 
@@ -124,13 +128,19 @@ func (uh *UserHome) buildMaintenancePage() {
 		button.AddCssClass("suggested-action")
 
 		clickedCb := func(btn gtk.Button) {
-			uh.onFlatpakCleanupClicked(button)
+			uh.confirmDestructive(&uh.maintenancePrefsPage.Widget,
+				"Remove Unused Runtimes?",
+				"Runtimes and extensions that no installed application depends on will be uninstalled.",
+				"Remove",
+				func() { uh.onFlatpakCleanupClicked(button) })
 		}
 		button.ConnectClicked(&clickedCb)
 
 		row.AddSuffix(&button.Widget)
 		group.Add(&row.Widget)
 
+		uh.addFlatpakRepairRows(group)
+
 		page.Add(group)
 
 		go func() {
@@ -140,12 +150,22 @@ func (uh *UserHome) buildMaintenancePage() {
 				})
 			} else {
 				sgtk.RunOnMainThread(func() {
-					uh.maintenanceFlatpakGroup.SetDescription("Remove unused Flatpak runtimes and extensions")
+					uh.maintenanceFlatpakGroup.SetDescription("Remove unused runtimes and repair damaged installations")
 				})
 			}
 		}()
 	}
 
+	// Manifest group
+	if uh.config.IsGroupEnabled("maintenance_page", "maintenance_manifest_group") {
+		uh.buildManifestGroup(page)
+	}
+
+	// Activity log group
+	if uh.config.IsGroupEnabled("maintenance_page", "maintenance_audit_group") {
+		uh.buildAuditLogGroup(page)
+	}
+
 	// Optimization group
 	if uh.config.IsGroupEnabled("maintenance_page", "maintenance_optimization_group") {
 		group := adw.NewPreferencesGroup()
@@ -168,7 +188,7 @@ func (uh *UserHome) onBrewCleanupClicked(button *gtk.Button) {
 	button.SetLabel("Cleaning...")
 
 	go func() {
-		output, err := homebrew.Cleanup()
+		output, err := homebrew.Cleanup(uh.ctx)
 
 		sgtk.RunOnMainThread(func() {
 			button.SetSensitive(true)
@@ -190,7 +210,7 @@ func (uh *UserHome) onFlatpakCleanupClicked(button *gtk.Button) {
 	button.SetLabel("Cleaning...")
 
 	go func() {
-		output, err := flatpak.UninstallUnused()
+		output, err := flatpak.UninstallUnused(uh.ctx)
 
 		sgtk.RunOnMainThread(func() {
 			button.SetSensitive(true)
@@ -211,14 +231,21 @@ func (uh *UserHome) onBrewBundleDumpClicked() {
 	go func() {
 		homeDir, _ := os.UserHomeDir()
 		path := homeDir + "/Brewfile"
-		if err := homebrew.BundleDump(path, true); err != nil {
+		if err := homebrew.BundleDump(uh.ctx, path, true); err != nil {
 			sgtk.RunOnMainThread(func() {
 				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Bundle dump failed: %v", err))
 			})
 			return
 		}
 		sgtk.RunOnMainThread(func() {
-			uh.toastAdder.ShowToast(actionmsg.BundleDump(homebrew.IsDryRun(), path))
+			decision := actionmsg.BundleDumpSaved(homebrew.IsDryRun(), path)
+			if !decision.OfferOpen {
+				uh.toastAdder.ShowToast(decision.Toast)
+				return
+			}
+			uh.toastAdder.ShowToastWithAction(decision.Toast, "Open", func() {
+				uh.openURL(path)
+			})
 		})
 	}()
 }
@@ -0,0 +1,254 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/manifest"
+	"github.com/frostyard/chairlift/internal/updateall"
+	"github.com/frostyard/chairlift/internal/updex"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// manifestSources checks which package managers are present. It blocks on
+// the availability probes, so call it off the main thread.
+func manifestSources() manifest.Sources {
+	return manifest.Sources{
+		Flatpak:  flatpak.IsInstalledCached(),
+		Homebrew: homebrew.IsInstalledCached(),
+		Features: updex.IsInstalledCached(),
+	}
+}
+
+// buildManifestGroup adds the Export and Import rows to the Maintenance page
+func (uh *UserHome) buildManifestGroup(page *adw.PreferencesPage) {
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("System Manifest")
+	group.SetDescription("Reproduce this machine's applications and features on another one")
+
+	exportRow := adw.NewActionRow()
+	exportRow.SetTitle("Export Manifest")
+	exportRow.SetSubtitle("Save Flatpak applications, the Brewfile and enabled features to one file")
+	exportIcon := gtk.NewImageFromIconName("document-save-symbolic")
+	exportRow.AddPrefix(&exportIcon.Widget)
+
+	exportBtn := gtk.NewButtonWithLabel("Export...")
+	exportBtn.SetValign(gtk.AlignCenterValue)
+	exportClickedCb := func(_ gtk.Button) {
+		uh.chooseManifestFile(true, func(path string) {
+			uh.exportManifest(path, exportBtn)
+		})
+	}
+	exportBtn.ConnectClicked(&exportClickedCb)
+	exportRow.AddSuffix(&exportBtn.Widget)
+	group.Add(&exportRow.Widget)
+
+	importRow := adw.NewActionRow()
+	importRow.SetTitle("Import Manifest")
+	importRow.SetSubtitle("Install everything a manifest lists that is missing here")
+	importIcon := gtk.NewImageFromIconName("document-open-symbolic")
+	importRow.AddPrefix(&importIcon.Widget)
+
+	uh.manifestImportBtn = gtk.NewButtonWithLabel("Import...")
+	uh.manifestImportBtn.SetValign(gtk.AlignCenterValue)
+	uh.manifestImportBtn.AddCssClass("suggested-action")
+	importClickedCb := func(_ gtk.Button) {
+		uh.chooseManifestFile(false, uh.confirmManifestImport)
+	}
+	uh.manifestImportBtn.ConnectClicked(&importClickedCb)
+	importRow.AddSuffix(&uh.manifestImportBtn.Widget)
+	group.Add(&importRow.Widget)
+
+	// Hidden until an import runs; shows one row per step
+	uh.manifestExpander = adw.NewExpanderRow()
+	uh.manifestExpander.SetTitle("Import Progress")
+	uh.manifestExpander.SetVisible(false)
+	group.Add(&uh.manifestExpander.Widget)
+
+	page.Add(group)
+}
+
+// chooseManifestFile opens a save (export) or open (import) chooser and
+// calls onPicked with the chosen path
+func (uh *UserHome) chooseManifestFile(save bool, onPicked func(path string)) {
+	dialog := gtk.NewFileDialog()
+
+	filter := gtk.NewFileFilter()
+	filter.SetName("ChairLift manifests")
+	filter.AddPattern("*.json")
+	dialog.SetDefaultFilter(filter)
+
+	root := uh.maintenancePrefsPage.GetRoot()
+	if root == nil {
+		return
+	}
+	parent := gtk.WindowNewFromInternalPtr(root.Ptr)
+
+	// Cancelling the chooser returns an error from the finish call; there
+	// is nothing to report in that case.
+	if save {
+		dialog.SetTitle("Export Manifest")
+		dialog.SetAcceptLabel("Export")
+		dialog.SetInitialName(manifest.DefaultName)
+		saveCb := gio.AsyncReadyCallback(func(_, result, _ uintptr) {
+			file, err := dialog.SaveFinish(&gio.AsyncResultBase{Ptr: result})
+			if err != nil || file == nil {
+				return
+			}
+			if path := file.GetPath(); path != "" {
+				onPicked(path)
+			}
+		})
+		dialog.Save(parent, gio.NewCancellable(), &saveCb, 0)
+		return
+	}
+
+	dialog.SetTitle("Import Manifest")
+	dialog.SetAcceptLabel("Import")
+	openCb := gio.AsyncReadyCallback(func(_, result, _ uintptr) {
+		file, err := dialog.OpenFinish(&gio.AsyncResultBase{Ptr: result})
+		if err != nil || file == nil {
+			return
+		}
+		if path := file.GetPath(); path != "" {
+			onPicked(path)
+		}
+	})
+	dialog.Open(parent, gio.NewCancellable(), &openCb, 0)
+}
+
+// exportManifest writes the manifest to path. Export only reads package
+// state, so it runs under dry-run too.
+func (uh *UserHome) exportManifest(path string, button *gtk.Button) {
+	button.SetSensitive(false)
+	button.SetLabel("Exporting...")
+
+	go func() {
+		m, err := manifest.Export(uh.ctx, manifestSources())
+		if err == nil {
+			err = manifest.Write(m, path)
+		}
+
+		sgtk.RunOnMainThread(func() {
+			button.SetSensitive(true)
+			button.SetLabel("Export...")
+
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Manifest export failed: %v", err))
+				return
+			}
+			uh.toastAdder.ShowToastWithAction(fmt.Sprintf("Manifest saved to %s", path), "Open", func() {
+				uh.openURL(path)
+			})
+		})
+	}()
+}
+
+// confirmManifestImport reads the manifest at path and asks before
+// applying it
+func (uh *UserHome) confirmManifestImport(path string) {
+	m, err := manifest.Read(path)
+	if err != nil {
+		uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not read manifest: %v", err))
+		return
+	}
+
+	dialog := adw.NewAlertDialog("Import Manifest?",
+		fmt.Sprintf("This manifest lists %s. Anything missing here will be installed or enabled; nothing is removed.", m.Summary()))
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("import", "Import")
+	dialog.SetResponseAppearance("import", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("import")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response == "import" {
+			uh.importManifest(m)
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(&uh.maintenancePrefsPage.Widget)
+}
+
+// importManifest applies m one step per source, reporting into the
+// progress expander, then reloads the package lists the import touched
+func (uh *UserHome) importManifest(m *manifest.Manifest) {
+	button := uh.manifestImportBtn
+	expander := uh.manifestExpander
+
+	button.SetSensitive(false)
+	button.SetLabel("Importing...")
+	expander.SetVisible(true)
+	expander.SetSubtitle("Checking sources...")
+
+	go func() {
+		steps := manifest.Steps(m, manifestSources())
+
+		sgtk.RunOnMainThread(func() {
+			for _, row := range uh.manifestRows {
+				expander.Remove(&row.Widget)
+			}
+			uh.manifestRows = nil
+
+			if len(steps) == 0 {
+				button.SetSensitive(true)
+				button.SetLabel("Import...")
+				expander.SetSubtitle("The manifest is empty")
+				return
+			}
+
+			reporter := addStepRows(expander, steps)
+			uh.manifestRows = reporter.rows
+			expander.SetExpanded(true)
+			expander.SetSubtitle("Importing...")
+
+			inhibitCookie := uh.toastAdder.Inhibit("Importing a system manifest")
+
+			go func() {
+				ctx, cancel := updateall.Context(uh.ctx)
+				defer cancel()
+
+				results := updateall.Run(ctx, steps, reporter)
+				failed := updateall.Failed(results)
+				cancelled := errors.Is(ctx.Err(), context.Canceled)
+
+				if uh.flatpakUserExpander != nil {
+					go uh.loadFlatpakApplications()
+				}
+				if uh.formulaeExpander != nil {
+					go uh.loadHomebrewPackages()
+				}
+
+				sgtk.RunOnMainThread(func() {
+					uh.toastAdder.Uninhibit(inhibitCookie)
+					button.SetSensitive(true)
+					button.SetLabel("Import...")
+
+					if cancelled {
+						expander.SetSubtitle("Cancelled")
+						return
+					}
+					if len(failed) > 0 {
+						msg := fmt.Sprintf("%s failed", strings.Join(failed, ", "))
+						expander.SetSubtitle(msg)
+						uh.toastAdder.ShowErrorToast(fmt.Sprintf("Manifest import: %s", msg))
+						return
+					}
+					expander.SetSubtitle(fmt.Sprintf("Finished at %s", time.Now().Format("15:04")))
+					uh.toastAdder.ShowToast(actionmsg.ManifestImport(IsDryRun()))
+				})
+			}()
+		})
+	}()
+}
@@ -0,0 +1,80 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/network"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// CheckNetwork re-checks connectivity now instead of at the next poll. The
+// offline banner's Retry button calls it.
+func (uh *UserHome) CheckNetwork() {
+	go func() {
+		online := network.Check(uh.ctx)
+		sgtk.RunOnMainThread(func() {
+			uh.onNetworkChanged(online)
+		})
+	}()
+}
+
+// networkWidgets returns the controls that start a search, install or
+// update, all of which need the network
+func (uh *UserHome) networkWidgets() []*gtk.Widget {
+	var widgets []*gtk.Widget
+	if uh.searchEntry != nil {
+		widgets = append(widgets, &uh.searchEntry.Widget)
+	}
+	for _, btn := range uh.bundleInstallBtns {
+		widgets = append(widgets, &btn.Widget)
+	}
+	for _, btn := range []*gtk.Button{uh.updateAllBtn, uh.bootcStageBtn, uh.manifestImportBtn} {
+		if btn != nil {
+			widgets = append(widgets, &btn.Widget)
+		}
+	}
+	for _, dg := range uh.devtoolsGroups {
+		widgets = append(widgets, &dg.upgradeAllBtn.Widget)
+	}
+	return widgets
+}
+
+// onNetworkChanged shows the offline banner and disables the network
+// controls while offline. A control that is already insensitive is busy
+// with a running operation, which resets it when done, so only the ones
+// this disabled are re-enabled. Must run on the main thread.
+func (uh *UserHome) onNetworkChanged(online bool) {
+	uh.toastAdder.SetOffline(!online)
+
+	if online {
+		for _, widget := range uh.offlineDisabled {
+			widget.SetSensitive(true)
+		}
+		uh.offlineDisabled = nil
+		return
+	}
+
+	if uh.offlineDisabled != nil {
+		return // Already offline
+	}
+	uh.offlineDisabled = []*gtk.Widget{}
+	for _, widget := range uh.networkWidgets() {
+		if widget.GetSensitive() {
+			widget.SetSensitive(false)
+			uh.offlineDisabled = append(uh.offlineDisabled, widget)
+		}
+	}
+}
+
+// errorText describes a failed load or search. Offline, the command's own
+// error (a DNS or TLS failure deep inside brew or flatpak) says little, so
+// the cause is named instead.
+func errorText(err error) string {
+	if !network.IsOnline() {
+		return "Offline — connect to a network and refresh"
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
@@ -0,0 +1,51 @@
+package views
+
+import (
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/pkgcache"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// packageLists caches the list commands behind the Applications and
+// Updates pages. Loaders show the cached list at once and refresh it in
+// the background once it is older than pkgcache.DefaultTTL; any install,
+// upgrade or removal invalidates every list.
+type packageLists struct {
+	formulae       *pkgcache.Cache[[]homebrew.Package]
+	casks          *pkgcache.Cache[[]homebrew.Package]
+	outdated       *pkgcache.Cache[[]homebrew.Package]
+	flatpakUser    *pkgcache.Cache[[]flatpak.Application]
+	flatpakSystem  *pkgcache.Cache[[]flatpak.Application]
+	flatpakUpdates *pkgcache.Cache[[]flatpak.UpdateInfo]
+}
+
+func newPackageLists() *packageLists {
+	return &packageLists{
+		formulae:       pkgcache.New(pkgcache.DefaultTTL, homebrew.ListInstalledFormulae),
+		casks:          pkgcache.New(pkgcache.DefaultTTL, homebrew.ListInstalledCasks),
+		outdated:       pkgcache.New(pkgcache.DefaultTTL, homebrew.ListOutdated),
+		flatpakUser:    pkgcache.New(pkgcache.DefaultTTL, flatpak.ListUserApplications),
+		flatpakSystem:  pkgcache.New(pkgcache.DefaultTTL, flatpak.ListSystemApplications),
+		flatpakUpdates: pkgcache.New(pkgcache.DefaultTTL, flatpak.ListAllUpdates),
+	}
+}
+
+// addRefreshButton puts a Refresh button in group's header that drops
+// the given cached lists and runs reload in a goroutine
+func (uh *UserHome) addRefreshButton(group *adw.PreferencesGroup, reload func(), caches ...interface{ Invalidate() }) {
+	refreshBtn := gtk.NewButtonFromIconName("view-refresh-symbolic")
+	refreshBtn.SetValign(gtk.AlignCenterValue)
+	refreshBtn.AddCssClass("flat")
+	refreshBtn.SetTooltipText("Refresh")
+	clickedCb := func(_ gtk.Button) {
+		for _, c := range caches {
+			c.Invalidate()
+		}
+		go reload()
+	}
+	refreshBtn.ConnectClicked(&clickedCb)
+	group.SetHeaderSuffix(&refreshBtn.Widget)
+}
@@ -0,0 +1,79 @@
+package views
+
+import (
+	"github.com/frostyard/chairlift/internal/preview"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// planActionIcons marks each change row with what happens to the package
+var planActionIcons = map[preview.Action]string{
+	preview.ActionInstall: "list-add-symbolic",
+	preview.ActionUpgrade: "software-update-available-symbolic",
+	preview.ActionRemove:  "list-remove-symbolic",
+}
+
+// confirmPlan shows what an operation would do — its package changes,
+// estimated download and commands — and runs onConfirm if the user goes
+// ahead or onCancel otherwise. Under dry-run the confirmed run is still a
+// preview; the body says so.
+func (uh *UserHome) confirmPlan(parent *gtk.Widget, heading, confirmLabel string, plan preview.Plan, dryRun bool, onConfirm, onCancel func()) {
+	body := plan.Summary()
+	if dryRun {
+		body += "\n\nDry-run mode is on: nothing will be changed."
+	}
+
+	dialog := adw.NewAlertDialog(heading, body)
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("confirm", confirmLabel)
+	dialog.SetResponseAppearance("confirm", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("confirm")
+	dialog.SetCloseResponse("cancel")
+
+	list := gtk.NewListBox()
+	list.SetSelectionMode(gtk.SelectionNoneValue)
+	list.AddCssClass("boxed-list")
+
+	for _, change := range plan.Changes {
+		row := adw.NewActionRow()
+		row.SetTitle(change.Name)
+		row.SetSubtitle(change.Describe())
+		if iconName, ok := planActionIcons[change.Action]; ok {
+			icon := gtk.NewImageFromIconName(iconName)
+			row.AddPrefix(&icon.Widget)
+		}
+		list.Append(&row.Widget)
+	}
+
+	if len(plan.Commands) > 0 {
+		commandsExpander := adw.NewExpanderRow()
+		commandsExpander.SetTitle("Commands")
+		for _, command := range plan.Commands {
+			commandRow := adw.NewActionRow()
+			commandRow.SetTitle(command)
+			commandRow.AddCssClass("monospace")
+			commandsExpander.AddRow(&commandRow.Widget)
+		}
+		list.Append(&commandsExpander.Widget)
+	}
+
+	scrolled := gtk.NewScrolledWindow()
+	scrolled.SetPolicy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue)
+	scrolled.SetPropagateNaturalHeight(true)
+	scrolled.SetMaxContentHeight(320)
+	scrolled.SetChild(&list.Widget)
+	dialog.SetExtraChild(&scrolled.Widget)
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response == "confirm" {
+			onConfirm()
+			return
+		}
+		if onCancel != nil {
+			onCancel()
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(parent)
+}
@@ -0,0 +1,115 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/bootc"
+	"github.com/frostyard/chairlift/internal/power"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// restartPending reports whether status has a deployment that takes
+// effect at the next boot
+func restartPending(status *bootc.Status) bool {
+	return status != nil && (status.Status.Staged != nil || status.Status.RollbackQueued)
+}
+
+// updateRestartBanner shows the window's restart banner while a staged
+// update or queued rollback waits for a restart, and hides it otherwise.
+// Must run on the main thread.
+func (uh *UserHome) updateRestartBanner(status *bootc.Status) {
+	if !restartPending(status) {
+		uh.toastAdder.SetRestartBanner("")
+		return
+	}
+	if !uh.restartAt.IsZero() {
+		uh.toastAdder.SetRestartBanner(fmt.Sprintf("The system update applies at the restart scheduled for %s", uh.restartAt.Format("15:04")))
+		return
+	}
+	uh.toastAdder.SetRestartBanner("Restart to finish applying the system update")
+}
+
+// offerRestart asks whether to restart now, tonight or later after a
+// change that applies at the next boot. Must run on the main thread.
+func (uh *UserHome) offerRestart(parent *gtk.Widget, heading string) {
+	tonight := power.Tonight(time.Now())
+
+	dialog := adw.NewAlertDialog(heading,
+		"The change applies the next time the computer starts. Save your work in other applications before restarting.")
+	dialog.AddResponse("later", "Later")
+	dialog.AddResponse("tonight", fmt.Sprintf("Restart at %s", tonight.Format("15:04")))
+	dialog.AddResponse("now", "Restart Now")
+	dialog.SetResponseAppearance("now", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("later")
+	dialog.SetCloseResponse("later")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		switch response {
+		case "now":
+			uh.rebootNow()
+		case "tonight":
+			uh.scheduleRestart(tonight)
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(parent)
+}
+
+// RestartNow confirms and restarts the computer. The window's restart
+// banner calls it.
+func (uh *UserHome) RestartNow() {
+	dialog := adw.NewAlertDialog("Restart Now?",
+		"Save your work in other applications first. Anything unsaved is lost.")
+	dialog.AddResponse("cancel", "Cancel")
+	dialog.AddResponse("restart", "Restart")
+	dialog.SetResponseAppearance("restart", adw.ResponseSuggestedValue)
+	dialog.SetDefaultResponse("cancel")
+	dialog.SetCloseResponse("cancel")
+
+	responseCb := func(_ adw.AlertDialog, response string) {
+		if response == "restart" {
+			uh.rebootNow()
+		}
+	}
+	dialog.ConnectResponse(&responseCb)
+	dialog.Present(&uh.systemPrefsPage.Widget)
+}
+
+// rebootNow asks logind to restart the computer
+func (uh *UserHome) rebootNow() {
+	go func() {
+		err := power.Reboot(uh.ctx)
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Restart failed: %v", err))
+				return
+			}
+			uh.toastAdder.ShowToast(actionmsg.Restart(power.IsDryRun()))
+		})
+	}()
+}
+
+// scheduleRestart asks logind to restart the computer at at and names the
+// time in the restart banner
+func (uh *UserHome) scheduleRestart(at time.Time) {
+	go func() {
+		err := power.ScheduleReboot(uh.ctx, at)
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not schedule a restart: %v", err))
+				return
+			}
+			if !power.IsDryRun() {
+				uh.restartAt = at
+				uh.toastAdder.SetRestartBanner(fmt.Sprintf("The system update applies at the restart scheduled for %s", at.Format("15:04")))
+			}
+			uh.toastAdder.ShowToast(actionmsg.RestartScheduled(power.IsDryRun(), at.Format("15:04")))
+		})
+	}()
+}
@@ -0,0 +1,203 @@
+package views
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/systemd"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// serviceJournalLines is how many journal lines the details dialog shows
+const serviceJournalLines = 50
+
+// showServiceDetails opens a dialog with a service's state, buttons to
+// start, stop and restart it, a switch for starting it at boot, and its
+// most recent journal lines. Every action re-reads the service's state
+// rather than trusting the click, so dry-run leaves the dialog as it was.
+func (uh *UserHome) showServiceDetails(sg *servicesGroup, name string) {
+	dialog := adw.NewPreferencesDialog()
+	dialog.SetTitle(name)
+	dialog.SetContentWidth(560)
+	dialog.SetContentHeight(620)
+
+	page := adw.NewPreferencesPage()
+
+	statusGroup := adw.NewPreferencesGroup()
+	statusGroup.SetTitle("Status")
+	page.Add(statusGroup)
+
+	stateRow := adw.NewActionRow()
+	stateRow.SetTitle("Loading...")
+	statusGroup.Add(&stateRow.Widget)
+
+	startBtn := gtk.NewButtonWithLabel("Start")
+	startBtn.SetValign(gtk.AlignCenterValue)
+	startBtn.SetSensitive(false)
+	stateRow.AddSuffix(&startBtn.Widget)
+
+	stopBtn := gtk.NewButtonWithLabel("Stop")
+	stopBtn.SetValign(gtk.AlignCenterValue)
+	stopBtn.SetSensitive(false)
+	stateRow.AddSuffix(&stopBtn.Widget)
+
+	restartBtn := gtk.NewButtonWithLabel("Restart")
+	restartBtn.SetValign(gtk.AlignCenterValue)
+	restartBtn.SetSensitive(false)
+	stateRow.AddSuffix(&restartBtn.Widget)
+
+	bootRow := adw.NewActionRow()
+	if sg.user {
+		bootRow.SetTitle("Start at Login")
+	} else {
+		bootRow.SetTitle("Start at Boot")
+	}
+	bootSwitch := gtk.NewSwitch()
+	bootSwitch.SetValign(gtk.AlignCenterValue)
+	bootSwitch.SetSensitive(false)
+	bootRow.AddSuffix(&bootSwitch.Widget)
+	bootRow.SetActivatableWidget(&bootSwitch.Widget)
+	statusGroup.Add(&bootRow.Widget)
+
+	journalGroup := adw.NewPreferencesGroup()
+	journalGroup.SetTitle("Recent Log")
+	journalGroup.SetDescription(fmt.Sprintf("The last %d lines from the journal", serviceJournalLines))
+	page.Add(journalGroup)
+
+	var journalRows []*adw.ActionRow // Store references for cleanup
+	journalLoadingRow := adw.NewActionRow()
+	journalLoadingRow.SetTitle("Loading...")
+	journalGroup.Add(&journalLoadingRow.Widget)
+
+	dialog.Add(page)
+
+	// settingSwitch is set while refreshState moves the switch itself, so
+	// its state-set handler lets the change through instead of acting on it
+	settingSwitch := false
+
+	refreshState := func() {
+		unit, err := systemd.GetService(uh.ctx, name, sg.user)
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				stateRow.SetTitle("State unavailable")
+				stateRow.SetSubtitle(err.Error())
+				return
+			}
+			if unit.Description != "" {
+				dialog.SetTitle(unit.Description)
+			}
+			stateRow.SetTitle(unit.State())
+			stateRow.SetSubtitle(name)
+			startBtn.SetSensitive(!unit.Active())
+			stopBtn.SetSensitive(unit.Active())
+			restartBtn.SetSensitive(true)
+
+			settingSwitch = true
+			bootSwitch.SetActive(unit.FileState == "enabled")
+			settingSwitch = false
+			bootSwitch.SetSensitive(unit.CanEnable())
+			if unit.CanEnable() {
+				bootRow.SetSubtitle("")
+			} else {
+				bootRow.SetSubtitle(fmt.Sprintf("Not configurable: the unit is %s", unit.FileState))
+			}
+		})
+	}
+
+	refreshJournal := func() {
+		entries, err := systemd.Journal(uh.ctx, name, sg.user, serviceJournalLines)
+		sgtk.RunOnMainThread(func() {
+			for _, row := range journalRows {
+				journalGroup.Remove(&row.Widget)
+			}
+			journalRows = nil
+
+			if err != nil {
+				journalLoadingRow.SetTitle("Failed to read the journal")
+				journalLoadingRow.SetSubtitle(err.Error())
+				journalLoadingRow.SetVisible(true)
+				return
+			}
+			if len(entries) == 0 {
+				journalLoadingRow.SetTitle("No log entries")
+				journalLoadingRow.SetSubtitle("Nothing this account may read was logged by the service")
+				journalLoadingRow.SetVisible(true)
+				return
+			}
+			journalLoadingRow.SetVisible(false)
+
+			// Newest first, like the audit log
+			for i := len(entries) - 1; i >= 0; i-- {
+				row := newJournalRow(entries[i])
+				journalGroup.Add(&row.Widget)
+				journalRows = append(journalRows, row)
+			}
+		})
+	}
+
+	// runAction runs one systemctl action, then re-reads the dialog and the
+	// group's list
+	runAction := func(verb string, action func(context.Context, string, bool) error) {
+		startBtn.SetSensitive(false)
+		stopBtn.SetSensitive(false)
+		restartBtn.SetSensitive(false)
+		bootSwitch.SetSensitive(false)
+		go func() {
+			err := action(uh.ctx, name, sg.user)
+			sgtk.RunOnMainThread(func() {
+				if err != nil {
+					uh.toastAdder.ShowErrorToast(fmt.Sprintf("Failed to %s %s: %v", verb, name, err))
+				} else {
+					uh.toastAdder.ShowToast(actionmsg.ServiceAction(systemd.IsDryRun(), verb, name))
+				}
+			})
+			refreshState()
+			refreshJournal()
+			if err == nil && !systemd.IsDryRun() {
+				uh.loadServices(sg)
+			}
+		}()
+	}
+
+	startCb := func(_ gtk.Button) {
+		runAction("start", systemd.Start)
+	}
+	startBtn.ConnectClicked(&startCb)
+
+	stopCb := func(_ gtk.Button) {
+		uh.confirmDestructive(&dialog.Widget,
+			fmt.Sprintf("Stop %s?", name),
+			"Anything that depends on the service stops working until it is started again.",
+			"Stop",
+			func() { runAction("stop", systemd.Stop) })
+	}
+	stopBtn.ConnectClicked(&stopCb)
+
+	restartCb := func(_ gtk.Button) {
+		runAction("restart", systemd.Restart)
+	}
+	restartBtn.ConnectClicked(&restartCb)
+
+	stateSetCb := func(_ gtk.Switch, state bool) bool {
+		if settingSwitch {
+			return false
+		}
+		if state {
+			runAction("enable", systemd.Enable)
+		} else {
+			runAction("disable", systemd.Disable)
+		}
+		return true // refreshState moves the switch once systemd agrees
+	}
+	bootSwitch.ConnectStateSet(&stateSetCb)
+
+	go refreshState()
+	go refreshJournal()
+
+	dialog.Present(&uh.servicesPrefsPage.Widget)
+}
@@ -0,0 +1,164 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/systemd"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// servicesGroup holds the widgets of the system or user services group on
+// the Services page
+type servicesGroup struct {
+	user            bool
+	group           *adw.PreferencesGroup
+	failedExpander  *adw.ExpanderRow
+	runningExpander *adw.ExpanderRow
+	otherExpander   *adw.ExpanderRow
+	failedRows      []*adw.ActionRow // Store references for cleanup
+	runningRows     []*adw.ActionRow // Store references for cleanup
+	otherRows       []*adw.ActionRow // Store references for cleanup
+}
+
+// hasServicesPage reports whether the Services page is shown: systemctl is
+// installed and at least one of its groups is enabled
+func (uh *UserHome) hasServicesPage() bool {
+	if !systemd.IsInstalled() {
+		return false
+	}
+	return uh.config.IsGroupEnabled("services_page", "system_services_group") ||
+		uh.config.IsGroupEnabled("services_page", "user_services_group")
+}
+
+// buildServicesPage builds one group for the system's services and one for
+// the user's, each split into failed, running and stopped services
+func (uh *UserHome) buildServicesPage() {
+	page := uh.servicesPrefsPage
+	if page == nil {
+		return
+	}
+
+	if uh.config.IsGroupEnabled("services_page", "system_services_group") {
+		uh.addServicesGroup(page, "System Services", false)
+	}
+	if uh.config.IsGroupEnabled("services_page", "user_services_group") {
+		uh.addServicesGroup(page, "User Services", true)
+	}
+}
+
+// addServicesGroup adds a services group to page and starts loading it
+func (uh *UserHome) addServicesGroup(page *adw.PreferencesPage, title string, user bool) {
+	sg := &servicesGroup{user: user}
+
+	sg.group = adw.NewPreferencesGroup()
+	sg.group.SetTitle(title)
+	sg.group.SetDescription("Loading services...")
+	uh.addRefreshButton(sg.group, func() { uh.loadServices(sg) })
+
+	sg.failedExpander = adw.NewExpanderRow()
+	sg.failedExpander.SetTitle("Failed")
+	sg.failedExpander.SetSubtitle("Loading...")
+	sg.failedExpander.SetEnableExpansion(false)
+	sg.group.Add(&sg.failedExpander.Widget)
+
+	sg.runningExpander = adw.NewExpanderRow()
+	sg.runningExpander.SetTitle("Running")
+	sg.runningExpander.SetSubtitle("Loading...")
+	sg.group.Add(&sg.runningExpander.Widget)
+
+	sg.otherExpander = adw.NewExpanderRow()
+	sg.otherExpander.SetTitle("Stopped")
+	sg.otherExpander.SetSubtitle("Loading...")
+	sg.group.Add(&sg.otherExpander.Widget)
+
+	page.Add(sg.group)
+	uh.servicesGroups = append(uh.servicesGroups, sg)
+
+	go uh.loadServices(sg)
+}
+
+// loadServices lists a group's services and sorts them into its expanders
+func (uh *UserHome) loadServices(sg *servicesGroup) {
+	units, err := systemd.ListServices(uh.ctx, sg.user)
+
+	sgtk.RunOnMainThread(func() {
+		if err != nil {
+			sg.group.SetDescription(fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		var failed, running, other []systemd.Unit
+		for _, u := range units {
+			switch {
+			case u.Failed():
+				failed = append(failed, u)
+			case u.Active():
+				running = append(running, u)
+			default:
+				other = append(other, u)
+			}
+		}
+
+		if sg.user {
+			sg.group.SetDescription("Services of your own session; no administrator needed")
+		} else {
+			sg.group.SetDescription("Starting, stopping or changing a system service requires administrator")
+		}
+
+		sg.failedRows = uh.fillServiceExpander(sg, sg.failedExpander, sg.failedRows, failed)
+		if len(failed) == 0 {
+			sg.failedExpander.SetSubtitle("No failed services")
+		} else {
+			sg.failedExpander.SetSubtitle(fmt.Sprintf("%d failed", len(failed)))
+		}
+		sg.failedExpander.SetEnableExpansion(len(failed) > 0)
+
+		sg.runningRows = uh.fillServiceExpander(sg, sg.runningExpander, sg.runningRows, running)
+		sg.runningExpander.SetSubtitle(fmt.Sprintf("%d running", len(running)))
+
+		sg.otherRows = uh.fillServiceExpander(sg, sg.otherExpander, sg.otherRows, other)
+		sg.otherExpander.SetSubtitle(fmt.Sprintf("%d stopped", len(other)))
+	})
+}
+
+// fillServiceExpander replaces an expander's rows with one row per unit,
+// each opening the service's details, and returns the new rows
+func (uh *UserHome) fillServiceExpander(sg *servicesGroup, expander *adw.ExpanderRow, old []*adw.ActionRow, units []systemd.Unit) []*adw.ActionRow {
+	for _, row := range old {
+		expander.Remove(&row.Widget)
+	}
+
+	rows := make([]*adw.ActionRow, 0, len(units))
+	for _, u := range units {
+		row := adw.NewActionRow()
+		title := u.Description
+		if title == "" {
+			title = u.Name
+		}
+		row.SetTitle(title)
+		row.SetSubtitle(fmt.Sprintf("%s — %s", u.Name, u.State()))
+		row.SetActivatable(true)
+
+		if u.Failed() {
+			icon := gtk.NewImageFromIconName("dialog-error-symbolic")
+			icon.AddCssClass("error")
+			row.AddPrefix(&icon.Widget)
+		}
+		nextIcon := gtk.NewImageFromIconName("go-next-symbolic")
+		row.AddSuffix(&nextIcon.Widget)
+
+		name := u.Name
+		activatedCb := func(_ adw.ActionRow) {
+			uh.showServiceDetails(sg, name)
+		}
+		row.ConnectActivated(&activatedCb)
+
+		expander.AddRow(&row.Widget)
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,25 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/frostyard/chairlift/internal/preview"
+)
+
+// withSize appends an installed size to a row subtitle. Unknown sizes (0)
+// leave the subtitle unchanged.
+func withSize(subtitle string, size int64) string {
+	if size <= 0 {
+		return subtitle
+	}
+	return fmt.Sprintf("%s · %s", subtitle, preview.FormatSize(size))
+}
+
+// withTotal appends a manager's total installed size to an expander
+// subtitle, when any size is known
+func withTotal(subtitle string, total int64) string {
+	if total <= 0 {
+		return subtitle
+	}
+	return fmt.Sprintf("%s, %s on disk", subtitle, preview.FormatSize(total))
+}
@@ -0,0 +1,243 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/preview"
+	"github.com/frostyard/chairlift/internal/storage"
+	"github.com/frostyard/chairlift/internal/systemd"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// diskWarningFraction is the usage above which a filesystem is marked
+const diskWarningFraction = 0.9
+
+// Cleanups a space consumer's Clean Up button runs
+const (
+	cleanupNone = iota
+	cleanupFlatpak
+	cleanupBrew
+	cleanupJournal
+)
+
+// spaceConsumer is one measured directory on the Storage page
+type spaceConsumer struct {
+	title   string
+	path    string
+	size    int64
+	cleanup int
+}
+
+// hasStoragePage reports whether the Storage page is shown: at least one
+// of its groups is enabled
+func (uh *UserHome) hasStoragePage() bool {
+	return uh.config.IsGroupEnabled("storage_page", "filesystems_group") ||
+		uh.config.IsGroupEnabled("storage_page", "consumers_group")
+}
+
+// buildStoragePage builds the Disks and Largest Consumers groups
+func (uh *UserHome) buildStoragePage() {
+	page := uh.storagePrefsPage
+	if page == nil {
+		return
+	}
+
+	if uh.config.IsGroupEnabled("storage_page", "filesystems_group") {
+		uh.storageDisksGroup = adw.NewPreferencesGroup()
+		uh.storageDisksGroup.SetTitle("Disks")
+		uh.storageDisksGroup.SetDescription("Loading filesystems...")
+		uh.addRefreshButton(uh.storageDisksGroup, uh.loadFilesystems)
+		page.Add(uh.storageDisksGroup)
+		go uh.loadFilesystems()
+	}
+
+	if uh.config.IsGroupEnabled("storage_page", "consumers_group") {
+		uh.storageConsumersGroup = adw.NewPreferencesGroup()
+		uh.storageConsumersGroup.SetTitle("Largest Consumers")
+		uh.storageConsumersGroup.SetDescription("Measuring...")
+		uh.addRefreshButton(uh.storageConsumersGroup, uh.loadSpaceConsumers)
+		page.Add(uh.storageConsumersGroup)
+		go uh.loadSpaceConsumers()
+	}
+}
+
+// loadFilesystems lists the mounted filesystems with a usage bar each
+func (uh *UserHome) loadFilesystems() {
+	filesystems, err := storage.ListFilesystems(uh.ctx)
+
+	sgtk.RunOnMainThread(func() {
+		group := uh.storageDisksGroup
+		for _, row := range uh.storageDiskRows {
+			group.Remove(&row.Widget)
+		}
+		uh.storageDiskRows = nil
+
+		if err != nil {
+			group.SetDescription(fmt.Sprintf("Error: %v", err))
+			return
+		}
+		group.SetDescription("Mounted filesystems and how full they are")
+
+		for _, fs := range filesystems {
+			row := adw.NewActionRow()
+			row.SetTitle(fs.Mount)
+			row.SetSubtitle(fmt.Sprintf("%s used of %s, %s free · %s on %s",
+				preview.FormatSize(fs.Used), preview.FormatSize(fs.Size), preview.FormatSize(fs.Avail), fs.Type, fs.Device))
+
+			if fs.UsedFraction() >= diskWarningFraction {
+				icon := gtk.NewImageFromIconName("dialog-warning-symbolic")
+				icon.AddCssClass("warning")
+				icon.SetTooltipText("Almost full")
+				row.AddPrefix(&icon.Widget)
+			}
+
+			bar := gtk.NewProgressBar()
+			bar.SetFraction(fs.UsedFraction())
+			bar.SetValign(gtk.AlignCenterValue)
+			bar.SetSizeRequest(120, -1)
+			bar.SetTooltipText(fmt.Sprintf("%.0f%% used", fs.UsedFraction()*100))
+			row.AddSuffix(&bar.Widget)
+
+			group.Add(&row.Widget)
+			uh.storageDiskRows = append(uh.storageDiskRows, row)
+		}
+	})
+}
+
+// measureSpaceConsumers measures the directories ChairLift can clean up,
+// largest first. Tools that are not installed are skipped, and so is
+// anything that cannot be measured or holds nothing.
+func (uh *UserHome) measureSpaceConsumers() []spaceConsumer {
+	var consumers []spaceConsumer
+	add := func(title, path string, size int64, err error, cleanup int) {
+		if err != nil || size <= 0 {
+			return
+		}
+		consumers = append(consumers, spaceConsumer{title: title, path: path, size: size, cleanup: cleanup})
+	}
+
+	if flatpak.IsInstalledCached() {
+		system, user := flatpak.InstallationDirs()
+		size, err := storage.DirSize(uh.ctx, system)
+		add("Flatpak Applications and Runtimes", system, size, err, cleanupFlatpak)
+		size, err = storage.DirSize(uh.ctx, user)
+		add("Flatpak Applications and Runtimes (User)", user, size, err, cleanupFlatpak)
+	}
+
+	if homebrew.IsInstalledCached() {
+		if cellar, cache, err := homebrew.Locations(uh.ctx); err == nil {
+			size, err := storage.DirSize(uh.ctx, cellar)
+			add("Homebrew Packages", cellar, size, err, cleanupBrew)
+			size, err = storage.DirSize(uh.ctx, cache)
+			add("Homebrew Download Cache", cache, size, err, cleanupBrew)
+		}
+	}
+
+	if systemd.JournalInstalled() {
+		size, err := systemd.JournalDiskUsage(uh.ctx)
+		add("System Journal", "/var/log/journal", size, err, cleanupJournal)
+	}
+
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].size > consumers[j].size })
+	return consumers
+}
+
+// loadSpaceConsumers measures the space consumers and lists them, each
+// with a Clean Up button for the cleanup that trims it
+func (uh *UserHome) loadSpaceConsumers() {
+	consumers := uh.measureSpaceConsumers()
+
+	sgtk.RunOnMainThread(func() {
+		group := uh.storageConsumersGroup
+		for _, row := range uh.storageConsumerRows {
+			group.Remove(&row.Widget)
+		}
+		uh.storageConsumerRows = nil
+
+		if len(consumers) == 0 {
+			group.SetDescription("Nothing ChairLift can clean up was found")
+			return
+		}
+		var total int64
+		for _, c := range consumers {
+			total += c.size
+		}
+		group.SetDescription(fmt.Sprintf("%s in places ChairLift can clean up", preview.FormatSize(total)))
+
+		for _, c := range consumers {
+			row := adw.NewActionRow()
+			row.SetTitle(c.title)
+			row.SetSubtitle(fmt.Sprintf("%s · %s", preview.FormatSize(c.size), c.path))
+			if c.cleanup != cleanupNone {
+				uh.addSpaceCleanupButton(row, c.cleanup)
+			}
+			group.Add(&row.Widget)
+			uh.storageConsumerRows = append(uh.storageConsumerRows, row)
+		}
+	})
+}
+
+// addSpaceCleanupButton adds a Clean Up button that asks, then runs the
+// same cleanup as the Maintenance page
+func (uh *UserHome) addSpaceCleanupButton(row *adw.ActionRow, cleanup int) {
+	button := gtk.NewButtonWithLabel("Clean Up")
+	button.SetValign(gtk.AlignCenterValue)
+
+	clickedCb := func(_ gtk.Button) {
+		parent := &uh.storagePrefsPage.Widget
+		switch cleanup {
+		case cleanupFlatpak:
+			uh.confirmDestructive(parent,
+				"Remove Unused Runtimes?",
+				"Runtimes and extensions that no installed application depends on will be uninstalled.",
+				"Remove",
+				func() { uh.onFlatpakCleanupClicked(button) })
+		case cleanupBrew:
+			uh.confirmDestructive(parent,
+				"Clean Up Homebrew?",
+				"Old versions of installed packages and cached downloads will be deleted. You will not be able to switch back to those versions without downloading them again.",
+				"Clean Up",
+				func() { uh.onBrewCleanupClicked(button) })
+		case cleanupJournal:
+			uh.confirmDestructive(parent,
+				"Trim the Journal?",
+				fmt.Sprintf("The oldest log files will be deleted until the journal takes up at most %s. Older entries can no longer be read on the Logs page.", systemd.JournalVacuumSize),
+				"Trim",
+				func() { uh.onJournalVacuumClicked(button) })
+		}
+	}
+	button.ConnectClicked(&clickedCb)
+	row.AddSuffix(&button.Widget)
+}
+
+// onJournalVacuumClicked trims the journal, then measures again
+func (uh *UserHome) onJournalVacuumClicked(button *gtk.Button) {
+	button.SetSensitive(false)
+	button.SetLabel("Cleaning...")
+
+	go func() {
+		err := systemd.VacuumJournal(uh.ctx)
+
+		sgtk.RunOnMainThread(func() {
+			button.SetSensitive(true)
+			button.SetLabel("Clean Up")
+
+			if err != nil {
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Journal cleanup failed: %v", err))
+				return
+			}
+			uh.toastAdder.ShowToast(actionmsg.JournalVacuum(systemd.IsDryRun(), systemd.JournalVacuumSize))
+			if !systemd.IsDryRun() {
+				go uh.loadSpaceConsumers()
+			}
+		})
+	}()
+}
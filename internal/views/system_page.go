@@ -2,11 +2,15 @@ package views
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/frostyard/chairlift/internal/bootc"
+	"github.com/frostyard/chairlift/internal/views/actionmsg"
 
 	sgtk "github.com/frostyard/snowkit/gtk"
 
@@ -53,6 +57,8 @@ func (uh *UserHome) buildSystemPage() {
 
 		group.Add(&bootcExpander.Widget)
 		page.Add(group)
+		uh.bootcStatusGroup = group
+		uh.bootcStatusExpander = bootcExpander
 
 		// Gate + load asynchronously
 		go uh.loadBootcStatus(group, bootcExpander)
@@ -130,6 +136,8 @@ func (uh *UserHome) loadOSRelease(expander *adw.ExpanderRow) {
 				uh.openURL(url)
 			}
 			row.ConnectActivated(&activatedCb)
+		} else {
+			uh.addCopyButton(row, value)
 		}
 
 		expander.AddRow(&row.Widget)
@@ -137,7 +145,8 @@ func (uh *UserHome) loadOSRelease(expander *adw.ExpanderRow) {
 }
 
 // loadBootcStatus checks the bootc boot gate and populates the status
-// expander. Runs in a goroutine; shows the group only on bootc hosts.
+// expander, replacing rows from an earlier load. Runs in a goroutine;
+// shows the group only on bootc hosts.
 func (uh *UserHome) loadBootcStatus(group *adw.PreferencesGroup, expander *adw.ExpanderRow) {
 	if !bootc.IsBootcBootedCached() {
 		return // group stays hidden on non-bootc hosts
@@ -147,6 +156,7 @@ func (uh *UserHome) loadBootcStatus(group *adw.PreferencesGroup, expander *adw.E
 	defer cancel()
 
 	status, err := bootc.GetStatus(ctx)
+	policy := loadSignaturePolicy()
 
 	sgtk.RunOnMainThread(func() {
 		group.SetVisible(true)
@@ -157,29 +167,39 @@ func (uh *UserHome) loadBootcStatus(group *adw.PreferencesGroup, expander *adw.E
 		}
 
 		expander.SetSubtitle("Loaded")
+		uh.updateRestartBanner(status)
 
-		addRow := func(title, subtitle string) {
+		for _, row := range uh.bootcStatusRows {
+			expander.Remove(&row.Widget)
+		}
+		uh.bootcStatusRows = nil
+
+		addRow := func(title, subtitle string) *adw.ActionRow {
 			row := adw.NewActionRow()
 			row.SetTitle(title)
 			row.SetSubtitle(subtitle)
 			expander.AddRow(&row.Widget)
+			uh.bootcStatusRows = append(uh.bootcStatusRows, row)
+			return row
 		}
 
 		booted := status.Status.Booted
 		if booted.ImageRef() != "" {
-			addRow("Image", booted.ImageRef())
+			uh.addCopyButton(addRow("Image", booted.ImageRef()), booted.ImageRef())
 		}
 		if booted.Version() != "" {
-			addRow("Version", booted.Version())
+			uh.addCopyButton(addRow("Version", booted.Version()), booted.Version())
 		}
 		if booted.Timestamp() != "" {
 			addRow("Built", booted.Timestamp())
 		}
 		if digest := booted.Digest(); digest != "" {
-			if len(digest) > 19 {
-				digest = digest[:19] + "..."
-			}
-			addRow("Digest", digest)
+			// Copy the full digest, not the truncated subtitle.
+			uh.addCopyButton(addRow("Digest", shortDigest(digest)), digest)
+		}
+		if ref := booted.Reference(); ref != nil {
+			styleProvenanceRow(addRow("Signature", ""), ref.Provenance(policy))
+			uh.addSwitchImageRow(addRow("Change System Image", "Switch to another image or channel, such as :testing"), ref)
 		}
 
 		if staged := status.Status.Staged; staged != nil {
@@ -191,11 +211,172 @@ func (uh *UserHome) loadBootcStatus(group *adw.PreferencesGroup, expander *adw.E
 		}
 
 		if rollback := status.Status.Rollback; rollback != nil {
-			subtitle := rollback.Version()
-			if subtitle == "" {
-				subtitle = "Available"
+			row := addRow("Roll Back to Previous Version", rollbackSubtitle(rollback))
+			if status.Status.RollbackQueued {
+				row.SetSubtitle(fmt.Sprintf("%s — restart to boot this version", rollbackSubtitle(rollback)))
+			} else {
+				uh.addRollbackRow(row, rollback)
 			}
-			addRow("Rollback", subtitle)
 		}
 	})
 }
+
+// loadSignaturePolicy reads the containers signature policy, returning nil
+// when it cannot be read so provenance falls back to unverified
+func loadSignaturePolicy() *bootc.Policy {
+	policy, err := bootc.LoadPolicy(bootc.PolicyPath)
+	if err != nil {
+		log.Printf("Error reading signature policy: %v", err)
+		return nil
+	}
+	return policy
+}
+
+// styleProvenanceRow fills row with an image's signature status. An image
+// pulled without a signature check gets a warning icon and style, since
+// nothing vouches for what it boots.
+func styleProvenanceRow(row *adw.ActionRow, prov bootc.Provenance) {
+	if !prov.Signed {
+		row.SetSubtitle(fmt.Sprintf("Not verified — %s does not require a signature", prov.Registry))
+		row.AddCssClass("warning")
+		icon := gtk.NewImageFromIconName("dialog-warning-symbolic")
+		icon.SetTooltipText("Unsigned image")
+		row.AddSuffix(&icon.Widget)
+		return
+	}
+
+	subtitle := fmt.Sprintf("Signed — verified when pulled from %s", prov.Registry)
+	if prov.Signer != "" {
+		subtitle = fmt.Sprintf("Signed by %s — verified when pulled from %s", prov.Signer, prov.Registry)
+	}
+	row.SetSubtitle(subtitle)
+	icon := gtk.NewImageFromIconName("channel-secure-symbolic")
+	icon.SetTooltipText("Signature required")
+	row.AddSuffix(&icon.Widget)
+}
+
+// watchBootcStatus keeps the System page's deployment details and the
+// Updates page's bootc status current when a deployment changes outside
+// ChairLift, such as an update staged from a terminal. Runs in a
+// goroutine until the pages shut down.
+func (uh *UserHome) watchBootcStatus() {
+	if uh.bootcStatusGroup == nil && uh.bootcUpdatesGroup == nil {
+		return
+	}
+	if !bootc.IsBootcBootedCached() {
+		return
+	}
+
+	bootc.WatchStatus(uh.ctx, bootc.StatusPollInterval, func(*bootc.Status) {
+		log.Println("bootc deployments changed; refreshing status")
+		if uh.bootcStatusGroup != nil {
+			uh.loadBootcStatus(uh.bootcStatusGroup, uh.bootcStatusExpander)
+		}
+		if uh.bootcUpdatesGroup != nil {
+			uh.loadBootcUpdateStatus(uh.bootcUpdatesGroup)
+		}
+	})
+}
+
+// shortDigest truncates an image digest for display
+func shortDigest(digest string) string {
+	if len(digest) > 19 {
+		return digest[:19] + "..."
+	}
+	return digest
+}
+
+// bootcFailureText returns the toast text for a failed bootc operation:
+// the plain-language cause and hint when it is recognised, otherwise the
+// raw error
+func bootcFailureText(prefix string, err error) string {
+	var opErr *bootc.OperationError
+	if errors.As(err, &opErr) {
+		return fmt.Sprintf("%s: %s", prefix, opErr.UserMessage())
+	}
+	return fmt.Sprintf("%s: %v", prefix, err)
+}
+
+// rollbackSubtitle describes the previous deployment by version and digest
+func rollbackSubtitle(rollback *bootc.Deployment) string {
+	var parts []string
+	if version := rollback.Version(); version != "" {
+		parts = append(parts, version)
+	}
+	if digest := rollback.Digest(); digest != "" {
+		parts = append(parts, shortDigest(digest))
+	}
+	if len(parts) == 0 {
+		return "Previous deployment"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// addRollbackRow adds a Roll Back button to row that makes the previous
+// deployment the default for the next boot
+func (uh *UserHome) addRollbackRow(row *adw.ActionRow, rollback *bootc.Deployment) {
+	version := rollback.Version()
+
+	button := gtk.NewButtonWithLabel("Roll Back...")
+	button.SetValign(gtk.AlignCenterValue)
+	clickedCb := func(_ gtk.Button) {
+		body := "The system will boot the previous deployment after the next restart. The current version stays installed and can be restored by rolling back again."
+		uh.confirmDestructive(&uh.systemPrefsPage.Widget, "Roll Back to Previous Version?", body, "Roll Back", func() {
+			uh.rollbackBootc(row, button, version)
+		})
+	}
+	button.ConnectClicked(&clickedCb)
+	row.AddSuffix(&button.Widget)
+}
+
+// rollbackBootc runs bootc rollback, reporting progress in row's subtitle
+func (uh *UserHome) rollbackBootc(row *adw.ActionRow, button *gtk.Button, version string) {
+	previous := row.GetSubtitle()
+	button.SetSensitive(false)
+	button.SetLabel("Rolling Back...")
+	row.SetSubtitle("Starting rollback...")
+
+	go func() {
+		progressCh := make(chan bootc.ProgressEvent)
+
+		var rollbackErr error
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rollbackErr = bootc.Rollback(uh.ctx, progressCh)
+		}()
+
+		for evt := range progressCh {
+			if evt.Type != bootc.EventMessage {
+				continue
+			}
+			msg := evt.Message
+			sgtk.RunOnMainThread(func() {
+				row.SetSubtitle(msg)
+			})
+		}
+		wg.Wait()
+
+		sgtk.RunOnMainThread(func() {
+			if rollbackErr != nil {
+				row.SetSubtitle(previous)
+				button.SetSensitive(true)
+				button.SetLabel("Roll Back...")
+				uh.toastAdder.ShowErrorToast(bootcFailureText("Rollback failed", rollbackErr))
+				return
+			}
+			if bootc.IsDryRun() {
+				row.SetSubtitle(previous)
+				button.SetSensitive(true)
+				button.SetLabel("Roll Back...")
+			} else {
+				row.SetSubtitle("Restart to boot this version")
+				button.SetLabel("Queued")
+				uh.toastAdder.SetRestartBanner("Restart to finish the rollback")
+				uh.offerRestart(&uh.systemPrefsPage.Widget, "Rollback Queued")
+			}
+			uh.toastAdder.ShowToast(actionmsg.BootcRollback(bootc.IsDryRun(), version))
+		})
+	}()
+}
@@ -0,0 +1,122 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/frostyard/chairlift/internal/network"
+	"github.com/frostyard/chairlift/internal/updatecheck"
+	"github.com/frostyard/chairlift/internal/updex"
+
+	sgtk "github.com/frostyard/snowkit/gtk"
+
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+)
+
+// updatesNotificationID is reused so a newer notification replaces an
+// unread older one instead of stacking up
+const updatesNotificationID = "updates-available"
+
+// buildUpdateCheckGroup adds the Automatic Checks group and starts the
+// background checker
+func (uh *UserHome) buildUpdateCheckGroup(page *adw.PreferencesPage) {
+	groupCfg := uh.config.GetGroupConfig("updates_page", "update_check_group")
+	var configured string
+	if groupCfg != nil {
+		configured = groupCfg.Interval
+	}
+	interval, err := updatecheck.ParseInterval(configured)
+	if err != nil {
+		log.Printf("%v; using %s", err, updatecheck.DefaultInterval)
+		interval = updatecheck.DefaultInterval
+	}
+
+	group := adw.NewPreferencesGroup()
+	group.SetTitle("Automatic Checks")
+	group.SetDescription("Check for updates in the background while ChairLift is open and notify when new ones appear")
+
+	uh.updateCheckRow = adw.NewActionRow()
+	uh.updateCheckRow.SetTitle(fmt.Sprintf("Every %s", formatInterval(interval)))
+	uh.updateCheckRow.SetSubtitle("Not checked since startup")
+	icon := gtk.NewImageFromIconName("alarm-symbolic")
+	uh.updateCheckRow.AddPrefix(&icon.Widget)
+
+	checkBtn := gtk.NewButtonWithLabel("Check Now")
+	checkBtn.SetValign(gtk.AlignCenterValue)
+	clickedCb := func(_ gtk.Button) {
+		go uh.checkForUpdates(uh.ctx)
+	}
+	checkBtn.ConnectClicked(&clickedCb)
+	uh.updateCheckRow.AddSuffix(&checkBtn.Widget)
+
+	group.Add(&uh.updateCheckRow.Widget)
+	page.Add(group)
+
+	go updatecheck.Run(uh.ctx, interval, uh.checkForUpdates)
+}
+
+// formatInterval writes an interval the way a person would: "6 hours",
+// "90 minutes"
+func formatInterval(d time.Duration) string {
+	if d%time.Hour == 0 {
+		if hours := int(d / time.Hour); hours != 1 {
+			return fmt.Sprintf("%d hours", hours)
+		}
+		return "hour"
+	}
+	return fmt.Sprintf("%d minutes", int(d/time.Minute))
+}
+
+// updateCounts returns the current per-source update counts
+func (uh *UserHome) updateCounts() updatecheck.Counts {
+	uh.updateCountMu.Lock()
+	defer uh.updateCountMu.Unlock()
+	return updatecheck.Counts{
+		System:   uh.bootcUpdateCount,
+		Flatpak:  uh.flatpakUpdateCount,
+		Homebrew: uh.brewUpdateCount,
+		Features: uh.featureUpdateCount,
+	}
+}
+
+// checkForUpdates refetches the update lists shown on the Updates page and
+// the Features page's update check, which also refreshes the badge, and
+// posts a notification when a source gained updates. The system image is
+// compared with the registry through skopeo, which downloads nothing.
+// Runs in a goroutine; skipped while offline.
+func (uh *UserHome) checkForUpdates(ctx context.Context) {
+	if !network.IsOnline() {
+		return
+	}
+	before := uh.updateCounts()
+
+	if uh.flatpakUpdatesExpander != nil {
+		uh.lists.flatpakUpdates.Invalidate()
+		uh.loadFlatpakUpdates()
+	}
+	if uh.outdatedExpander != nil {
+		uh.lists.outdated.Invalidate()
+		uh.loadOutdatedPackages()
+	}
+	if uh.bootcUpdatesGroup != nil {
+		uh.loadBootcUpdateStatus(uh.bootcUpdatesGroup)
+	}
+	if uh.featuresGroup != nil && updex.IsInstalledCached() {
+		uh.checkFeatureUpdates()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	after := uh.updateCounts()
+	checked := time.Now()
+	sgtk.RunOnMainThread(func() {
+		uh.updateCheckRow.SetSubtitle(fmt.Sprintf("Last checked at %s", checked.Format("15:04")))
+		if body, ok := updatecheck.Notification(before, after); ok {
+			uh.toastAdder.Notify(updatesNotificationID, "Updates Available", body)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -11,6 +12,9 @@ import (
 	"github.com/frostyard/chairlift/internal/bootc"
 	"github.com/frostyard/chairlift/internal/flatpak"
 	"github.com/frostyard/chairlift/internal/homebrew"
+	"github.com/frostyard/chairlift/internal/network"
+	"github.com/frostyard/chairlift/internal/preview"
+	"github.com/frostyard/chairlift/internal/updateall"
 	"github.com/frostyard/chairlift/internal/views/actionmsg"
 	"github.com/frostyard/chairlift/internal/views/trustmsg"
 
@@ -27,10 +31,35 @@ func (uh *UserHome) buildUpdatesPage() {
 		return
 	}
 
+	// Update Everything group - runs every enabled source below in one go
+	if uh.config.IsGroupEnabled("updates_page", "update_all_group") {
+		group := adw.NewPreferencesGroup()
+		group.SetTitle("Update Everything")
+		group.SetDescription("Stage the system image, then update Flatpak applications and Homebrew packages")
+
+		uh.updateAllExpander = adw.NewExpanderRow()
+		uh.updateAllExpander.SetTitle("All Sources")
+		uh.updateAllExpander.SetSubtitle("Update every source shown on this page")
+		uh.updateAllExpander.SetEnableExpansion(false)
+
+		uh.updateAllBtn = gtk.NewButtonWithLabel("Update Everything")
+		uh.updateAllBtn.SetValign(gtk.AlignCenterValue)
+		uh.updateAllBtn.AddCssClass("suggested-action")
+		updateAllClickedCb := func(btn gtk.Button) {
+			uh.onUpdateEverythingClicked()
+		}
+		uh.updateAllBtn.ConnectClicked(&updateAllClickedCb)
+		uh.updateAllExpander.AddSuffix(&uh.updateAllBtn.Widget)
+
+		group.Add(&uh.updateAllExpander.Widget)
+		page.Add(group)
+	}
+
 	// bootc System Updates group - built hidden, shown asynchronously on
 	// bootc hosts that ship the update-stage script.
 	if uh.config.IsGroupEnabled("updates_page", "bootc_updates_group") {
 		group := adw.NewPreferencesGroup()
+		uh.bootcUpdatesGroup = group
 		group.SetTitle("System Updates")
 		group.SetDescription("Download and stage system image updates; staged updates apply on restart")
 		group.SetVisible(false)
@@ -59,11 +88,13 @@ func (uh *UserHome) buildUpdatesPage() {
 		group := adw.NewPreferencesGroup()
 		group.SetTitle("Flatpak Updates")
 		group.SetDescription("Available updates for Flatpak applications")
+		uh.addRefreshButton(group, uh.loadFlatpakUpdates, uh.lists.flatpakUpdates)
 
 		uh.flatpakUpdatesExpander = adw.NewExpanderRow()
 		uh.flatpakUpdatesExpander.SetTitle("Available Updates")
 		uh.flatpakUpdatesExpander.SetSubtitle("Loading...")
 		group.Add(&uh.flatpakUpdatesExpander.Widget)
+		uh.flatpakUpdateSelection = newBatchSelection(group, uh.flatpakUpdatesExpander, "Update", uh.updateSelectedFlatpaks)
 
 		page.Add(group)
 
@@ -76,6 +107,7 @@ func (uh *UserHome) buildUpdatesPage() {
 		group := adw.NewPreferencesGroup()
 		group.SetTitle("Homebrew Updates")
 		group.SetDescription("Check for and install Homebrew package updates")
+		uh.addRefreshButton(group, uh.loadOutdatedPackages, uh.lists.outdated)
 
 		// Update button row
 		updateRow := adw.NewActionRow()
@@ -98,6 +130,7 @@ func (uh *UserHome) buildUpdatesPage() {
 		uh.outdatedExpander.SetTitle("Outdated Packages")
 		uh.outdatedExpander.SetSubtitle("Loading...")
 		group.Add(&uh.outdatedExpander.Widget)
+		uh.outdatedSelection = newBatchSelection(group, uh.outdatedExpander, "Upgrade", uh.upgradeSelectedPackages)
 
 		page.Add(group)
 
@@ -116,6 +149,11 @@ func (uh *UserHome) buildUpdatesPage() {
 
 		go uh.loadUntrustedTaps()
 	}
+
+	// Automatic Checks group - re-runs the checks above on an interval
+	if uh.config.IsGroupEnabled("updates_page", "update_check_group") {
+		uh.buildUpdateCheckGroup(page)
+	}
 }
 
 // loadUntrustedTaps populates the Untrusted Taps group. Runs in a
@@ -125,7 +163,7 @@ func (uh *UserHome) loadUntrustedTaps() {
 		return
 	}
 
-	taps, err := homebrew.ListUntrustedTaps()
+	taps, err := homebrew.ListUntrustedTaps(uh.ctx)
 	if err != nil {
 		log.Printf("untrusted tap check failed: %v", err)
 		return
@@ -193,7 +231,7 @@ func (uh *UserHome) confirmTrustTap(tap homebrew.UntrustedTap, button *gtk.Butto
 
 // trustTap runs brew trust and updates the UI on completion.
 func (uh *UserHome) trustTap(tap homebrew.UntrustedTap, button *gtk.Button) {
-	err := homebrew.TrustPackages(tap)
+	err := homebrew.TrustPackages(uh.ctx, tap)
 
 	sgtk.RunOnMainThread(func() {
 		if err != nil {
@@ -253,7 +291,12 @@ func (uh *UserHome) loadOutdatedPackages() {
 		return
 	}
 
-	packages, err := homebrew.ListOutdated()
+	uh.lists.outdated.Load(uh.ctx, uh.showOutdatedPackages)
+}
+
+// showOutdatedPackages updates the badge count and replaces the Outdated
+// Packages rows with packages
+func (uh *UserHome) showOutdatedPackages(packages []homebrew.Package, err error) {
 	if err != nil {
 		uh.updateCountMu.Lock()
 		uh.brewUpdateCount = 0
@@ -265,7 +308,7 @@ func (uh *UserHome) loadOutdatedPackages() {
 				uh.outdatedExpander.Remove(&row.Widget)
 			}
 			uh.outdatedRows = nil
-			uh.outdatedExpander.SetSubtitle(fmt.Sprintf("Error: %v", err))
+			uh.outdatedExpander.SetSubtitle(errorText(err))
 		})
 		return
 	}
@@ -281,19 +324,38 @@ func (uh *UserHome) loadOutdatedPackages() {
 			uh.outdatedExpander.Remove(&row.Widget)
 		}
 		uh.outdatedRows = nil
+		uh.outdatedSelection.reset()
+		uh.outdatedNames = nil
+		defer uh.outdatedSelection.finishRows()
 
 		uh.outdatedExpander.SetSubtitle(fmt.Sprintf("%d packages available", len(packages)))
 		for _, pkg := range packages {
 			row := adw.NewActionRow()
 			row.SetTitle(pkg.Name)
-			row.SetSubtitle(pkg.Version)
+			if pkg.NewVersion != "" {
+				row.SetSubtitle(fmt.Sprintf("%s → %s", pkg.Version, pkg.NewVersion))
+			} else {
+				row.SetSubtitle(pkg.Version)
+			}
+
+			if pkg.Pinned {
+				// brew upgrade refuses pinned formulae; unpin from the
+				// Applications page to upgrade.
+				label := gtk.NewLabel("Pinned")
+				label.AddCssClass("dim-label")
+				label.SetTooltipText("Unpin on the Applications page to upgrade")
+				row.AddSuffix(&label.Widget)
+				uh.outdatedExpander.AddRow(&row.Widget)
+				uh.outdatedRows = append(uh.outdatedRows, row)
+				continue
+			}
 
 			upgradeBtn := gtk.NewButtonWithLabel("Upgrade")
 			upgradeBtn.SetValign(gtk.AlignCenterValue)
 			pkgName := pkg.Name
 			clickedCb := func(btn gtk.Button) {
 				go func() {
-					if err := homebrew.Upgrade(pkgName); err != nil {
+					if err := homebrew.Upgrade(uh.ctx, pkgName); err != nil {
 						var trustErr *homebrew.UntrustedTapError
 						msg := fmt.Sprintf("Upgrade failed: %v", err)
 						if errors.As(err, &trustErr) {
@@ -316,6 +378,8 @@ func (uh *UserHome) loadOutdatedPackages() {
 			upgradeBtn.ConnectClicked(&clickedCb)
 
 			row.AddSuffix(&upgradeBtn.Widget)
+			uh.outdatedSelection.addRow(row, &upgradeBtn.Widget)
+			uh.outdatedNames = append(uh.outdatedNames, pkgName)
 			uh.outdatedExpander.AddRow(&row.Widget)
 			uh.outdatedRows = append(uh.outdatedRows, row)
 		}
@@ -338,23 +402,34 @@ func (uh *UserHome) loadFlatpakUpdates() {
 		return
 	}
 
-	// Collect updates from both user and system installations
-	var allUpdates []flatpak.UpdateInfo
+	// Fails only when neither installation could be checked
+	uh.lists.flatpakUpdates.Load(uh.ctx, uh.showFlatpakUpdates)
+}
 
-	// Load user updates
-	userUpdates, err := flatpak.ListUpdates(true)
+// showFlatpakUpdates updates the badge count and replaces the Available
+// Updates rows with allUpdates
+func (uh *UserHome) showFlatpakUpdates(allUpdates []flatpak.UpdateInfo, err error) {
 	if err != nil {
-		log.Printf("Error loading user flatpak updates: %v", err)
-	} else {
-		allUpdates = append(allUpdates, userUpdates...)
-	}
+		uh.updateCountMu.Lock()
+		uh.flatpakUpdateCount = 0
+		uh.updateCountMu.Unlock()
+		uh.updateBadgeCount()
 
-	// Load system updates
-	systemUpdates, err := flatpak.ListUpdates(false)
-	if err != nil {
-		log.Printf("Error loading system flatpak updates: %v", err)
-	} else {
-		allUpdates = append(allUpdates, systemUpdates...)
+		sgtk.RunOnMainThread(func() {
+			if uh.flatpakUpdatesExpander == nil {
+				return
+			}
+			for _, row := range uh.flatpakUpdateRows {
+				uh.flatpakUpdatesExpander.Remove(&row.Widget)
+			}
+			uh.flatpakUpdateRows = nil
+			uh.flatpakUpdateSelection.reset()
+			uh.flatpakUpdateItems = nil
+			uh.flatpakUpdateSelection.finishRows()
+			uh.flatpakUpdatesExpander.SetSubtitle(errorText(err))
+			uh.flatpakUpdatesExpander.SetEnableExpansion(false)
+		})
+		return
 	}
 
 	// Update the badge count
@@ -373,6 +448,9 @@ func (uh *UserHome) loadFlatpakUpdates() {
 			uh.flatpakUpdatesExpander.Remove(&row.Widget)
 		}
 		uh.flatpakUpdateRows = nil
+		uh.flatpakUpdateSelection.reset()
+		uh.flatpakUpdateItems = nil
+		defer uh.flatpakUpdateSelection.finishRows()
 
 		if len(allUpdates) == 0 {
 			uh.flatpakUpdatesExpander.SetSubtitle("All applications are up to date")
@@ -406,7 +484,7 @@ func (uh *UserHome) loadFlatpakUpdates() {
 				btn.SetSensitive(false)
 				btn.SetLabel("Updating...")
 				go func() {
-					if err := flatpak.Update(appID, isUser); err != nil {
+					if err := flatpak.Update(uh.ctx, appID, isUser); err != nil {
 						sgtk.RunOnMainThread(func() {
 							btn.SetSensitive(true)
 							btn.SetLabel("Update")
@@ -424,14 +502,132 @@ func (uh *UserHome) loadFlatpakUpdates() {
 			updateBtn.ConnectClicked(&clickedCb)
 
 			row.AddSuffix(&updateBtn.Widget)
+			uh.flatpakUpdateSelection.addRow(row, &updateBtn.Widget)
+			uh.flatpakUpdateItems = append(uh.flatpakUpdateItems, update)
 			uh.flatpakUpdatesExpander.AddRow(&row.Widget)
 			uh.flatpakUpdateRows = append(uh.flatpakUpdateRows, row)
 		}
 	})
 }
 
+// updateSelectedFlatpaks previews the checked rows of the Flatpak updates
+// list and, once confirmed, updates them through flatpak.UpdateBatch and
+// reloads the list
+func (uh *UserHome) updateSelectedFlatpaks(indices []int) {
+	sel := uh.flatpakUpdateSelection
+	var updates []flatpak.UpdateInfo
+	for _, i := range indices {
+		updates = append(updates, uh.flatpakUpdateItems[i])
+	}
+
+	go func() {
+		plan := flatpak.PreviewUpdate(uh.ctx, updates)
+		sgtk.RunOnMainThread(func() {
+			uh.confirmPlan(&uh.updatesPrefsPage.Widget, "Update Selected Applications?", "Update", plan, flatpak.IsDryRun(),
+				func() {
+					sel.progress(0, len(updates))
+					go uh.runFlatpakBatch(updates)
+				}, sel.resume)
+		})
+	}()
+}
+
+// runFlatpakBatch runs a confirmed batch of Flatpak updates
+func (uh *UserHome) runFlatpakBatch(updates []flatpak.UpdateInfo) {
+	sel := uh.flatpakUpdateSelection
+	done := 0
+	errs := flatpak.UpdateBatch(uh.ctx, updates, func(flatpak.UpdateInfo, error) {
+		sgtk.RunOnMainThread(func() {
+			done++
+			sel.progress(done, len(updates))
+		})
+	})
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Batch update of %s failed: %v", updates[i].ApplicationID, err)
+			failed = append(failed, updates[i].Name)
+		}
+	}
+
+	sgtk.RunOnMainThread(func() {
+		sel.finish()
+		if len(failed) > 0 {
+			uh.toastAdder.ShowErrorToast(fmt.Sprintf("%d of %d updates failed: %s", len(failed), len(updates), strings.Join(failed, ", ")))
+		} else {
+			uh.toastAdder.ShowToast(actionmsg.BatchUpdate(flatpak.IsDryRun(), len(updates)))
+		}
+		go uh.loadFlatpakUpdates()
+	})
+}
+
+// upgradeSelectedPackages previews the checked rows of the outdated
+// Homebrew packages list with brew upgrade --dry-run and, once confirmed,
+// upgrades them through homebrew.UpgradeBatch and reloads the list
+func (uh *UserHome) upgradeSelectedPackages(indices []int) {
+	sel := uh.outdatedSelection
+	var names []string
+	for _, i := range indices {
+		names = append(names, uh.outdatedNames[i])
+	}
+
+	go func() {
+		plan, err := homebrew.PreviewUpgrade(uh.ctx, names)
+		sgtk.RunOnMainThread(func() {
+			if err != nil {
+				sel.resume()
+				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Could not preview upgrade: %v", err))
+				return
+			}
+			uh.confirmPlan(&uh.updatesPrefsPage.Widget, "Upgrade Selected Packages?", "Upgrade", plan, homebrew.IsDryRun(),
+				func() {
+					sel.progress(0, len(names))
+					go uh.runHomebrewBatch(names)
+				}, sel.resume)
+		})
+	}()
+}
+
+// runHomebrewBatch runs a confirmed batch of Homebrew upgrades
+func (uh *UserHome) runHomebrewBatch(names []string) {
+	sel := uh.outdatedSelection
+	done := 0
+	errs := homebrew.UpgradeBatch(uh.ctx, names, func(string, error) {
+		sgtk.RunOnMainThread(func() {
+			done++
+			sel.progress(done, len(names))
+		})
+	})
+
+	var failed []string
+	untrusted := false
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Batch upgrade of %s failed: %v", names[i], err)
+			failed = append(failed, names[i])
+			var trustErr *homebrew.UntrustedTapError
+			untrusted = untrusted || errors.As(err, &trustErr)
+		}
+	}
+
+	sgtk.RunOnMainThread(func() {
+		sel.finish()
+		switch {
+		case len(failed) == 1 && untrusted:
+			uh.toastAdder.ShowErrorToast(trustmsg.UpgradeMessage(failed[0], uh.brewTrustGroup != nil))
+		case len(failed) > 0:
+			uh.toastAdder.ShowErrorToast(fmt.Sprintf("%d of %d upgrades failed: %s", len(failed), len(names), strings.Join(failed, ", ")))
+		default:
+			uh.toastAdder.ShowToast(actionmsg.BatchUpgrade(homebrew.IsDryRun(), len(names)))
+		}
+		go uh.loadOutdatedPackages()
+	})
+}
+
 // loadBootcUpdateStatus gates the bootc updates group and reflects the
 // current staged/booted state in the expander subtitle and update badge.
+// A staged image or a newer one in the registry counts as one update.
 func (uh *UserHome) loadBootcUpdateStatus(group *adw.PreferencesGroup) {
 	if !bootc.IsBootcBootedCached() || !bootc.StageScriptAvailable() {
 		return // group stays hidden
@@ -441,10 +637,15 @@ func (uh *UserHome) loadBootcUpdateStatus(group *adw.PreferencesGroup) {
 	defer cancel()
 
 	status, err := bootc.GetStatus(ctx)
+	policy := loadSignaturePolicy()
 
 	staged := err == nil && status.Status.Staged != nil
+	var remote *bootc.RemoteImage
+	if err == nil && !staged {
+		remote = checkRemoteImage(uh.ctx, status)
+	}
 	uh.updateCountMu.Lock()
-	if staged {
+	if staged || (err == nil && remote.Newer(status.Status.Booted)) {
 		uh.bootcUpdateCount = 1
 	} else {
 		uh.bootcUpdateCount = 0
@@ -465,18 +666,116 @@ func (uh *UserHome) loadBootcUpdateStatus(group *adw.PreferencesGroup) {
 			} else {
 				uh.bootcStageExpander.SetSubtitle("Update staged — restart to apply")
 			}
+		} else if remote.Newer(status.Status.Booted) {
+			uh.bootcStageExpander.SetSubtitle(fmt.Sprintf("Update available (%s download)", preview.FormatSize(remote.DownloadSize)))
+		} else if remote != nil {
+			uh.bootcStageExpander.SetSubtitle("System is up to date")
 		} else {
 			uh.bootcStageExpander.SetSubtitle("Check for and download the latest system image")
 		}
+		uh.showUpdateSource(status.Spec.Image, policy)
+		uh.showStagedChanges(status)
+		uh.updateRestartBanner(status)
 	})
 }
 
+// checkRemoteImage looks up the image updates come from in its registry,
+// returning nil when that is not possible: offline, no skopeo, or an image
+// that does not come from a registry
+func checkRemoteImage(ctx context.Context, status *bootc.Status) *bootc.RemoteImage {
+	ref := status.Spec.Image
+	if ref == nil || !network.IsOnline() {
+		return nil
+	}
+	if ref.Transport != "registry" && ref.Transport != "containers-storage" {
+		return nil
+	}
+	remote, err := bootc.CheckRemote(ctx, ref.Image)
+	if err != nil {
+		log.Printf("Error checking %s for updates: %v", ref.Image, err)
+		return nil
+	}
+	return remote
+}
+
+// showUpdateSource replaces the Update Source row with the image updates
+// are pulled from and whether it must be signed. Must run on the main
+// thread.
+func (uh *UserHome) showUpdateSource(ref *bootc.ImageReference, policy *bootc.Policy) {
+	if uh.bootcSourceRow != nil {
+		uh.bootcStageExpander.Remove(&uh.bootcSourceRow.Widget)
+		uh.bootcSourceRow = nil
+	}
+	if ref == nil || ref.Image == "" {
+		return
+	}
+
+	row := adw.NewActionRow()
+	row.SetTitle(ref.Image)
+	styleProvenanceRow(row, ref.Provenance(policy))
+	uh.bootcStageExpander.AddRow(&row.Widget)
+	uh.bootcSourceRow = row
+}
+
+// showStagedChanges replaces the What's New rows with how the staged image
+// differs from the booted one, so the user sees what a restart brings.
+// Shows nothing when no update is staged. Must run on the main thread.
+func (uh *UserHome) showStagedChanges(status *bootc.Status) {
+	expander := uh.bootcStageExpander
+	if uh.bootcChangesExpander != nil {
+		expander.Remove(&uh.bootcChangesExpander.Widget)
+		uh.bootcChangesExpander = nil
+	}
+	if status == nil || status.Status.Staged == nil {
+		return
+	}
+
+	changes := bootc.Diff(status.Status.Booted, status.Status.Staged)
+	if len(changes) == 0 {
+		return
+	}
+
+	changesExpander := adw.NewExpanderRow()
+	changesExpander.SetTitle("What's New")
+	changesExpander.SetSubtitle("Changes from the running system")
+	for _, change := range changes {
+		from, to := change.From, change.To
+		if change.Field == "Digest" {
+			from, to = shortDigest(from), shortDigest(to)
+		}
+		if from == "" {
+			from = "unknown"
+		}
+		if to == "" {
+			to = "unknown"
+		}
+		if change.Field == "Version" {
+			changesExpander.SetSubtitle(fmt.Sprintf("%s → %s", from, to))
+		}
+
+		row := adw.NewActionRow()
+		row.SetTitle(change.Field)
+		row.SetSubtitle(fmt.Sprintf("%s → %s", from, to))
+		changesExpander.AddRow(&row.Widget)
+	}
+	expander.AddRow(&changesExpander.Widget)
+	uh.bootcChangesExpander = changesExpander
+}
+
 // onBootcStageClicked runs the stage script with streamed log output.
 // The script checks, downloads, and stages in one idempotent operation.
 func (uh *UserHome) onBootcStageClicked() {
 	button := uh.bootcStageBtn
 	expander := uh.bootcStageExpander
 
+	if uh.stageRunning {
+		return
+	}
+	uh.stageRunning = true
+	if uh.updateAllBtn != nil {
+		uh.updateAllBtn.SetSensitive(false)
+	}
+
 	button.SetSensitive(false)
 	button.SetLabel("Working...")
 	expander.SetExpanded(true)
@@ -508,6 +807,10 @@ func (uh *UserHome) onBootcStageClicked() {
 	expander.AddRow(&logExpander.Widget)
 	uh.bootcLogExpander = logExpander
 
+	// Staging pulls and deploys a whole image; logging out or suspending
+	// half-way through leaves the user waiting on a retry.
+	inhibitCookie := uh.toastAdder.Inhibit("Staging a system update")
+
 	go func() {
 		ctx, cancel := bootc.DefaultContext()
 		defer cancel()
@@ -569,13 +872,18 @@ func (uh *UserHome) onBootcStageClicked() {
 		uh.updateBadgeCount()
 
 		sgtk.RunOnMainThread(func() {
+			uh.toastAdder.Uninhibit(inhibitCookie)
 			spinner.Stop()
+			uh.stageRunning = false
 			button.SetSensitive(true)
 			button.SetLabel("Check for Updates")
+			if uh.updateAllBtn != nil {
+				uh.updateAllBtn.SetSensitive(true)
+			}
 
 			if stageErr != nil {
 				expander.SetSubtitle(fmt.Sprintf("Update failed: %v", stageErr))
-				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Update failed: %v", stageErr))
+				uh.toastAdder.ShowErrorToast(bootcFailureText("Update failed", stageErr))
 				return
 			}
 
@@ -593,7 +901,12 @@ func (uh *UserHome) onBootcStageClicked() {
 				}
 				expander.SetSubtitle(subtitle)
 			}
+			uh.showStagedChanges(status)
+			uh.updateRestartBanner(status)
 			uh.toastAdder.ShowToast(actionmsg.BootcStage(bootc.IsDryRun(), staged))
+			if staged && !bootc.IsDryRun() {
+				uh.offerRestart(&uh.updatesPrefsPage.Widget, "System Update Staged")
+			}
 		})
 	}()
 }
@@ -601,7 +914,7 @@ func (uh *UserHome) onBootcStageClicked() {
 // onUpdateHomebrewClicked handles the Homebrew update button click
 func (uh *UserHome) onUpdateHomebrewClicked() {
 	go func() {
-		if err := homebrew.Update(); err != nil {
+		if err := homebrew.Update(uh.ctx); err != nil {
 			sgtk.RunOnMainThread(func() {
 				uh.toastAdder.ShowErrorToast(fmt.Sprintf("Update failed: %v", err))
 			})
@@ -612,3 +925,180 @@ func (uh *UserHome) onUpdateHomebrewClicked() {
 		})
 	}()
 }
+
+// updateAllReporter forwards updateall progress to per-step rows on the
+// main thread. Update Everything and manifest import both use it.
+type updateAllReporter struct {
+	rows     []*adw.ActionRow
+	spinners []*gtk.Spinner
+	icons    []*gtk.Image
+}
+
+func (r *updateAllReporter) StepStarted(index int, _ string) {
+	sgtk.RunOnMainThread(func() {
+		r.rows[index].SetSubtitle("Running...")
+		r.icons[index].SetVisible(false)
+		r.spinners[index].SetVisible(true)
+		r.spinners[index].Start()
+	})
+}
+
+func (r *updateAllReporter) StepProgress(index int, line string) {
+	sgtk.RunOnMainThread(func() {
+		r.rows[index].SetSubtitle(line)
+	})
+}
+
+func (r *updateAllReporter) StepFinished(index int, _ string, err error) {
+	sgtk.RunOnMainThread(func() {
+		r.spinners[index].Stop()
+		r.spinners[index].SetVisible(false)
+		r.icons[index].SetVisible(true)
+		if err != nil {
+			r.icons[index].SetFromIconName("dialog-error-symbolic")
+			r.rows[index].SetSubtitle(err.Error())
+			return
+		}
+		r.icons[index].SetFromIconName("object-select-symbolic")
+		r.rows[index].SetSubtitle("Done")
+	})
+}
+
+// addStepRows adds a waiting row per step to expander and returns a
+// reporter that drives them
+func addStepRows(expander *adw.ExpanderRow, steps []updateall.Step) *updateAllReporter {
+	reporter := &updateAllReporter{}
+	for _, step := range steps {
+		row := adw.NewActionRow()
+		row.SetTitle(step.Name)
+		row.SetSubtitle("Waiting")
+		spinner := gtk.NewSpinner()
+		spinner.SetVisible(false)
+		row.AddSuffix(&spinner.Widget)
+		icon := gtk.NewImageFromIconName("content-loading-symbolic")
+		row.AddSuffix(&icon.Widget)
+		expander.AddRow(&row.Widget)
+
+		reporter.rows = append(reporter.rows, row)
+		reporter.spinners = append(reporter.spinners, spinner)
+		reporter.icons = append(reporter.icons, icon)
+	}
+	return reporter
+}
+
+// onUpdateEverythingClicked runs updateall over every source whose Updates
+// page group is enabled and whose tool is present, with one row per step,
+// then refreshes the per-source groups and the badge. While a run is in
+// progress the button cancels it instead.
+func (uh *UserHome) onUpdateEverythingClicked() {
+	button := uh.updateAllBtn
+	expander := uh.updateAllExpander
+
+	if uh.updateAllCancel != nil {
+		// The running command is stopped and the remaining steps skipped.
+		// A system image stage runs as root and finishes regardless.
+		uh.updateAllCancel()
+		button.SetSensitive(false)
+		button.SetLabel("Cancelling...")
+		return
+	}
+
+	// A Check for Updates stage is already running the stage script
+	if uh.stageRunning {
+		return
+	}
+	uh.stageRunning = true
+
+	button.SetSensitive(false)
+	button.SetLabel("Working...")
+	if uh.bootcStageBtn != nil {
+		uh.bootcStageBtn.SetSensitive(false)
+	}
+	expander.SetSubtitle("Checking sources...")
+
+	go func() {
+		steps := updateall.Steps(updateall.Sources{
+			Bootc: uh.config.IsGroupEnabled("updates_page", "bootc_updates_group") &&
+				bootc.IsBootcBootedCached() && bootc.StageScriptAvailable(),
+			Flatpak:  uh.config.IsGroupEnabled("updates_page", "flatpak_updates_group") && flatpak.IsInstalledCached(),
+			Homebrew: uh.config.IsGroupEnabled("updates_page", "brew_updates_group") && homebrew.IsInstalledCached(),
+		})
+
+		sgtk.RunOnMainThread(func() {
+			for _, row := range uh.updateAllRows {
+				expander.Remove(&row.Widget)
+			}
+			uh.updateAllRows = nil
+
+			if len(steps) == 0 {
+				uh.stageRunning = false
+				button.SetSensitive(true)
+				button.SetLabel("Update Everything")
+				if uh.bootcStageBtn != nil {
+					uh.bootcStageBtn.SetSensitive(true)
+				}
+				expander.SetSubtitle("No update sources available")
+				return
+			}
+
+			reporter := addStepRows(expander, steps)
+			uh.updateAllRows = reporter.rows
+			expander.SetEnableExpansion(true)
+			expander.SetExpanded(true)
+			expander.SetSubtitle("Updating...")
+
+			// The run may stage a system image; keep the session up.
+			inhibitCookie := uh.toastAdder.Inhibit("Updating everything")
+
+			ctx, cancel := updateall.Context(uh.ctx)
+			uh.updateAllCancel = cancel
+			button.SetLabel("Cancel")
+			button.RemoveCssClass("suggested-action")
+			button.SetSensitive(true)
+
+			go func() {
+				defer cancel()
+
+				results := updateall.Run(ctx, steps, reporter)
+				failed := updateall.Failed(results)
+				cancelled := errors.Is(ctx.Err(), context.Canceled)
+
+				if uh.bootcUpdatesGroup != nil {
+					go uh.loadBootcUpdateStatus(uh.bootcUpdatesGroup)
+				}
+				if uh.flatpakUpdatesExpander != nil {
+					go uh.loadFlatpakUpdates()
+				}
+				if uh.outdatedExpander != nil {
+					go uh.loadOutdatedPackages()
+				}
+
+				sgtk.RunOnMainThread(func() {
+					uh.updateAllCancel = nil
+					uh.stageRunning = false
+					uh.toastAdder.Uninhibit(inhibitCookie)
+					button.SetSensitive(true)
+					button.SetLabel("Update Everything")
+					button.AddCssClass("suggested-action")
+					if uh.bootcStageBtn != nil {
+						uh.bootcStageBtn.SetSensitive(true)
+					}
+
+					if cancelled {
+						expander.SetSubtitle("Cancelled")
+						uh.toastAdder.ShowToast("Update Everything cancelled")
+						return
+					}
+					if len(failed) > 0 {
+						msg := fmt.Sprintf("%s failed", strings.Join(failed, ", "))
+						expander.SetSubtitle(msg)
+						uh.toastAdder.ShowErrorToast(fmt.Sprintf("Update Everything: %s", msg))
+						return
+					}
+					expander.SetSubtitle(fmt.Sprintf("Finished at %s", time.Now().Format("15:04")))
+					uh.toastAdder.ShowToast(actionmsg.UpdateEverything(homebrew.IsDryRun()))
+				})
+			}()
+		})
+	}()
+}
@@ -2,11 +2,16 @@
 package views
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/frostyard/chairlift/internal/config"
+	"github.com/frostyard/chairlift/internal/flatpak"
+	"github.com/frostyard/chairlift/internal/network"
+	"github.com/frostyard/chairlift/internal/pkgsearch"
+	"github.com/frostyard/chairlift/internal/updex"
 
 	sgtk "github.com/frostyard/snowkit/gtk"
 
@@ -18,7 +23,25 @@ import (
 type ToastAdder interface {
 	ShowToast(message string)
 	ShowErrorToast(message string)
+	ShowToastWithAction(message, buttonLabel string, onAction func())
 	SetUpdateBadge(count int)
+	// SetOffline shows or hides a window-wide banner explaining that
+	// network actions are unavailable
+	SetOffline(offline bool)
+
+	// Inhibit asks the session to block logout and suspend while a
+	// system-level update runs, returning a cookie for Uninhibit. A zero
+	// cookie means the session refused; Uninhibit ignores it.
+	Inhibit(reason string) uint32
+	Uninhibit(cookie uint32)
+
+	// SetRestartBanner shows a window-wide banner with a Restart Now
+	// button and the given title; an empty title hides it
+	SetRestartBanner(title string)
+
+	// Notify posts a desktop notification that opens the Updates page.
+	// A later notification with the same id replaces an earlier one.
+	Notify(id, title, body string)
 }
 
 // UserHome manages all content pages
@@ -26,12 +49,25 @@ type UserHome struct {
 	config     *config.Config
 	toastAdder ToastAdder
 
+	// ctx is passed to every Homebrew and Flatpak call and is cancelled by
+	// Shutdown, so commands still running stop with the window
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Cached package lists shared by the Applications and Updates pages
+	lists *packageLists
+
 	// Pages (ToolbarViews)
 	systemPage       *adw.ToolbarView
 	updatesPage      *adw.ToolbarView
 	applicationsPage *adw.ToolbarView
 	maintenancePage  *adw.ToolbarView
 	featuresPage     *adw.ToolbarView
+	devtoolsPage     *adw.ToolbarView // nil when no developer tool manager is shown
+	containersPage   *adw.ToolbarView // nil when no container engine is shown
+	servicesPage     *adw.ToolbarView // nil when systemctl is missing or both groups are disabled
+	logsPage         *adw.ToolbarView // nil when journalctl is missing or the group is disabled
+	storagePage      *adw.ToolbarView // nil when both groups are disabled
 	helpPage         *adw.ToolbarView
 
 	// PreferencesPages inside each ToolbarView - keep references to prevent GC
@@ -40,33 +76,119 @@ type UserHome struct {
 	applicationsPrefsPage *adw.PreferencesPage
 	maintenancePrefsPage  *adw.PreferencesPage
 	featuresPrefsPage     *adw.PreferencesPage
+	devtoolsPrefsPage     *adw.PreferencesPage
+	containersPrefsPage   *adw.PreferencesPage
+	servicesPrefsPage     *adw.PreferencesPage
+	logsPrefsPage         *adw.PreferencesPage
+	storagePrefsPage      *adw.PreferencesPage
 	helpPrefsPage         *adw.PreferencesPage
 
 	// References for dynamic updates
 	formulaeExpander       *adw.ExpanderRow
 	casksExpander          *adw.ExpanderRow
+	formulaRows            []*adw.ActionRow // Store references for cleanup
+	caskRows               []*adw.ActionRow // Store references for cleanup
+	pinnedFormulae         map[string]bool
+	pinnedFilterBtn        *gtk.ToggleButton
+	brewBundlesGroup       *adw.PreferencesGroup
+	bundleChooseRow        *adw.ActionRow
+	bundleProgressExpander *adw.ExpanderRow
+	bundleProgressRows     []*gtk.Widget // Store references for cleanup
+	bundleInstallBtns      []*gtk.Button
 	outdatedExpander       *adw.ExpanderRow
 	searchResultsExpander  *adw.ExpanderRow
+	caskResultsExpander    *adw.ExpanderRow
+	flatpakResultsExpander *adw.ExpanderRow
 	searchEntry            *gtk.SearchEntry
+	searchCancel           context.CancelFunc // Cancels the search in flight
+	searchSources          []pkgsearch.Source // Sources enabled in config
 	flatpakUserExpander    *adw.ExpanderRow
 	flatpakSystemExpander  *adw.ExpanderRow
 	flatpakUpdatesExpander *adw.ExpanderRow
+	flatpakUserAppRows     []*adw.ActionRow // Store references for cleanup
+	flatpakSystemAppRows   []*adw.ActionRow // Store references for cleanup
+	flatpakRemotesGroup    *adw.PreferencesGroup
+	flatpakRemoteRows      []*adw.ActionRow // Store references for cleanup
 	flatpakUpdateRows      []*adw.ActionRow // Store references for cleanup
 	searchResultRows       []*adw.ActionRow // Store references for cleanup
+	caskResultRows         []*adw.ActionRow // Store references for cleanup
+	flatpakResultRows      []*adw.ActionRow // Store references for cleanup
 	brewTrustGroup         *adw.PreferencesGroup
 	brewTrustRows          map[string]*adw.ActionRow
 	outdatedRows           []*adw.ActionRow // Store references for cleanup
+	outdatedNames          []string         // Selectable outdated packages, in row order
+	outdatedSelection      *batchSelection
+	flatpakUpdateItems     []flatpak.UpdateInfo // Selectable Flatpak updates, in row order
+	flatpakUpdateSelection *batchSelection
+
+	// Update Everything references
+	updateAllExpander *adw.ExpanderRow
+	updateAllBtn      *gtk.Button
+	updateAllRows     []*adw.ActionRow   // Store references for cleanup
+	updateAllCancel   context.CancelFunc // Set while a run is in progress
+	// stageRunning is set while the stage script may run: a Check for
+	// Updates stage, or a whole Update Everything run. Either one keeps
+	// the other from starting. Main thread only.
+	stageRunning bool
+
+	// Manifest import references
+	manifestImportBtn *gtk.Button
+	manifestExpander  *adw.ExpanderRow
+	manifestRows      []*adw.ActionRow // Store references for cleanup
+
+	// bootc status references
+	bootcStatusGroup    *adw.PreferencesGroup
+	bootcStatusExpander *adw.ExpanderRow
+	bootcStatusRows     []*adw.ActionRow // Store references for cleanup
 
 	// bootc update references
-	bootcStageExpander *adw.ExpanderRow
-	bootcStageBtn      *gtk.Button
-	bootcActivityRow   *adw.ActionRow
-	bootcLogExpander   *adw.ExpanderRow
+	bootcUpdatesGroup    *adw.PreferencesGroup
+	bootcStageExpander   *adw.ExpanderRow
+	bootcStageBtn        *gtk.Button
+	bootcActivityRow     *adw.ActionRow
+	bootcLogExpander     *adw.ExpanderRow
+	bootcChangesExpander *adw.ExpanderRow // What's New for a staged update
+	bootcSourceRow       *adw.ActionRow   // Image updates come from
 
 	// Features page references
 	featuresGroup            *adw.PreferencesGroup
 	featuresUnavailableGroup *adw.PreferencesGroup
 	featureRows              map[string]*adw.ActionRow
+	featureChecks            map[string][]updex.CheckResult // Last update check, by feature
+
+	// Developer Tools page references
+	devtoolsGroups []*devtoolsGroup
+
+	// Containers page references
+	containersGroups []*containersGroup
+
+	// Services page references
+	servicesGroups []*servicesGroup
+
+	// Logs page references
+	logs *logsView
+
+	// Storage page references
+	storageDisksGroup     *adw.PreferencesGroup
+	storageDiskRows       []*adw.ActionRow // Store references for cleanup
+	storageConsumersGroup *adw.PreferencesGroup
+	storageConsumerRows   []*adw.ActionRow // Store references for cleanup
+
+	// offlineDisabled holds the widgets disabled when the network went
+	// down, so exactly those are re-enabled when it comes back
+	offlineDisabled []*gtk.Widget
+
+	// Flatpak repair references
+	flatpakRepairBtn      *gtk.Button
+	flatpakRepairExpander *adw.ExpanderRow
+	flatpakRepairRows     []*gtk.Widget // Store references for cleanup
+
+	// restartAt is when a restart was scheduled from ChairLift, zero when
+	// none was. Only touched on the main thread.
+	restartAt time.Time
+
+	// Automatic update check references
+	updateCheckRow *adw.ActionRow
 
 	// Groups with deferred visibility
 	maintenanceBrewGroup    *adw.PreferencesGroup
@@ -76,6 +198,7 @@ type UserHome struct {
 	bootcUpdateCount   int
 	flatpakUpdateCount int
 	brewUpdateCount    int
+	featureUpdateCount int
 	updateCountMu      sync.Mutex
 }
 
@@ -87,6 +210,8 @@ func New(cfg *config.Config, toastAdder ToastAdder) *UserHome {
 		config:     cfg,
 		toastAdder: toastAdder,
 	}
+	uh.ctx, uh.cancel = context.WithCancel(context.Background())
+	uh.lists = newPackageLists()
 
 	// Create pages - createPage returns both ToolbarView and PreferencesPage
 	uh.systemPage, uh.systemPrefsPage = uh.createPage()
@@ -94,6 +219,23 @@ func New(cfg *config.Config, toastAdder ToastAdder) *UserHome {
 	uh.applicationsPage, uh.applicationsPrefsPage = uh.createPage()
 	uh.maintenancePage, uh.maintenancePrefsPage = uh.createPage()
 	uh.featuresPage, uh.featuresPrefsPage = uh.createPage()
+	devtoolsManagers := uh.devtoolsManagers()
+	if len(devtoolsManagers) > 0 {
+		uh.devtoolsPage, uh.devtoolsPrefsPage = uh.createPage()
+	}
+	containerEngines := uh.containerEngines()
+	if len(containerEngines) > 0 {
+		uh.containersPage, uh.containersPrefsPage = uh.createPage()
+	}
+	if uh.hasServicesPage() {
+		uh.servicesPage, uh.servicesPrefsPage = uh.createPage()
+	}
+	if uh.hasLogsPage() {
+		uh.logsPage, uh.logsPrefsPage = uh.createPage()
+	}
+	if uh.hasStoragePage() {
+		uh.storagePage, uh.storagePrefsPage = uh.createPage()
+	}
 	uh.helpPage, uh.helpPrefsPage = uh.createPage()
 
 	// Build page content
@@ -102,17 +244,36 @@ func New(cfg *config.Config, toastAdder ToastAdder) *UserHome {
 	uh.buildApplicationsPage()
 	uh.buildMaintenancePage()
 	uh.buildFeaturesPage()
+	uh.buildDevtoolsPage(devtoolsManagers)
+	uh.buildContainersPage(containerEngines)
+	uh.buildServicesPage()
+	uh.buildLogsPage()
+	uh.buildStoragePage()
 	uh.buildHelpPage()
 
 	log.Printf("views: all pages built in %s", time.Since(start))
 
+	go uh.watchBootcStatus()
+
+	go network.Watch(uh.ctx, network.PollInterval, func(online bool) {
+		sgtk.RunOnMainThread(func() {
+			uh.onNetworkChanged(online)
+		})
+	})
+
 	return uh
 }
 
+// Shutdown cancels the Homebrew and Flatpak commands still running for
+// these pages. The window calls it when it closes.
+func (uh *UserHome) Shutdown() {
+	uh.cancel()
+}
+
 // updateBadgeCount updates the total update count and notifies the window
 func (uh *UserHome) updateBadgeCount() {
 	uh.updateCountMu.Lock()
-	total := uh.bootcUpdateCount + uh.flatpakUpdateCount + uh.brewUpdateCount
+	total := uh.bootcUpdateCount + uh.flatpakUpdateCount + uh.brewUpdateCount + uh.featureUpdateCount
 	uh.updateCountMu.Unlock()
 
 	sgtk.RunOnMainThread(func() {
@@ -120,7 +281,7 @@ func (uh *UserHome) updateBadgeCount() {
 	})
 }
 
-// GetPage returns a page by name
+// GetPage returns a page by name, or nil for a page that is not shown
 func (uh *UserHome) GetPage(name string) *adw.ToolbarView {
 	switch name {
 	case "system":
@@ -133,6 +294,16 @@ func (uh *UserHome) GetPage(name string) *adw.ToolbarView {
 		return uh.maintenancePage
 	case "features":
 		return uh.featuresPage
+	case "devtools":
+		return uh.devtoolsPage
+	case "containers":
+		return uh.containersPage
+	case "services":
+		return uh.servicesPage
+	case "logs":
+		return uh.logsPage
+	case "storage":
+		return uh.storagePage
 	case "help":
 		return uh.helpPage
 	default:
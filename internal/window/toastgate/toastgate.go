@@ -0,0 +1,105 @@
+// Package toastgate decides whether a toast the window is asked to show
+// should be added, dropped as a duplicate, or folded into the error toast
+// already on screen.
+//
+// It is deliberately free of any puregotk/GTK import so its logic can be
+// unit-tested on a headless host. A test binary for a package that imports
+// puregotk panics while resolving GTK/graphene shared libraries at package
+// init — before any test function runs. See
+// docs/agents/skills/gtk-headless-tests.md.
+package toastgate
+
+import (
+	"fmt"
+	"time"
+)
+
+// Verdict is what the window should do with a requested toast.
+type Verdict int
+
+const (
+	// Show means add a new toast.
+	Show Verdict = iota
+	// Duplicate means an identical toast is already visible or queued, so
+	// the request is dropped.
+	Duplicate
+	// Fold means an error arrived within the error interval while the
+	// latest error toast is still open; the window retitles that toast
+	// with FoldedTitle instead of queueing another persistent one.
+	Fold
+)
+
+// Gate tracks which toasts are on screen. It is not safe for concurrent
+// use; the window only calls it from the GTK main thread.
+type Gate struct {
+	errorInterval time.Duration
+
+	active map[string]int
+	// errorMsg is the latest error toast shown, which later errors fold
+	// into; empty once it is dismissed, even if older error toasts are
+	// still open
+	errorMsg  string
+	lastError time.Time
+	folded    int
+}
+
+// New returns a Gate that folds error toasts arriving less than
+// errorInterval after the previous one.
+func New(errorInterval time.Duration) *Gate {
+	return &Gate{
+		errorInterval: errorInterval,
+		active:        make(map[string]int),
+	}
+}
+
+// Admit records a request to show msg at now and returns what to do with
+// it. A Show verdict must be paired with a later Dismissed call for the same
+// msg and isError once the toast goes away.
+func (g *Gate) Admit(msg string, isError bool, now time.Time) Verdict {
+	if g.active[msg] > 0 {
+		return Duplicate
+	}
+
+	if isError && g.errorMsg != "" && now.Sub(g.lastError) < g.errorInterval {
+		g.lastError = now
+		g.folded++
+		return Fold
+	}
+
+	g.active[msg]++
+	if isError {
+		g.errorMsg = msg
+		g.lastError = now
+		g.folded = 0
+	}
+	return Show
+}
+
+// Dismissed records that a toast admitted with Show has gone away.
+func (g *Gate) Dismissed(msg string, isError bool) {
+	if g.active[msg] > 1 {
+		g.active[msg]--
+	} else {
+		delete(g.active, msg)
+	}
+
+	if isError && msg == g.errorMsg {
+		g.errorMsg = ""
+		g.folded = 0
+	}
+}
+
+// Folded returns how many errors have been folded into the open error
+// toast since it was shown.
+func (g *Gate) Folded() int {
+	return g.folded
+}
+
+// FoldedTitle is the title for an error toast that first showed msg and has
+// since absorbed more errors.
+func FoldedTitle(msg string, more int) string {
+	if more == 1 {
+		return fmt.Sprintf("%s (and 1 more error)", msg)
+	}
+	return fmt.Sprintf("%s (and %d more errors)", msg, more)
+}
@@ -0,0 +1,177 @@
+package toastgate
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGateAdmit walks a Gate through sequences of Admit/Dismissed calls and
+// checks each verdict, covering de-duplication of identical messages and the
+// folding of error toasts that arrive within the error interval.
+func TestGateAdmit(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type step struct {
+		msg     string
+		isError bool
+		at      time.Duration // offset from base
+		dismiss bool          // call Dismissed instead of Admit
+		want    Verdict
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "distinct info toasts all show",
+			steps: []step{
+				{msg: "a", want: Show},
+				{msg: "b", want: Show},
+			},
+		},
+		{
+			name: "identical info toast is a duplicate until dismissed",
+			steps: []step{
+				{msg: "a", want: Show},
+				{msg: "a", want: Duplicate},
+				{msg: "a", dismiss: true},
+				{msg: "a", want: Show},
+			},
+		},
+		{
+			name: "identical error toast is a duplicate",
+			steps: []step{
+				{msg: "boom", isError: true, want: Show},
+				{msg: "boom", isError: true, at: 10 * time.Second, want: Duplicate},
+			},
+		},
+		{
+			name: "second error inside interval folds",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "e2", isError: true, at: time.Second, want: Fold},
+				{msg: "e3", isError: true, at: 2 * time.Second, want: Fold},
+			},
+		},
+		{
+			name: "second error after interval shows",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "e2", isError: true, at: 5 * time.Second, want: Show},
+			},
+		},
+		{
+			name: "error after previous error dismissed shows",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "e1", isError: true, dismiss: true},
+				{msg: "e2", isError: true, at: time.Second, want: Show},
+			},
+		},
+		{
+			name: "dismissing an older error keeps folding into the latest",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "e2", isError: true, at: 5 * time.Second, want: Show},
+				{msg: "e1", isError: true, dismiss: true},
+				{msg: "e3", isError: true, at: 6 * time.Second, want: Fold},
+			},
+		},
+		{
+			name: "dismissing the latest error ends folding",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "e2", isError: true, at: 5 * time.Second, want: Show},
+				{msg: "e2", isError: true, dismiss: true},
+				{msg: "e3", isError: true, at: 6 * time.Second, want: Show},
+			},
+		},
+		{
+			name: "info toast never folds into an open error",
+			steps: []step{
+				{msg: "e1", isError: true, want: Show},
+				{msg: "done", at: time.Second, want: Show},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(3 * time.Second)
+			for i, s := range tt.steps {
+				if s.dismiss {
+					g.Dismissed(s.msg, s.isError)
+					continue
+				}
+				if got := g.Admit(s.msg, s.isError, base.Add(s.at)); got != s.want {
+					t.Fatalf("step %d: Admit(%q, %v) = %v, want %v", i, s.msg, s.isError, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+// TestGateFoldedCount checks that Folded counts errors absorbed into the
+// open error toast and resets when that toast is dismissed.
+func TestGateFoldedCount(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := New(3 * time.Second)
+
+	g.Admit("e1", true, base)
+	g.Admit("e2", true, base.Add(time.Second))
+	g.Admit("e3", true, base.Add(2*time.Second))
+	if got := g.Folded(); got != 2 {
+		t.Fatalf("Folded() = %d, want 2", got)
+	}
+
+	g.Dismissed("e1", true)
+	if got := g.Folded(); got != 0 {
+		t.Fatalf("Folded() after dismiss = %d, want 0", got)
+	}
+}
+
+// TestGateFoldedCountOverlapping checks that with two error toasts open,
+// dismissing the older one leaves the latest one's folded count alone.
+func TestGateFoldedCountOverlapping(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := New(3 * time.Second)
+
+	g.Admit("e1", true, base)
+	g.Admit("e2", true, base.Add(5*time.Second))
+	g.Admit("e3", true, base.Add(6*time.Second))
+	if got := g.Folded(); got != 1 {
+		t.Fatalf("Folded() = %d, want 1", got)
+	}
+
+	g.Dismissed("e1", true)
+	if got := g.Folded(); got != 1 {
+		t.Fatalf("Folded() after dismissing the older error = %d, want 1", got)
+	}
+	if got := g.Admit("e4", true, base.Add(7*time.Second)); got != Fold {
+		t.Fatalf("Admit(e4) = %v, want Fold", got)
+	}
+	if got := g.Folded(); got != 2 {
+		t.Fatalf("Folded() = %d, want 2", got)
+	}
+
+	g.Dismissed("e2", true)
+	if got := g.Folded(); got != 0 {
+		t.Fatalf("Folded() after dismissing the latest error = %d, want 0", got)
+	}
+}
+
+func TestFoldedTitle(t *testing.T) {
+	tests := []struct {
+		more int
+		want string
+	}{
+		{1, "Update failed (and 1 more error)"},
+		{3, "Update failed (and 3 more errors)"},
+	}
+	for _, tt := range tests {
+		if got := FoldedTitle("Update failed", tt.more); got != tt.want {
+			t.Errorf("FoldedTitle(%d) = %q, want %q", tt.more, got, tt.want)
+		}
+	}
+}
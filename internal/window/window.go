@@ -10,6 +10,7 @@ import (
 	"github.com/frostyard/chairlift/internal/config"
 	"github.com/frostyard/chairlift/internal/version"
 	"github.com/frostyard/chairlift/internal/views"
+	"github.com/frostyard/chairlift/internal/window/toastgate"
 
 	"github.com/frostyard/snowkit/gobj"
 
@@ -39,6 +40,22 @@ type Window struct {
 	config      *config.Config
 	views       *views.UserHome
 	updateBadge *gtk.Button // Badge for updates count
+
+	offlineBanner *adw.Banner // Revealed while the network is down
+	restartBanner *adw.Banner // Revealed while a system update waits for a restart
+
+	// toastGate de-duplicates toasts and folds bursts of errors into the
+	// error toast already on screen (errorToast, first shown with
+	// errorToastMsg).
+	toastGate     *toastgate.Gate
+	errorToast    *adw.Toast
+	errorToastMsg string
+
+	// busyCount is the number of running system updates that hold a
+	// session inhibitor; closing the window while it is non-zero asks
+	// for confirmation first. Only touched on the main thread.
+	busyCount  int
+	forceClose bool
 }
 
 // NavItem represents a navigation item in the sidebar
@@ -48,6 +65,11 @@ type NavItem struct {
 	Icon  string
 }
 
+// errorToastInterval is how soon after an error toast a further error is
+// folded into it rather than queued behind it; error toasts persist until
+// dismissed, so a burst would otherwise need one click per failure.
+const errorToastInterval = 3 * time.Second
+
 // navItems defines the sidebar navigation structure
 var navItems = []NavItem{
 	{Name: "applications", Title: "Applications", Icon: "application-x-executable-symbolic"},
@@ -55,6 +77,11 @@ var navItems = []NavItem{
 	{Name: "updates", Title: "Updates", Icon: "software-update-available-symbolic"},
 	{Name: "system", Title: "System", Icon: "computer-symbolic"},
 	{Name: "features", Title: "Features", Icon: "application-x-addon-symbolic"},
+	{Name: "devtools", Title: "Developer Tools", Icon: "utilities-terminal-symbolic"},
+	{Name: "containers", Title: "Containers", Icon: "package-x-generic-symbolic"},
+	{Name: "services", Title: "Services", Icon: "system-run-symbolic"},
+	{Name: "logs", Title: "Logs", Icon: "text-x-generic-symbolic"},
+	{Name: "storage", Title: "Storage", Icon: "drive-harddisk-symbolic"},
 	{Name: "help", Title: "Help", Icon: "help-browser-symbolic"},
 }
 
@@ -82,6 +109,7 @@ func init() {
 					pages:             make(map[string]*adw.ToolbarView),
 					navRows:           make(map[string]*adw.ActionRow),
 					config:            cfg,
+					toastGate:         toastgate.New(errorToastInterval),
 				}
 
 				reg.Pin(o, unsafe.Pointer(w))
@@ -90,6 +118,7 @@ func init() {
 				w.SetTitle("ChairLift")
 				w.buildUI()
 				w.setupActions()
+				w.setupCloseGuard()
 
 				log.Printf("window: constructed in %s", time.Since(windowStart))
 			})
@@ -125,9 +154,33 @@ func (w *Window) buildUI() {
 	contentPage := w.buildContentArea()
 	w.splitView.SetContent(contentPage)
 
+	// Offline banner above both panes, hidden until the views report
+	// that the network is down
+	w.offlineBanner = adw.NewBanner("You are offline. Searching, installing and updating are unavailable.")
+	w.offlineBanner.SetButtonLabel("Retry")
+	retryCb := func(_ adw.Banner) {
+		w.views.CheckNetwork()
+	}
+	w.offlineBanner.ConnectButtonClicked(&retryCb)
+
+	// Restart banner, revealed while a staged system update waits for
+	// the next boot
+	w.restartBanner = adw.NewBanner("")
+	w.restartBanner.SetButtonLabel("Restart Now")
+	restartCb := func(_ adw.Banner) {
+		w.views.RestartNow()
+	}
+	w.restartBanner.ConnectButtonClicked(&restartCb)
+
+	box := gtk.NewBox(gtk.OrientationVerticalValue, 0)
+	box.Append(&w.offlineBanner.Widget)
+	box.Append(&w.restartBanner.Widget)
+	w.splitView.SetVexpand(true)
+	box.Append(&w.splitView.Widget)
+
 	// Create toast overlay for notifications
 	w.toasts = adw.NewToastOverlay()
-	w.toasts.SetChild(&w.splitView.Widget)
+	w.toasts.SetChild(&box.Widget)
 
 	// Set window content
 	w.SetContent(&w.toasts.Widget)
@@ -158,8 +211,12 @@ func (w *Window) buildSidebar() *adw.NavigationPage {
 	w.sidebarList.SetSelectionMode(gtk.SelectionSingleValue)
 	w.sidebarList.AddCssClass("navigation-sidebar")
 
-	// Add navigation items
+	// Add navigation items, skipping pages the views left out (the
+	// Developer Tools page only exists when a tool manager is installed)
 	for _, item := range navItems {
+		if w.views.GetPage(item.Name) == nil {
+			continue
+		}
 		row := w.createNavRow(item)
 		w.sidebarList.Append(&row.Widget)
 	}
@@ -326,12 +383,11 @@ func (w *Window) navigateToPage(pageName string) {
 		w.contentStack.SetVisibleChildName(pageName)
 
 		// Select the corresponding row and update title
-		for i, item := range navItems {
+		if row, ok := w.navRows[pageName]; ok {
+			w.sidebarList.SelectRow(&row.ListBoxRow)
+		}
+		for _, item := range navItems {
 			if item.Name == pageName {
-				row := w.sidebarList.GetRowAtIndex(int32(i))
-				if row != nil {
-					w.sidebarList.SelectRow(row)
-				}
 				w.contentPage.SetTitle(item.Title)
 				break
 			}
@@ -385,6 +441,10 @@ func (w *Window) onShowShortcuts() {
 		{"Alt+4", "Go to System"},
 		{"Alt+5", "Go to Features"},
 		{"Alt+6", "Go to Help"},
+		{"Alt+7", "Go to Developer Tools"},
+		{"Alt+8", "Go to Services"},
+		{"Alt+9", "Go to Logs"},
+		{"Alt+0", "Go to Storage"},
 	}
 
 	for _, s := range navShortcuts {
@@ -457,15 +517,61 @@ func (w *Window) AddToast(toast *adw.Toast) {
 
 // ShowToast shows a simple toast message
 func (w *Window) ShowToast(message string) {
-	toast := adw.NewToast(message)
-	toast.SetTimeout(3)
-	w.AddToast(toast)
+	w.showGatedToast(message, false, "", nil)
 }
 
 // ShowErrorToast shows an error toast
 func (w *Window) ShowErrorToast(message string) {
+	w.showGatedToast(message, true, "", nil)
+}
+
+// ShowToastWithAction shows a toast with a button that calls onAction
+func (w *Window) ShowToastWithAction(message, buttonLabel string, onAction func()) {
+	w.showGatedToast(message, false, buttonLabel, onAction)
+}
+
+// showGatedToast adds a toast unless an identical one is already showing.
+// Errors arriving in a burst retitle the open error toast instead of
+// stacking up; every folded message is still logged.
+func (w *Window) showGatedToast(message string, isError bool, buttonLabel string, onAction func()) {
+	switch w.toastGate.Admit(message, isError, time.Now()) {
+	case toastgate.Duplicate:
+		return
+	case toastgate.Fold:
+		log.Printf("Error: %s", message)
+		if w.errorToast != nil {
+			w.errorToast.SetTitle(toastgate.FoldedTitle(w.errorToastMsg, w.toastGate.Folded()))
+		}
+		return
+	}
+
 	toast := adw.NewToast(message)
-	toast.SetTimeout(0) // Persist until dismissed
+	if isError {
+		log.Printf("Error: %s", message)
+		toast.SetTimeout(0) // Persist until dismissed
+		w.errorToast = toast
+		w.errorToastMsg = message
+	} else {
+		toast.SetTimeout(3)
+	}
+
+	if buttonLabel != "" && onAction != nil {
+		toast.SetButtonLabel(buttonLabel)
+		buttonClickedCb := func(_ adw.Toast) {
+			onAction()
+		}
+		toast.ConnectButtonClicked(&buttonClickedCb)
+	}
+
+	dismissedCb := func(_ adw.Toast) {
+		w.toastGate.Dismissed(message, isError)
+		if isError && w.errorToastMsg == message {
+			w.errorToast = nil
+			w.errorToastMsg = ""
+		}
+	}
+	toast.ConnectDismissed(&dismissedCb)
+
 	w.AddToast(toast)
 }
 
@@ -482,3 +588,100 @@ func (w *Window) SetUpdateBadge(count int) {
 		w.updateBadge.SetVisible(false)
 	}
 }
+
+// SetOffline reveals or hides the offline banner
+func (w *Window) SetOffline(offline bool) {
+	if w.offlineBanner == nil {
+		return
+	}
+	w.offlineBanner.SetRevealed(offline)
+}
+
+// SetRestartBanner reveals the restart banner with title, or hides it
+// when title is empty
+func (w *Window) SetRestartBanner(title string) {
+	if w.restartBanner == nil {
+		return
+	}
+	if title != "" {
+		w.restartBanner.SetTitle(title)
+	}
+	w.restartBanner.SetRevealed(title != "")
+}
+
+// Notify posts a desktop notification whose default action opens the
+// Updates page
+func (w *Window) Notify(id, title, body string) {
+	app := w.GetApplication()
+	if app == nil {
+		return
+	}
+	notification := gio.NewNotification(title)
+	notification.SetBody(body)
+	notification.SetDefaultAction("app.show-updates")
+	app.SendNotification(id, notification)
+}
+
+// ShowPage brings the window forward on the named page
+func (w *Window) ShowPage(pageName string) {
+	w.navigateToPage(pageName)
+	w.Present()
+}
+
+// Inhibit blocks logout and suspend for reason until Uninhibit is called
+// with the returned cookie, and marks the window busy so closing it asks
+// for confirmation.
+func (w *Window) Inhibit(reason string) uint32 {
+	w.busyCount++
+
+	app := w.GetApplication()
+	if app == nil {
+		return 0
+	}
+	return app.Inhibit(&w.Window, gtk.ApplicationInhibitLogoutValue|gtk.ApplicationInhibitSuspendValue, reason)
+}
+
+// Uninhibit releases an inhibitor taken by Inhibit
+func (w *Window) Uninhibit(cookie uint32) {
+	if w.busyCount > 0 {
+		w.busyCount--
+	}
+
+	if cookie == 0 {
+		return
+	}
+	if app := w.GetApplication(); app != nil {
+		app.Uninhibit(cookie)
+	}
+}
+
+// setupCloseGuard asks for confirmation before closing the window while a
+// system update holds an inhibitor, since quitting abandons its progress.
+func (w *Window) setupCloseGuard() {
+	closeRequestCb := func(_ gtk.Window) bool {
+		if w.busyCount == 0 || w.forceClose {
+			w.views.Shutdown()
+			return false
+		}
+
+		dialog := adw.NewAlertDialog(
+			"Quit While Updating?",
+			"A system update is still running. Quitting now stops it before it finishes.",
+		)
+		dialog.AddResponse("cancel", "Keep Running")
+		dialog.AddResponse("quit", "Quit")
+		dialog.SetResponseAppearance("quit", adw.ResponseDestructiveValue)
+
+		responseCb := func(_ adw.AlertDialog, response string) {
+			if response != "quit" {
+				return
+			}
+			w.forceClose = true
+			w.Close()
+		}
+		dialog.ConnectResponse(&responseCb)
+		dialog.Present(&w.Widget)
+		return true
+	}
+	w.ConnectCloseRequest(&closeRequestCb)
+}